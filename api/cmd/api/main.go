@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+	_ "time/tzdata" // user timezones must resolve even without a system zone database
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v5"
@@ -13,15 +14,28 @@ import (
 
 	dbgen "github.com/ethanwang/devpulse/api/db/generated"
 	"github.com/ethanwang/devpulse/api/internal/activity"
+	"github.com/ethanwang/devpulse/api/internal/apikey"
 	"github.com/ethanwang/devpulse/api/internal/apperror"
 	"github.com/ethanwang/devpulse/api/internal/auth"
+	"github.com/ethanwang/devpulse/api/internal/backfill"
+	"github.com/ethanwang/devpulse/api/internal/bitbucket"
 	"github.com/ethanwang/devpulse/api/internal/config"
 	"github.com/ethanwang/devpulse/api/internal/datasource"
+	"github.com/ethanwang/devpulse/api/internal/gitea"
 	"github.com/ethanwang/devpulse/api/internal/github"
+	"github.com/ethanwang/devpulse/api/internal/gitscan"
+	"github.com/ethanwang/devpulse/api/internal/gitlab"
+	"github.com/ethanwang/devpulse/api/internal/heartbeat"
+	"github.com/ethanwang/devpulse/api/internal/metrics"
 	mw "github.com/ethanwang/devpulse/api/internal/middleware"
 	"github.com/ethanwang/devpulse/api/internal/oauth"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+	"github.com/ethanwang/devpulse/api/internal/pullrequest"
 	riversetup "github.com/ethanwang/devpulse/api/internal/river"
 	"github.com/ethanwang/devpulse/api/internal/summary"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
+	"github.com/ethanwang/devpulse/api/internal/wakatime"
+	"github.com/ethanwang/devpulse/api/internal/webhook"
 )
 
 func main() {
@@ -44,35 +58,135 @@ func main() {
 	// Dependency injection
 	queries := dbgen.New(pool)
 
-	// GitHub client
-	ghClient := github.NewClient(nil)
+	keyring, err := tokencrypt.ParseKeyring(cfg.TokenEncryptionKeys, cfg.TokenPrimaryKeyID)
+	if err != nil {
+		slog.Error("failed to load token encryption keys", "error", err)
+		return
+	}
+
+	// API clients
+	ghHosts := github.Hosts{github.ProviderName: github.NewClient(nil, cfg.GitHubAPIURL)}
+	wtClient := wakatime.NewClient(nil)
+
+	// Data source providers
+	enabled := []provider.Provider{
+		github.NewProvider(github.ProviderName, cfg.GitHubWebURL, ghHosts[github.ProviderName], provider.Credentials{
+			ClientID:     cfg.GitHubClientID,
+			ClientSecret: cfg.GitHubClientSecret,
+			CallbackURL:  cfg.GitHubCallbackURL,
+		}),
+		wakatime.NewProvider(wtClient, provider.Credentials{
+			ClientID:     cfg.WakaTimeClientID,
+			ClientSecret: cfg.WakaTimeClientSecret,
+			CallbackURL:  cfg.WakaTimeCallbackURL,
+		}),
+	}
+	// Optional providers are enabled by configuring their OAuth app
+	if cfg.GitHubEnterpriseURL != "" {
+		ghHosts[github.EnterpriseProviderName] = github.NewClient(nil, cfg.GitHubEnterpriseAPIURL)
+		enabled = append(enabled, github.NewProvider(github.EnterpriseProviderName, cfg.GitHubEnterpriseURL, ghHosts[github.EnterpriseProviderName], provider.Credentials{
+			ClientID:     cfg.GitHubEnterpriseClientID,
+			ClientSecret: cfg.GitHubEnterpriseClientSecret,
+			CallbackURL:  cfg.GitHubEnterpriseCallbackURL,
+		}))
+	}
+	if cfg.GitLabClientID != "" {
+		enabled = append(enabled, gitlab.NewProvider(gitlab.NewClient(nil, cfg.GitLabBaseURL), provider.Credentials{
+			ClientID:     cfg.GitLabClientID,
+			ClientSecret: cfg.GitLabClientSecret,
+			CallbackURL:  cfg.GitLabCallbackURL,
+		}))
+	}
+	if cfg.GiteaBaseURL != "" {
+		enabled = append(enabled, gitea.NewProvider(gitea.NewClient(nil, cfg.GiteaBaseURL), provider.Credentials{
+			ClientID:     cfg.GiteaClientID,
+			ClientSecret: cfg.GiteaClientSecret,
+			CallbackURL:  cfg.GiteaCallbackURL,
+		}))
+	}
+	if cfg.BitbucketClientID != "" {
+		enabled = append(enabled, bitbucket.NewProvider(bitbucket.NewClient(nil), provider.Credentials{
+			ClientID:     cfg.BitbucketClientID,
+			ClientSecret: cfg.BitbucketClientSecret,
+			CallbackURL:  cfg.BitbucketCallbackURL,
+		}))
+	}
+	providers := provider.NewRegistry(enabled...)
 
 	// River workers
 	workers := riverlib.NewWorkers()
-	ghSyncWorker := github.NewSyncWorker(queries, ghClient)
-	riverlib.AddWorker(workers, ghSyncWorker)
+	syncWorker := provider.NewSyncWorker(queries, providers)
+	riverlib.AddWorker(workers, syncWorker)
+
+	syncUserWorker := provider.NewSyncUserWorker(queries, providers, keyring)
+	riverlib.AddWorker(workers, syncUserWorker)
+
+	ghHistoryWorker := github.NewHistoryImportWorker(queries, ghHosts, keyring)
+	riverlib.AddWorker(workers, ghHistoryWorker)
+
+	ghEnrichWorker := github.NewEnrichCommitsWorker(queries, ghHosts, keyring)
+	riverlib.AddWorker(workers, ghEnrichWorker)
+
+	ghReviewWorker := github.NewEnrichReviewsWorker(queries, ghHosts, keyring)
+	riverlib.AddWorker(workers, ghReviewWorker)
+
+	ghPullRequestWorker := github.NewPullRequestDetailsWorker(queries, ghHosts, keyring)
+	riverlib.AddWorker(workers, ghPullRequestWorker)
+
+	prProjectWorker := pullrequest.NewProjectWorker(queries)
+	riverlib.AddWorker(workers, prProjectWorker)
 
 	aggWorker := summary.NewAggregateWorker(queries)
 	riverlib.AddWorker(workers, aggWorker)
 
+	langResolver := github.NewLanguageResolver(queries, ghHosts, keyring)
+	aggregator := summary.NewAggregator(queries, cfg.ExcludeMergeCommits, langResolver)
+	aggUserWorker := summary.NewAggregateUserWorker(queries, aggregator)
+	riverlib.AddWorker(workers, aggUserWorker)
+
+	reaggWorker := summary.NewReaggregateWorker(queries, aggregator)
+	riverlib.AddWorker(workers, reaggWorker)
+
+	backfillWorker := backfill.NewWorker(queries, aggregator)
+	riverlib.AddWorker(workers, backfillWorker)
+
+	durationsWorker := heartbeat.NewDurationsWorker(queries)
+	riverlib.AddWorker(workers, durationsWorker)
+
+	rekeyWorker := tokencrypt.NewRekeyWorker(queries, keyring)
+	riverlib.AddWorker(workers, rekeyWorker)
+
 	// River periodic jobs
 	periodicJobs := []*riverlib.PeriodicJob{
 		riverlib.NewPeriodicJob(
 			riverlib.PeriodicInterval(1*time.Hour),
 			func() (riverlib.JobArgs, *riverlib.InsertOpts) {
-				return github.SyncArgs{}, nil
+				return provider.SyncArgs{}, nil
 			},
 			&riverlib.PeriodicJobOpts{RunOnStart: true},
 		),
 		riverlib.NewPeriodicJob(
-			riverlib.PeriodicInterval(24*time.Hour),
+			riverlib.PeriodicInterval(1*time.Hour),
 			func() (riverlib.JobArgs, *riverlib.InsertOpts) {
 				return summary.AggregateArgs{}, nil
 			},
-			nil, // Don't run on start — aggregation is for yesterday's data
+			// Hourly so each user is picked up soon after their local midnight
+			&riverlib.PeriodicJobOpts{RunOnStart: true},
 		),
 	}
 
+	// Local repository scanning is opt-in for self-hosted deployments
+	if len(cfg.GitScanRepos) > 0 {
+		riverlib.AddWorker(workers, gitscan.NewScanWorker(queries, cfg.GitScanRepos))
+		periodicJobs = append(periodicJobs, riverlib.NewPeriodicJob(
+			riverlib.PeriodicInterval(1*time.Hour),
+			func() (riverlib.JobArgs, *riverlib.InsertOpts) {
+				return gitscan.ScanArgs{}, nil
+			},
+			&riverlib.PeriodicJobOpts{RunOnStart: true},
+		))
+	}
+
 	// Create and start River client
 	riverClient, err := riversetup.NewClient(pool, workers, periodicJobs)
 	if err != nil {
@@ -86,16 +200,26 @@ func main() {
 	defer riverClient.Stop(context.Background()) //nolint:errcheck
 	slog.Info("river started")
 
+	// Re-encrypt stored tokens once per primary key (unique by args)
+	if _, err := riverClient.Insert(context.Background(), tokencrypt.RekeyArgs{PrimaryKeyID: keyring.PrimaryID()}, nil); err != nil {
+		slog.Error("failed to enqueue token rekey", "error", err)
+	}
+
 	authSvc := auth.NewService(queries, cfg.JWTSecret)
 	authHandler := auth.NewHandler(authSvc)
 
-	oauthSvc := oauth.NewService(queries, oauth.GitHubConfig{
-		ClientID:     cfg.GitHubClientID,
-		ClientSecret: cfg.GitHubClientSecret,
-		CallbackURL:  cfg.GitHubCallbackURL,
-	})
+	oauthSvc := oauth.NewService(queries, keyring, cfg.JWTSecret, providers)
 	oauthHandler := oauth.NewHandler(oauthSvc)
 
+	webhookSvc := webhook.NewService(queries, riverClient)
+	webhookHandler := webhook.NewHandler(webhookSvc)
+
+	apiKeySvc := apikey.NewService(queries)
+	apiKeyHandler := apikey.NewHandler(apiKeySvc)
+
+	heartbeatSvc := heartbeat.NewService(queries, riverClient)
+	heartbeatHandler := heartbeat.NewHandler(heartbeatSvc)
+
 	// Echo
 	e := echo.New()
 	e.Use(middleware.RequestLogger())
@@ -108,24 +232,43 @@ func main() {
 
 	api := e.Group("/api")
 	authHandler.RegisterPublicRoutes(api)
+	webhookHandler.RegisterPublicRoutes(api)
 
 	protected := api.Group("")
 	protected.Use(mw.JWTAuth(cfg.JWTSecret))
 	authHandler.RegisterProtectedRoutes(protected)
 	oauthHandler.RegisterRoutes(protected)
+	webhookHandler.RegisterProtectedRoutes(protected)
+	apiKeyHandler.RegisterRoutes(protected)
 
-	activitySvc := activity.NewService(queries)
+	// WakaTime-compatible API: editor plugins use <host>/api/v1 as api_url
+	wakaAPI := api.Group("/v1", mw.APIKeyAuth(apiKeySvc))
+	heartbeatHandler.RegisterRoutes(wakaAPI)
+
+	activitySvc := activity.NewService(queries, riverClient, providers.ActivityTypes())
 	activityHandler := activity.NewHandler(activitySvc)
 	activityHandler.RegisterRoutes(protected)
 
-	summarySvc := summary.NewService(queries)
+	ingest := api.Group("", mw.JWTOrAPIKeyAuth(cfg.JWTSecret, apiKeySvc))
+	activityHandler.RegisterIngestRoutes(ingest)
+
+	summarySvc := summary.NewService(queries, aggregator)
 	summaryHandler := summary.NewHandler(summarySvc)
 	summaryHandler.RegisterRoutes(protected)
 
-	dsSvc := datasource.NewService(queries)
+	metricsSvc := metrics.NewService(queries)
+	metricsHandler := metrics.NewHandler(metricsSvc)
+	metricsHandler.RegisterRoutes(protected)
+
+	dsSvc := datasource.NewService(queries, keyring, providers)
 	dsHandler := datasource.NewHandler(dsSvc)
 	dsHandler.RegisterRoutes(protected)
 
+	admin := protected.Group("/admin", mw.RequireAdmin(cfg.AdminUserIDs))
+	backfillSvc := backfill.NewService(queries, riverClient)
+	backfillHandler := backfill.NewHandler(backfillSvc)
+	backfillHandler.RegisterRoutes(admin)
+
 	slog.Info("starting server", "port", cfg.Port)
 	if err := e.Start(":" + cfg.Port); err != nil {
 		slog.Error("server stopped", "error", err)
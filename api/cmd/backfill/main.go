@@ -0,0 +1,89 @@
+// Command backfill rebuilds a user's daily summaries for a date range.
+//
+// It only enqueues the summary_backfill job; the API server's workers run
+// it. With -wait it polls progress until the backfill finishes.
+//
+//	go run ./cmd/backfill -user 42 -from 2025-01-01 -to 2025-12-31 -wait
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/backfill"
+	"github.com/ethanwang/devpulse/api/internal/config"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
+)
+
+func main() {
+	userID := flag.Int64("user", 0, "user ID to backfill")
+	from := flag.String("from", "", "first day, YYYY-MM-DD")
+	to := flag.String("to", "", "last day, YYYY-MM-DD (default: yesterday)")
+	wait := flag.Bool("wait", false, "poll progress until the backfill finishes")
+	flag.Parse()
+
+	if err := run(*userID, *from, *to, *wait); err != nil {
+		fmt.Fprintln(os.Stderr, "backfill:", err)
+		os.Exit(1)
+	}
+}
+
+func run(userID int64, from, to string, wait bool) error {
+	if userID == 0 || from == "" {
+		flag.Usage()
+		return fmt.Errorf("-user and -from are required")
+	}
+	if to == "" {
+		to = time.Now().AddDate(0, 0, -1).Format(time.DateOnly)
+	}
+	start, end, err := backfill.ParseRange(from, to)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cfg := config.Load()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer pool.Close()
+
+	queries := dbgen.New(pool)
+	riverClient, err := riversetup.NewInsertOnlyClient(pool)
+	if err != nil {
+		return fmt.Errorf("create river client: %w", err)
+	}
+
+	row, err := backfill.Enqueue(ctx, queries, riverClient, userID, start, end)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("backfill %d enqueued: user %d, %s..%s (%d days)\n",
+		row.ID, userID, start.Format(time.DateOnly), end.Format(time.DateOnly), row.TotalDays)
+
+	if !wait {
+		return nil
+	}
+	for {
+		time.Sleep(2 * time.Second)
+		bf, err := queries.GetSummaryBackfill(ctx, row.ID)
+		if err != nil {
+			return fmt.Errorf("get progress: %w", err)
+		}
+		fmt.Printf("%s: %d/%d days\n", bf.Status, bf.DoneDays, bf.TotalDays)
+		switch bf.Status {
+		case backfill.StatusCompleted:
+			return nil
+		case backfill.StatusFailed:
+			return fmt.Errorf("backfill failed: %s", bf.Error.String)
+		}
+	}
+}
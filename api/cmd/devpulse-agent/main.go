@@ -0,0 +1,201 @@
+// Command devpulse-agent reports commits from local repositories to
+// DevPulse, including ones that never reach a connected forge.
+//
+// Configure it once with an API key, then install its hooks per repository:
+//
+//	devpulse-agent configure -url https://devpulse.example.com -key <api key>
+//	devpulse-agent install ~/src/project
+//
+// The post-commit hook queues each commit; uploads happen in the
+// background and on pre-push. Commits made offline stay queued until an
+// upload succeeds.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethanwang/devpulse/api/internal/gitagent"
+)
+
+const usage = `usage: devpulse-agent <command> [flags]
+
+commands:
+  configure -url URL -key KEY   save the server URL and API key
+  install [-force] [dir]        install git hooks in the repository at dir
+  record [-quiet] [rev]         queue a commit (default HEAD) and upload
+  flush [-quiet]                upload queued commits
+  status                        show the configuration and queue length
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "configure":
+		err = configure(args)
+	case "install":
+		err = install(args)
+	case "record":
+		err = record(args)
+	case "flush":
+		err = flush(args)
+	case "status":
+		err = status()
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "devpulse-agent:", err)
+		os.Exit(1)
+	}
+}
+
+func configure(args []string) error {
+	fs := flag.NewFlagSet("configure", flag.ExitOnError)
+	url := fs.String("url", "", "DevPulse server URL")
+	key := fs.String("key", "", "DevPulse API key")
+	fs.Parse(args) //nolint:errcheck // ExitOnError exits on failure
+
+	if *url == "" || *key == "" {
+		return errors.New("-url and -key are required")
+	}
+	dir, err := gitagent.Dir()
+	if err != nil {
+		return err
+	}
+	if err := gitagent.SaveConfig(dir, gitagent.Config{URL: *url, APIKey: *key}); err != nil {
+		return err
+	}
+	fmt.Println("saved", filepath.Join(dir, "agent.json"))
+	return nil
+}
+
+func install(args []string) error {
+	fs := flag.NewFlagSet("install", flag.ExitOnError)
+	force := fs.Bool("force", false, "replace existing hooks not written by the agent")
+	fs.Parse(args) //nolint:errcheck // ExitOnError exits on failure
+
+	repo := "."
+	if fs.NArg() > 0 {
+		repo = fs.Arg(0)
+	}
+	agent, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	installed, err := gitagent.Install(context.Background(), repo, agent, *force)
+	for _, path := range installed {
+		fmt.Println("installed", path)
+	}
+	if errors.Is(err, gitagent.ErrHookExists) {
+		return fmt.Errorf("%w; rerun with -force to replace it", err)
+	}
+	return err
+}
+
+func record(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	quiet := fs.Bool("quiet", false, "only report errors")
+	fs.Parse(args) //nolint:errcheck // ExitOnError exits on failure
+
+	rev := "HEAD"
+	if fs.NArg() > 0 {
+		rev = fs.Arg(0)
+	}
+	dir, err := gitagent.Dir()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	commit, err := gitagent.ReadCommit(ctx, ".", rev)
+	if err != nil {
+		return err
+	}
+	if err := gitagent.NewQueue(dir).Append(gitagent.CommitActivity(*commit)); err != nil {
+		return fmt.Errorf("queue commit: %w", err)
+	}
+	if !*quiet {
+		fmt.Printf("queued %s %s\n", commit.SHA[:min(12, len(commit.SHA))], commit.Repo)
+	}
+	return nil
+}
+
+func flush(args []string) error {
+	fs := flag.NewFlagSet("flush", flag.ExitOnError)
+	quiet := fs.Bool("quiet", false, "only report errors")
+	fs.Parse(args) //nolint:errcheck // ExitOnError exits on failure
+
+	dir, err := gitagent.Dir()
+	if err != nil {
+		return err
+	}
+	cfg, err := gitagent.LoadConfig(dir)
+	if err != nil {
+		return err
+	}
+	if cfg.URL == "" || cfg.APIKey == "" {
+		return errors.New("not configured; run devpulse-agent configure")
+	}
+
+	client := gitagent.NewClient(nil, cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var result *gitagent.UploadResult
+	n, err := gitagent.NewQueue(dir).Flush(ctx, func(ctx context.Context, acts []gitagent.Activity) error {
+		var err error
+		result, err = client.Upload(ctx, acts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return nil
+	}
+
+	for _, r := range result.Rejected {
+		fmt.Fprintf(os.Stderr, "devpulse-agent: dropped %s: %s\n", r.Activity.ExternalID, r.Error)
+	}
+	if !*quiet {
+		fmt.Printf("uploaded %d commits (%d new, %d already known)\n", n, result.Created, result.Duplicates)
+	}
+	return nil
+}
+
+func status() error {
+	dir, err := gitagent.Dir()
+	if err != nil {
+		return err
+	}
+	cfg, err := gitagent.LoadConfig(dir)
+	if err != nil {
+		return err
+	}
+	queued, err := gitagent.NewQueue(dir).Len()
+	if err != nil {
+		return err
+	}
+
+	server := cfg.URL
+	if server == "" {
+		server = "(not configured)"
+	}
+	fmt.Println("server:", server)
+	fmt.Println("queued:", queued)
+	return nil
+}
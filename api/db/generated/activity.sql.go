@@ -16,6 +16,7 @@ const countActivitiesByUser = `-- name: CountActivitiesByUser :one
 SELECT count(*) FROM activities
 WHERE user_id = $1
   AND ($2::text = '' OR source = $2)
+  AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1)
 `
 
 type CountActivitiesByUserParams struct {
@@ -30,7 +31,27 @@ func (q *Queries) CountActivitiesByUser(ctx context.Context, arg CountActivities
 	return count, err
 }
 
-const insertActivity = `-- name: InsertActivity :exec
+const getEarliestPushTime = `-- name: GetEarliestPushTime :one
+SELECT min(occurred_at)::timestamptz AS earliest
+FROM activities
+WHERE user_id = $1 AND source = $2 AND type = 'push'
+  AND external_id NOT LIKE 'contrib:%'
+`
+
+type GetEarliestPushTimeParams struct {
+	UserID int64  `json:"user_id"`
+	Source string `json:"source"`
+}
+
+// Ignores push rows seeded by the contribution history import.
+func (q *Queries) GetEarliestPushTime(ctx context.Context, arg GetEarliestPushTimeParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getEarliestPushTime, arg.UserID, arg.Source)
+	var earliest pgtype.Timestamptz
+	err := row.Scan(&earliest)
+	return earliest, err
+}
+
+const insertActivity = `-- name: InsertActivity :execrows
 INSERT INTO activities (user_id, source, type, payload, occurred_at, external_id)
 VALUES ($1, $2, $3, $4, $5, $6)
 ON CONFLICT (user_id, source, external_id) DO NOTHING
@@ -45,8 +66,8 @@ type InsertActivityParams struct {
 	ExternalID pgtype.Text        `json:"external_id"`
 }
 
-func (q *Queries) InsertActivity(ctx context.Context, arg InsertActivityParams) error {
-	_, err := q.db.Exec(ctx, insertActivity,
+func (q *Queries) InsertActivity(ctx context.Context, arg InsertActivityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, insertActivity,
 		arg.UserID,
 		arg.Source,
 		arg.Type,
@@ -54,7 +75,10 @@ func (q *Queries) InsertActivity(ctx context.Context, arg InsertActivityParams)
 		arg.OccurredAt,
 		arg.ExternalID,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const listActivitiesByUser = `-- name: ListActivitiesByUser :many
@@ -62,6 +86,7 @@ SELECT id, user_id, source, type, payload, occurred_at, external_id, created_at
 FROM activities
 WHERE user_id = $1
   AND ($4::text = '' OR source = $4)
+  AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1)
 ORDER BY occurred_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -118,26 +143,170 @@ func (q *Queries) ListActivitiesByUser(ctx context.Context, arg ListActivitiesBy
 	return items, nil
 }
 
-const listDistinctActivityUsers = `-- name: ListDistinctActivityUsers :many
-SELECT DISTINCT user_id FROM activities
+const listUnenrichedPushes = `-- name: ListUnenrichedPushes :many
+SELECT id, payload, occurred_at
+FROM activities
+WHERE user_id = $1 AND source = $2 AND type = 'push'
+  AND jsonb_typeof(payload->'payload'->'commits') = 'array'
+  AND jsonb_array_length(payload->'payload'->'commits') > 0
+  AND payload->'payload'->>'stats_fetched' IS NULL
+ORDER BY occurred_at DESC
+LIMIT $3
 `
 
-func (q *Queries) ListDistinctActivityUsers(ctx context.Context) ([]int64, error) {
-	rows, err := q.db.Query(ctx, listDistinctActivityUsers)
+type ListUnenrichedPushesParams struct {
+	UserID int64  `json:"user_id"`
+	Source string `json:"source"`
+	Limit  int32  `json:"limit"`
+}
+
+type ListUnenrichedPushesRow struct {
+	ID         int64              `json:"id"`
+	Payload    json.RawMessage    `json:"payload"`
+	OccurredAt pgtype.Timestamptz `json:"occurred_at"`
+}
+
+// Pushes with commits whose stats haven't been looked up yet, newest first.
+func (q *Queries) ListUnenrichedPushes(ctx context.Context, arg ListUnenrichedPushesParams) ([]ListUnenrichedPushesRow, error) {
+	rows, err := q.db.Query(ctx, listUnenrichedPushes, arg.UserID, arg.Source, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []int64{}
+	items := []ListUnenrichedPushesRow{}
 	for rows.Next() {
-		var user_id int64
-		if err := rows.Scan(&user_id); err != nil {
+		var i ListUnenrichedPushesRow
+		if err := rows.Scan(&i.ID, &i.Payload, &i.OccurredAt); err != nil {
 			return nil, err
 		}
-		items = append(items, user_id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
+
+const listUnenrichedReviews = `-- name: ListUnenrichedReviews :many
+SELECT id, payload, occurred_at
+FROM activities
+WHERE user_id = $1 AND source = $2 AND type = 'review'
+  AND payload->'payload'->>'review_fetched' IS NULL
+ORDER BY occurred_at DESC
+LIMIT $3
+`
+
+type ListUnenrichedReviewsParams struct {
+	UserID int64  `json:"user_id"`
+	Source string `json:"source"`
+	Limit  int32  `json:"limit"`
+}
+
+type ListUnenrichedReviewsRow struct {
+	ID         int64              `json:"id"`
+	Payload    json.RawMessage    `json:"payload"`
+	OccurredAt pgtype.Timestamptz `json:"occurred_at"`
+}
+
+// Reviews whose pull request author and request time haven't been looked
+// up yet, newest first.
+func (q *Queries) ListUnenrichedReviews(ctx context.Context, arg ListUnenrichedReviewsParams) ([]ListUnenrichedReviewsRow, error) {
+	rows, err := q.db.Query(ctx, listUnenrichedReviews, arg.UserID, arg.Source, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnenrichedReviewsRow{}
+	for rows.Next() {
+		var i ListUnenrichedReviewsRow
+		if err := rows.Scan(&i.ID, &i.Payload, &i.OccurredAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rekeyActivity = `-- name: RekeyActivity :execrows
+UPDATE activities SET external_id = $4, payload = $5
+WHERE user_id = $1 AND source = $2 AND external_id = $3
+  AND NOT EXISTS (
+      SELECT 1 FROM activities a
+      WHERE a.user_id = $1 AND a.source = $2 AND a.external_id = $4
+  )
+`
+
+type RekeyActivityParams struct {
+	UserID       int64           `json:"user_id"`
+	Source       string          `json:"source"`
+	ExternalID   pgtype.Text     `json:"external_id"`
+	ExternalID_2 pgtype.Text     `json:"external_id_2"`
+	Payload      json.RawMessage `json:"payload"`
+}
+
+// Moves a row stored under the key a provider used before to its current
+// key, unless a row with the current key already exists.
+func (q *Queries) RekeyActivity(ctx context.Context, arg RekeyActivityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rekeyActivity,
+		arg.UserID,
+		arg.Source,
+		arg.ExternalID,
+		arg.ExternalID_2,
+		arg.Payload,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateActivityPayload = `-- name: UpdateActivityPayload :exec
+UPDATE activities SET payload = $2 WHERE id = $1
+`
+
+type UpdateActivityPayloadParams struct {
+	ID      int64           `json:"id"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func (q *Queries) UpdateActivityPayload(ctx context.Context, arg UpdateActivityPayloadParams) error {
+	_, err := q.db.Exec(ctx, updateActivityPayload, arg.ID, arg.Payload)
+	return err
+}
+
+const upsertActivity = `-- name: UpsertActivity :execrows
+INSERT INTO activities (user_id, source, type, payload, occurred_at, external_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, source, external_id)
+DO UPDATE SET payload = EXCLUDED.payload,
+              occurred_at = EXCLUDED.occurred_at
+WHERE activities.payload IS DISTINCT FROM EXCLUDED.payload
+   OR activities.occurred_at IS DISTINCT FROM EXCLUDED.occurred_at
+`
+
+type UpsertActivityParams struct {
+	UserID     int64              `json:"user_id"`
+	Source     string             `json:"source"`
+	Type       string             `json:"type"`
+	Payload    json.RawMessage    `json:"payload"`
+	OccurredAt pgtype.Timestamptz `json:"occurred_at"`
+	ExternalID pgtype.Text        `json:"external_id"`
+}
+
+func (q *Queries) UpsertActivity(ctx context.Context, arg UpsertActivityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertActivity,
+		arg.UserID,
+		arg.Source,
+		arg.Type,
+		arg.Payload,
+		arg.OccurredAt,
+		arg.ExternalID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
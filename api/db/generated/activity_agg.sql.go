@@ -7,34 +7,44 @@ package dbgen
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const listTopRepos = `-- name: ListTopRepos :many
-SELECT payload->>'repo' AS name,
-       COUNT(*)::int AS count,
-       MAX(occurred_at) AS last_active
+SELECT source,
+       (payload->>'repo')::text AS name,
+       count(*)::int AS count,
+       max(occurred_at)::timestamptz AS last_active
 FROM activities
 WHERE user_id = $1
-  AND occurred_at >= CURRENT_DATE - $2::int
+  AND occurred_at >= $2::timestamptz
+  AND ($3::text = '' OR source = $3)
   AND payload->>'repo' IS NOT NULL
-GROUP BY payload->>'repo'
-ORDER BY count DESC
+  AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1)
+GROUP BY source, payload->>'repo'
+ORDER BY count DESC, name, source
 LIMIT 10
 `
 
 type ListTopReposParams struct {
-	UserID  int64 `json:"user_id"`
-	Column2 int32 `json:"column_2"`
+	UserID  int64              `json:"user_id"`
+	Column2 pgtype.Timestamptz `json:"column_2"`
+	Column3 string             `json:"column_3"`
 }
 
 type ListTopReposRow struct {
-	Name       interface{} `json:"name"`
-	Count      int32       `json:"count"`
-	LastActive interface{} `json:"last_active"`
+	Source     string             `json:"source"`
+	Name       string             `json:"name"`
+	Count      int32              `json:"count"`
+	LastActive pgtype.Timestamptz `json:"last_active"`
 }
 
+// Ranks repos by their activities since $2, counted like daily summaries:
+// per source, so the same name on two hosts is two repos, and without the
+// activity types the user has hidden. $3 limits it to one source.
 func (q *Queries) ListTopRepos(ctx context.Context, arg ListTopReposParams) ([]ListTopReposRow, error) {
-	rows, err := q.db.Query(ctx, listTopRepos, arg.UserID, arg.Column2)
+	rows, err := q.db.Query(ctx, listTopRepos, arg.UserID, arg.Column2, arg.Column3)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +52,12 @@ func (q *Queries) ListTopRepos(ctx context.Context, arg ListTopReposParams) ([]L
 	items := []ListTopReposRow{}
 	for rows.Next() {
 		var i ListTopReposRow
-		if err := rows.Scan(&i.Name, &i.Count, &i.LastActive); err != nil {
+		if err := rows.Scan(
+			&i.Source,
+			&i.Name,
+			&i.Count,
+			&i.LastActive,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -12,7 +12,7 @@ import (
 )
 
 const getDataSourceByUserAndProvider = `-- name: GetDataSourceByUserAndProvider :one
-SELECT id, user_id, provider, access_token, refresh_token, expires_at, created_at
+SELECT id, user_id, provider, access_token, refresh_token, expires_at, created_at, webhook_secret, sync_etag, next_sync_at, history_imported_at
 FROM data_sources
 WHERE user_id = $1 AND provider = $2
 `
@@ -33,10 +33,64 @@ func (q *Queries) GetDataSourceByUserAndProvider(ctx context.Context, arg GetDat
 		&i.RefreshToken,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.WebhookSecret,
+		&i.SyncEtag,
+		&i.NextSyncAt,
+		&i.HistoryImportedAt,
 	)
 	return i, err
 }
 
+const getWebhookSecret = `-- name: GetWebhookSecret :one
+SELECT webhook_secret
+FROM data_sources
+WHERE user_id = $1 AND provider = $2
+`
+
+type GetWebhookSecretParams struct {
+	UserID   int64  `json:"user_id"`
+	Provider string `json:"provider"`
+}
+
+func (q *Queries) GetWebhookSecret(ctx context.Context, arg GetWebhookSecretParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getWebhookSecret, arg.UserID, arg.Provider)
+	var webhook_secret pgtype.Text
+	err := row.Scan(&webhook_secret)
+	return webhook_secret, err
+}
+
+const listDataSourceTokens = `-- name: ListDataSourceTokens :many
+SELECT id, access_token, refresh_token
+FROM data_sources
+ORDER BY id
+`
+
+type ListDataSourceTokensRow struct {
+	ID           int64  `json:"id"`
+	AccessToken  []byte `json:"access_token"`
+	RefreshToken []byte `json:"refresh_token"`
+}
+
+func (q *Queries) ListDataSourceTokens(ctx context.Context) ([]ListDataSourceTokensRow, error) {
+	rows, err := q.db.Query(ctx, listDataSourceTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDataSourceTokensRow{}
+	for rows.Next() {
+		var i ListDataSourceTokensRow
+		if err := rows.Scan(&i.ID, &i.AccessToken, &i.RefreshToken); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDataSourcesByProvider = `-- name: ListDataSourcesByProvider :many
 SELECT id, user_id, provider, created_at
 FROM data_sources
@@ -115,6 +169,95 @@ func (q *Queries) ListDataSourcesByUser(ctx context.Context, userID int64) ([]Li
 	return items, nil
 }
 
+const setHistoryImported = `-- name: SetHistoryImported :exec
+UPDATE data_sources
+SET history_imported_at = now()
+WHERE user_id = $1 AND provider = $2
+`
+
+type SetHistoryImportedParams struct {
+	UserID   int64  `json:"user_id"`
+	Provider string `json:"provider"`
+}
+
+func (q *Queries) SetHistoryImported(ctx context.Context, arg SetHistoryImportedParams) error {
+	_, err := q.db.Exec(ctx, setHistoryImported, arg.UserID, arg.Provider)
+	return err
+}
+
+const setWebhookSecret = `-- name: SetWebhookSecret :execrows
+UPDATE data_sources
+SET webhook_secret = $3
+WHERE user_id = $1 AND provider = $2
+`
+
+type SetWebhookSecretParams struct {
+	UserID        int64       `json:"user_id"`
+	Provider      string      `json:"provider"`
+	WebhookSecret pgtype.Text `json:"webhook_secret"`
+}
+
+func (q *Queries) SetWebhookSecret(ctx context.Context, arg SetWebhookSecretParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setWebhookSecret, arg.UserID, arg.Provider, arg.WebhookSecret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateDataSourceTokens = `-- name: UpdateDataSourceTokens :execrows
+UPDATE data_sources
+SET access_token = $2, refresh_token = $3
+WHERE id = $1 AND access_token = $4 AND refresh_token IS NOT DISTINCT FROM $5
+`
+
+type UpdateDataSourceTokensParams struct {
+	ID             int64  `json:"id"`
+	AccessToken    []byte `json:"access_token"`
+	RefreshToken   []byte `json:"refresh_token"`
+	AccessToken_2  []byte `json:"access_token_2"`
+	RefreshToken_2 []byte `json:"refresh_token_2"`
+}
+
+// Only replaces the tokens if they are still the ones read as $4 and $5,
+// so a token refreshed in the meantime isn't overwritten.
+func (q *Queries) UpdateDataSourceTokens(ctx context.Context, arg UpdateDataSourceTokensParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateDataSourceTokens,
+		arg.ID,
+		arg.AccessToken,
+		arg.RefreshToken,
+		arg.AccessToken_2,
+		arg.RefreshToken_2,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateSyncState = `-- name: UpdateSyncState :exec
+UPDATE data_sources
+SET sync_etag = $3, next_sync_at = $4
+WHERE user_id = $1 AND provider = $2
+`
+
+type UpdateSyncStateParams struct {
+	UserID     int64              `json:"user_id"`
+	Provider   string             `json:"provider"`
+	SyncEtag   pgtype.Text        `json:"sync_etag"`
+	NextSyncAt pgtype.Timestamptz `json:"next_sync_at"`
+}
+
+func (q *Queries) UpdateSyncState(ctx context.Context, arg UpdateSyncStateParams) error {
+	_, err := q.db.Exec(ctx, updateSyncState,
+		arg.UserID,
+		arg.Provider,
+		arg.SyncEtag,
+		arg.NextSyncAt,
+	)
+	return err
+}
+
 const upsertDataSource = `-- name: UpsertDataSource :one
 INSERT INTO data_sources (user_id, provider, access_token, refresh_token, expires_at)
 VALUES ($1, $2, $3, $4, $5)
@@ -71,6 +71,26 @@ type Activity struct {
 	ExternalID pgtype.Text        `json:"external_id"`
 }
 
+type ApiKey struct {
+	ID         int64              `json:"id"`
+	UserID     int64              `json:"user_id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	KeyHash    string             `json:"key_hash"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type CommitStat struct {
+	Repo         string             `json:"repo"`
+	Sha          string             `json:"sha"`
+	Additions    pgtype.Int4        `json:"additions"`
+	Deletions    pgtype.Int4        `json:"deletions"`
+	ChangedFiles pgtype.Int4        `json:"changed_files"`
+	Extensions   json.RawMessage    `json:"extensions"`
+	FetchedAt    pgtype.Timestamptz `json:"fetched_at"`
+}
+
 type DailySummary struct {
 	ID            int64           `json:"id"`
 	UserID        int64           `json:"user_id"`
@@ -80,16 +100,79 @@ type DailySummary struct {
 	CodingMinutes pgtype.Int4     `json:"coding_minutes"`
 	TopRepos      json.RawMessage `json:"top_repos"`
 	TopLanguages  json.RawMessage `json:"top_languages"`
+	LinesAdded    pgtype.Int4     `json:"lines_added"`
+	LinesRemoved  pgtype.Int4     `json:"lines_removed"`
+	TotalReviews  pgtype.Int4     `json:"total_reviews"`
 }
 
 type DataSource struct {
-	ID           int64              `json:"id"`
-	UserID       int64              `json:"user_id"`
-	Provider     string             `json:"provider"`
-	AccessToken  []byte             `json:"access_token"`
-	RefreshToken []byte             `json:"refresh_token"`
-	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ID                int64              `json:"id"`
+	UserID            int64              `json:"user_id"`
+	Provider          string             `json:"provider"`
+	AccessToken       []byte             `json:"access_token"`
+	RefreshToken      []byte             `json:"refresh_token"`
+	ExpiresAt         pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	WebhookSecret     pgtype.Text        `json:"webhook_secret"`
+	SyncEtag          pgtype.Text        `json:"sync_etag"`
+	NextSyncAt        pgtype.Timestamptz `json:"next_sync_at"`
+	HistoryImportedAt pgtype.Timestamptz `json:"history_imported_at"`
+}
+
+type GitScanState struct {
+	RepoPath      string             `json:"repo_path"`
+	Tips          []string           `json:"tips"`
+	ScannedAt     pgtype.Timestamptz `json:"scanned_at"`
+	AuthorsDigest string             `json:"authors_digest"`
+}
+
+type Heartbeat struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	Entity    string             `json:"entity"`
+	Type      string             `json:"type"`
+	Category  string             `json:"category"`
+	Project   string             `json:"project"`
+	Branch    string             `json:"branch"`
+	Language  string             `json:"language"`
+	Editor    string             `json:"editor"`
+	IsWrite   bool               `json:"is_write"`
+	Time      pgtype.Timestamptz `json:"time"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type HiddenActivityType struct {
+	UserID int64  `json:"user_id"`
+	Type   string `json:"type"`
+}
+
+type PullRequest struct {
+	ID            int64              `json:"id"`
+	UserID        int64              `json:"user_id"`
+	Source        string             `json:"source"`
+	Repo          string             `json:"repo"`
+	Number        int32              `json:"number"`
+	Title         string             `json:"title"`
+	State         string             `json:"state"`
+	StateAt       pgtype.Timestamptz `json:"state_at"`
+	OpenedAt      pgtype.Timestamptz `json:"opened_at"`
+	FirstReviewAt pgtype.Timestamptz `json:"first_review_at"`
+	ApprovedAt    pgtype.Timestamptz `json:"approved_at"`
+	MergedAt      pgtype.Timestamptz `json:"merged_at"`
+	ClosedAt      pgtype.Timestamptz `json:"closed_at"`
+	FetchedAt     pgtype.Timestamptz `json:"fetched_at"`
+}
+
+type PullRequestProjection struct {
+	UserID         int64              `json:"user_id"`
+	ProjectedUntil pgtype.Timestamptz `json:"projected_until"`
+}
+
+type RepoLanguage struct {
+	Repo      string             `json:"repo"`
+	Languages []byte             `json:"languages"`
+	FetchedAt pgtype.Timestamptz `json:"fetched_at"`
+	Source    string             `json:"source"`
 }
 
 type RiverClient struct {
@@ -153,6 +236,19 @@ type RiverQueue struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type SummaryBackfill struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	StartDate pgtype.Date        `json:"start_date"`
+	EndDate   pgtype.Date        `json:"end_date"`
+	TotalDays int32              `json:"total_days"`
+	DoneDays  int32              `json:"done_days"`
+	Status    string             `json:"status"`
+	Error     pgtype.Text        `json:"error"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type User struct {
 	ID        int64              `json:"id"`
 	Email     string             `json:"email"`
@@ -161,4 +257,5 @@ type User struct {
 	Password  string             `json:"password"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Timezone  string             `json:"timezone"`
 }
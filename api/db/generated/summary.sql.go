@@ -13,35 +13,181 @@ import (
 )
 
 const aggregateDailySummary = `-- name: AggregateDailySummary :one
+WITH day AS (
+    SELECT
+        type,
+        payload,
+        CASE WHEN jsonb_typeof(payload->'payload'->'commits') = 'array'
+             THEN payload->'payload'->'commits'
+             ELSE '[]'::jsonb
+        END AS commits
+    FROM activities
+    WHERE user_id = $1
+      AND occurred_at >= $2::timestamptz - interval '1 day'
+      AND occurred_at < $3::timestamptz + interval '1 day'
+      AND CASE WHEN type = 'coding' AND payload->>'date' IS NOT NULL
+               THEN payload->>'date' = $5::text
+               ELSE occurred_at >= $2::timestamptz AND occurred_at < $3::timestamptz
+          END
+      AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1)
+),
+commits AS (
+    -- One row per SHA, preferring a copy that carries stats
+    SELECT DISTINCT ON (c->>'sha') c
+    FROM day, jsonb_array_elements(day.commits) AS c
+    WHERE day.type = 'push'
+      AND NOT ($4::boolean AND COALESCE(c->>'message', '') LIKE 'Merge %')
+    ORDER BY c->>'sha', (c->'additions') IS NULL
+)
 SELECT
-    count(*) FILTER (WHERE type = 'push')::int AS total_commits,
-    count(*) FILTER (WHERE type = 'pull_request')::int AS total_prs
-FROM activities
-WHERE user_id = $1
-  AND occurred_at >= $2::timestamptz
-  AND occurred_at < $3::timestamptz
+    ((SELECT count(DISTINCT c->>'sha') FROM commits)
+     + (SELECT COALESCE(SUM(COALESCE(
+            (payload->'payload'->>'distinct_size')::int,
+            (payload->'payload'->>'size')::int,
+            1)), 0)
+        FROM day
+        WHERE type = 'push' AND jsonb_array_length(commits) = 0)
+     + (SELECT COALESCE(SUM(GREATEST(
+            COALESCE((payload->'payload'->>'distinct_size')::int, 0) - jsonb_array_length(commits),
+            0)), 0)
+        FROM day
+        WHERE type = 'push' AND jsonb_array_length(commits) > 0))::int AS total_commits,
+    (SELECT count(*) FROM day
+     WHERE type = 'pull_request'
+       AND COALESCE(payload->'payload'->>'action', 'opened') = 'opened')::int AS total_prs,
+    ((SELECT COALESCE(SUM((payload->>'seconds')::numeric), 0) FROM day WHERE type = 'coding') / 60)::int AS coding_minutes,
+    (SELECT COALESCE(SUM((c->>'additions')::int), 0) FROM commits)::int AS lines_added,
+    (SELECT COALESCE(SUM((c->>'deletions')::int), 0) FROM commits)::int AS lines_removed,
+    (SELECT count(*) FROM day
+     WHERE type = 'review'
+       AND NOT COALESCE(payload->'payload'->'review'->'user'->>'login'
+                        = payload->'payload'->'pull_request'->'user'->>'login', false))::int AS total_reviews
 `
 
 type AggregateDailySummaryParams struct {
 	UserID  int64              `json:"user_id"`
 	Column2 pgtype.Timestamptz `json:"column_2"`
 	Column3 pgtype.Timestamptz `json:"column_3"`
+	Column4 bool               `json:"column_4"`
+	Column5 string             `json:"column_5"`
 }
 
 type AggregateDailySummaryRow struct {
-	TotalCommits int32 `json:"total_commits"`
-	TotalPrs     int32 `json:"total_prs"`
+	TotalCommits  int32 `json:"total_commits"`
+	TotalPrs      int32 `json:"total_prs"`
+	CodingMinutes int32 `json:"coding_minutes"`
+	LinesAdded    int32 `json:"lines_added"`
+	LinesRemoved  int32 `json:"lines_removed"`
+	TotalReviews  int32 `json:"total_reviews"`
 }
 
+// total_commits counts distinct commit SHAs across the day's pushes, so a
+// commit pushed to several branches counts once. Pushes stored without a
+// commits list fall back to distinct_size, then size. The Events API lists
+// at most 20 commits per push, so a push whose distinct_size is larger
+// also counts the commits that weren't listed. When $4 is true, listed
+// commits whose message starts with "Merge " are excluded.
+// Every source is counted; forge providers store their pushes and merge
+// requests in the same payload shape as GitHub events.
+// Activity types the user has hidden are skipped.
+// Coding activities that carry a date count toward that local date ($5)
+// rather than the day their timestamp falls on: WakaTime reports days in
+// the WakaTime account's timezone, which needn't match the user's.
+// lines_added and lines_removed sum the additions and deletions of those
+// same distinct commits, for commits whose stats are known.
+// total_prs counts pull requests opened that day; closes and reopens of
+// the same pull request are separate activities but not new PRs.
+// total_reviews counts pull request reviews the user submitted, except on
+// their own pull requests, where replying to a review comment also
+// creates a review.
 func (q *Queries) AggregateDailySummary(ctx context.Context, arg AggregateDailySummaryParams) (AggregateDailySummaryRow, error) {
-	row := q.db.QueryRow(ctx, aggregateDailySummary, arg.UserID, arg.Column2, arg.Column3)
+	row := q.db.QueryRow(ctx, aggregateDailySummary,
+		arg.UserID,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+	)
 	var i AggregateDailySummaryRow
-	err := row.Scan(&i.TotalCommits, &i.TotalPrs)
+	err := row.Scan(
+		&i.TotalCommits,
+		&i.TotalPrs,
+		&i.CodingMinutes,
+		&i.LinesAdded,
+		&i.LinesRemoved,
+		&i.TotalReviews,
+	)
 	return i, err
 }
 
+const getSummaryDateRange = `-- name: GetSummaryDateRange :one
+SELECT min(date)::date AS first_date, max(date)::date AS last_date
+FROM daily_summaries
+WHERE user_id = $1
+`
+
+type GetSummaryDateRangeRow struct {
+	FirstDate pgtype.Date `json:"first_date"`
+	LastDate  pgtype.Date `json:"last_date"`
+}
+
+func (q *Queries) GetSummaryDateRange(ctx context.Context, userID int64) (GetSummaryDateRangeRow, error) {
+	row := q.db.QueryRow(ctx, getSummaryDateRange, userID)
+	var i GetSummaryDateRangeRow
+	err := row.Scan(&i.FirstDate, &i.LastDate)
+	return i, err
+}
+
+const listDailyRepoActivity = `-- name: ListDailyRepoActivity :many
+SELECT source,
+       (payload->>'repo')::text AS name,
+       count(*)::int AS count
+FROM activities
+WHERE user_id = $1
+  AND occurred_at >= $2::timestamptz
+  AND occurred_at < $3::timestamptz
+  AND payload->>'repo' IS NOT NULL
+  AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1)
+GROUP BY source, payload->>'repo'
+ORDER BY count DESC, name, source
+LIMIT 10
+`
+
+type ListDailyRepoActivityParams struct {
+	UserID  int64              `json:"user_id"`
+	Column2 pgtype.Timestamptz `json:"column_2"`
+	Column3 pgtype.Timestamptz `json:"column_3"`
+}
+
+type ListDailyRepoActivityRow struct {
+	Source string `json:"source"`
+	Name   string `json:"name"`
+	Count  int32  `json:"count"`
+}
+
+// Repos are per source: the same name on two hosts is two repos.
+func (q *Queries) ListDailyRepoActivity(ctx context.Context, arg ListDailyRepoActivityParams) ([]ListDailyRepoActivityRow, error) {
+	rows, err := q.db.Query(ctx, listDailyRepoActivity, arg.UserID, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDailyRepoActivityRow{}
+	for rows.Next() {
+		var i ListDailyRepoActivityRow
+		if err := rows.Scan(&i.Source, &i.Name, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSummariesByUser = `-- name: ListSummariesByUser :many
-SELECT id, user_id, date, total_commits, total_prs, coding_minutes, top_repos, top_languages
+SELECT id, user_id, date, total_commits, total_prs, coding_minutes, top_repos, top_languages, lines_added, lines_removed, total_reviews
 FROM daily_summaries
 WHERE user_id = $1
   AND date >= CURRENT_DATE - $2::int
@@ -71,6 +217,9 @@ func (q *Queries) ListSummariesByUser(ctx context.Context, arg ListSummariesByUs
 			&i.CodingMinutes,
 			&i.TopRepos,
 			&i.TopLanguages,
+			&i.LinesAdded,
+			&i.LinesRemoved,
+			&i.TotalReviews,
 		); err != nil {
 			return nil, err
 		}
@@ -83,15 +232,18 @@ func (q *Queries) ListSummariesByUser(ctx context.Context, arg ListSummariesByUs
 }
 
 const upsertDailySummary = `-- name: UpsertDailySummary :exec
-INSERT INTO daily_summaries (user_id, date, total_commits, total_prs, coding_minutes, top_repos, top_languages)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO daily_summaries (user_id, date, total_commits, total_prs, coding_minutes, top_repos, top_languages, lines_added, lines_removed, total_reviews)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 ON CONFLICT (user_id, date)
 DO UPDATE SET
     total_commits = EXCLUDED.total_commits,
     total_prs = EXCLUDED.total_prs,
     coding_minutes = EXCLUDED.coding_minutes,
     top_repos = EXCLUDED.top_repos,
-    top_languages = EXCLUDED.top_languages
+    top_languages = EXCLUDED.top_languages,
+    lines_added = EXCLUDED.lines_added,
+    lines_removed = EXCLUDED.lines_removed,
+    total_reviews = EXCLUDED.total_reviews
 `
 
 type UpsertDailySummaryParams struct {
@@ -102,6 +254,9 @@ type UpsertDailySummaryParams struct {
 	CodingMinutes pgtype.Int4     `json:"coding_minutes"`
 	TopRepos      json.RawMessage `json:"top_repos"`
 	TopLanguages  json.RawMessage `json:"top_languages"`
+	LinesAdded    pgtype.Int4     `json:"lines_added"`
+	LinesRemoved  pgtype.Int4     `json:"lines_removed"`
+	TotalReviews  pgtype.Int4     `json:"total_reviews"`
 }
 
 func (q *Queries) UpsertDailySummary(ctx context.Context, arg UpsertDailySummaryParams) error {
@@ -113,6 +268,9 @@ func (q *Queries) UpsertDailySummary(ctx context.Context, arg UpsertDailySummary
 		arg.CodingMinutes,
 		arg.TopRepos,
 		arg.TopLanguages,
+		arg.LinesAdded,
+		arg.LinesRemoved,
+		arg.TotalReviews,
 	)
 	return err
 }
@@ -49,11 +49,57 @@ func (q *Queries) ListDailySummariesForHeatmap(ctx context.Context, arg ListDail
 	return items, nil
 }
 
+const listLanguageTotals = `-- name: ListLanguageTotals :many
+SELECT (l->>'name')::text AS name,
+       SUM((l->>'weight')::numeric)::float8 AS weight
+FROM daily_summaries ds,
+     jsonb_array_elements(COALESCE(ds.top_languages, '[]'::jsonb)) AS l
+WHERE ds.user_id = $1
+  AND ds.date >= $2::date
+  AND ds.date <= $3::date
+GROUP BY l->>'name'
+ORDER BY weight DESC
+`
+
+type ListLanguageTotalsParams struct {
+	UserID  int64       `json:"user_id"`
+	Column2 pgtype.Date `json:"column_2"`
+	Column3 pgtype.Date `json:"column_3"`
+}
+
+type ListLanguageTotalsRow struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+func (q *Queries) ListLanguageTotals(ctx context.Context, arg ListLanguageTotalsParams) ([]ListLanguageTotalsRow, error) {
+	rows, err := q.db.Query(ctx, listLanguageTotals, arg.UserID, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLanguageTotalsRow{}
+	for rows.Next() {
+		var i ListLanguageTotalsRow
+		if err := rows.Scan(&i.Name, &i.Weight); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMonthlySummaries = `-- name: ListMonthlySummaries :many
 SELECT DATE_TRUNC('month', date)::date AS period,
        COALESCE(SUM(total_commits), 0)::int AS total_commits,
        COALESCE(SUM(total_prs), 0)::int AS total_prs,
-       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes
+       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes,
+       COALESCE(SUM(lines_added), 0)::int AS lines_added,
+       COALESCE(SUM(lines_removed), 0)::int AS lines_removed,
+       COALESCE(SUM(total_reviews), 0)::int AS total_reviews
 FROM daily_summaries
 WHERE user_id = $1
   AND date >= CURRENT_DATE - ($2::int * 30)
@@ -71,6 +117,9 @@ type ListMonthlySummariesRow struct {
 	TotalCommits  int32       `json:"total_commits"`
 	TotalPrs      int32       `json:"total_prs"`
 	CodingMinutes int32       `json:"coding_minutes"`
+	LinesAdded    int32       `json:"lines_added"`
+	LinesRemoved  int32       `json:"lines_removed"`
+	TotalReviews  int32       `json:"total_reviews"`
 }
 
 func (q *Queries) ListMonthlySummaries(ctx context.Context, arg ListMonthlySummariesParams) ([]ListMonthlySummariesRow, error) {
@@ -87,6 +136,9 @@ func (q *Queries) ListMonthlySummaries(ctx context.Context, arg ListMonthlySumma
 			&i.TotalCommits,
 			&i.TotalPrs,
 			&i.CodingMinutes,
+			&i.LinesAdded,
+			&i.LinesRemoved,
+			&i.TotalReviews,
 		); err != nil {
 			return nil, err
 		}
@@ -102,7 +154,10 @@ const listWeeklySummaries = `-- name: ListWeeklySummaries :many
 SELECT DATE_TRUNC('week', date)::date AS period,
        COALESCE(SUM(total_commits), 0)::int AS total_commits,
        COALESCE(SUM(total_prs), 0)::int AS total_prs,
-       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes
+       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes,
+       COALESCE(SUM(lines_added), 0)::int AS lines_added,
+       COALESCE(SUM(lines_removed), 0)::int AS lines_removed,
+       COALESCE(SUM(total_reviews), 0)::int AS total_reviews
 FROM daily_summaries
 WHERE user_id = $1
   AND date >= CURRENT_DATE - ($2::int * 7)
@@ -120,6 +175,9 @@ type ListWeeklySummariesRow struct {
 	TotalCommits  int32       `json:"total_commits"`
 	TotalPrs      int32       `json:"total_prs"`
 	CodingMinutes int32       `json:"coding_minutes"`
+	LinesAdded    int32       `json:"lines_added"`
+	LinesRemoved  int32       `json:"lines_removed"`
+	TotalReviews  int32       `json:"total_reviews"`
 }
 
 func (q *Queries) ListWeeklySummaries(ctx context.Context, arg ListWeeklySummariesParams) ([]ListWeeklySummariesRow, error) {
@@ -136,6 +194,9 @@ func (q *Queries) ListWeeklySummaries(ctx context.Context, arg ListWeeklySummari
 			&i.TotalCommits,
 			&i.TotalPrs,
 			&i.CodingMinutes,
+			&i.LinesAdded,
+			&i.LinesRemoved,
+			&i.TotalReviews,
 		); err != nil {
 			return nil, err
 		}
@@ -14,7 +14,7 @@ import (
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (email, name, password)
 VALUES ($1, $2, $3)
-RETURNING id, email, name, avatar_url, created_at, updated_at
+RETURNING id, email, name, avatar_url, created_at, updated_at, timezone
 `
 
 type CreateUserParams struct {
@@ -30,6 +30,7 @@ type CreateUserRow struct {
 	AvatarUrl pgtype.Text        `json:"avatar_url"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Timezone  string             `json:"timezone"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
@@ -42,12 +43,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, name, avatar_url, password, created_at, updated_at
+SELECT id, email, name, avatar_url, password, created_at, updated_at, timezone
 FROM users
 WHERE email = $1
 `
@@ -63,12 +65,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, name, avatar_url, created_at, updated_at
+SELECT id, email, name, avatar_url, created_at, updated_at, timezone
 FROM users
 WHERE id = $1
 `
@@ -80,6 +83,7 @@ type GetUserByIDRow struct {
 	AvatarUrl pgtype.Text        `json:"avatar_url"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Timezone  string             `json:"timezone"`
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, error) {
@@ -92,15 +96,104 @@ func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, er
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
 
+const getUserEmailsDigest = `-- name: GetUserEmailsDigest :one
+SELECT md5(coalesce(string_agg(lower(email), ',' ORDER BY lower(email)), ''))::text AS digest
+FROM users
+`
+
+// Changes whenever a user is added or an email changes.
+func (q *Queries) GetUserEmailsDigest(ctx context.Context) (string, error) {
+	row := q.db.QueryRow(ctx, getUserEmailsDigest)
+	var digest string
+	err := row.Scan(&digest)
+	return digest, err
+}
+
+const getUserTimezone = `-- name: GetUserTimezone :one
+SELECT timezone FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserTimezone(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRow(ctx, getUserTimezone, id)
+	var timezone string
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
+const listActiveUserTimezones = `-- name: ListActiveUserTimezones :many
+SELECT u.id, u.timezone
+FROM users u
+WHERE EXISTS (SELECT 1 FROM activities a WHERE a.user_id = u.id)
+ORDER BY u.id
+`
+
+type ListActiveUserTimezonesRow struct {
+	ID       int64  `json:"id"`
+	Timezone string `json:"timezone"`
+}
+
+func (q *Queries) ListActiveUserTimezones(ctx context.Context) ([]ListActiveUserTimezonesRow, error) {
+	rows, err := q.db.Query(ctx, listActiveUserTimezones)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActiveUserTimezonesRow{}
+	for rows.Next() {
+		var i ListActiveUserTimezonesRow
+		if err := rows.Scan(&i.ID, &i.Timezone); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByEmails = `-- name: ListUsersByEmails :many
+SELECT id, lower(email)::text AS email
+FROM users
+WHERE lower(email) = ANY($1::text[])
+`
+
+type ListUsersByEmailsRow struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+}
+
+// Matches case-insensitively; pass lowercased emails.
+func (q *Queries) ListUsersByEmails(ctx context.Context, dollar_1 []string) ([]ListUsersByEmailsRow, error) {
+	rows, err := q.db.Query(ctx, listUsersByEmails, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUsersByEmailsRow{}
+	for rows.Next() {
+		var i ListUsersByEmailsRow
+		if err := rows.Scan(&i.ID, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET name = $2, avatar_url = $3, updated_at = now()
 WHERE id = $1
-RETURNING id, email, name, avatar_url, created_at, updated_at
+RETURNING id, email, name, avatar_url, created_at, updated_at, timezone
 `
 
 type UpdateUserParams struct {
@@ -116,6 +209,7 @@ type UpdateUserRow struct {
 	AvatarUrl pgtype.Text        `json:"avatar_url"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Timezone  string             `json:"timezone"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
@@ -128,6 +222,44 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateU
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const updateUserTimezone = `-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, email, name, avatar_url, created_at, updated_at, timezone
+`
+
+type UpdateUserTimezoneParams struct {
+	ID       int64  `json:"id"`
+	Timezone string `json:"timezone"`
+}
+
+type UpdateUserTimezoneRow struct {
+	ID        int64              `json:"id"`
+	Email     string             `json:"email"`
+	Name      string             `json:"name"`
+	AvatarUrl pgtype.Text        `json:"avatar_url"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Timezone  string             `json:"timezone"`
+}
+
+func (q *Queries) UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) (UpdateUserTimezoneRow, error) {
+	row := q.db.QueryRow(ctx, updateUserTimezone, arg.ID, arg.Timezone)
+	var i UpdateUserTimezoneRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
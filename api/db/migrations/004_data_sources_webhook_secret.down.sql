@@ -0,0 +1 @@
+ALTER TABLE data_sources DROP COLUMN IF EXISTS webhook_secret;
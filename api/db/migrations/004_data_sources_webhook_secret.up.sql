@@ -0,0 +1 @@
+ALTER TABLE data_sources ADD COLUMN webhook_secret text;
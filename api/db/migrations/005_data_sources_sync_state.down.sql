@@ -0,0 +1,2 @@
+ALTER TABLE data_sources DROP COLUMN IF EXISTS next_sync_at;
+ALTER TABLE data_sources DROP COLUMN IF EXISTS sync_etag;
@@ -0,0 +1,2 @@
+ALTER TABLE data_sources ADD COLUMN sync_etag text;
+ALTER TABLE data_sources ADD COLUMN next_sync_at timestamptz;
@@ -0,0 +1,6 @@
+-- repo_languages: cached GitHub language breakdown (bytes per language)
+CREATE TABLE repo_languages (
+    repo        text PRIMARY KEY,
+    languages   jsonb NOT NULL,
+    fetched_at  timestamptz NOT NULL DEFAULT now()
+);
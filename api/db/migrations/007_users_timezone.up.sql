@@ -0,0 +1,2 @@
+-- IANA zone name; daily summaries are bucketed by the user's local day
+ALTER TABLE users ADD COLUMN timezone text NOT NULL DEFAULT 'UTC';
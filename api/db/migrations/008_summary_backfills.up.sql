@@ -0,0 +1,15 @@
+-- summary_backfills: progress of admin-triggered daily summary rebuilds
+CREATE TABLE summary_backfills (
+    id          bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id     bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_date  date NOT NULL,
+    end_date    date NOT NULL,
+    total_days  int NOT NULL,
+    done_days   int NOT NULL DEFAULT 0,
+    status      text NOT NULL DEFAULT 'pending',
+    error       text,
+    created_at  timestamptz DEFAULT now(),
+    updated_at  timestamptz DEFAULT now()
+);
+
+CREATE INDEX idx_summary_backfills_user_id ON summary_backfills (user_id);
@@ -0,0 +1 @@
+ALTER TABLE data_sources DROP COLUMN IF EXISTS history_imported_at;
@@ -0,0 +1 @@
+ALTER TABLE data_sources ADD COLUMN history_imported_at timestamptz;
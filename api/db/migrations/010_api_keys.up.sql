@@ -0,0 +1,13 @@
+-- api_keys: per-user keys for editor plugins and scripts. Only the SHA-256
+-- of each key is stored; prefix identifies it in listings.
+CREATE TABLE api_keys (
+    id           bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id      bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         text NOT NULL,
+    prefix       text NOT NULL,
+    key_hash     text NOT NULL UNIQUE,
+    last_used_at timestamptz,
+    created_at   timestamptz DEFAULT now()
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
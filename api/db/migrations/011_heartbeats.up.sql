@@ -0,0 +1,18 @@
+-- heartbeats: raw WakaTime-style editor heartbeats. A plugin retrying a
+-- bulk upload resends the same (time, entity), so those are deduplicated.
+CREATE TABLE heartbeats (
+    id         bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id    bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    entity     text NOT NULL,
+    type       text NOT NULL,
+    category   text NOT NULL DEFAULT 'coding',
+    project    text NOT NULL DEFAULT '',
+    branch     text NOT NULL DEFAULT '',
+    language   text NOT NULL DEFAULT '',
+    editor     text NOT NULL DEFAULT '',
+    is_write   boolean NOT NULL DEFAULT false,
+    time       timestamptz NOT NULL,
+    created_at timestamptz DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_heartbeats_dedup ON heartbeats (user_id, time, entity);
@@ -0,0 +1,8 @@
+-- git_scan_state: the ref tips each configured local repository had at its
+-- last scan. The next scan only reads commits that aren't reachable from
+-- them.
+CREATE TABLE git_scan_state (
+    repo_path  text PRIMARY KEY,
+    tips       text[] NOT NULL,
+    scanned_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,8 @@
+-- hidden_activity_types: activity types a user has chosen to leave off
+-- their dashboard. Hidden activities are still stored, but the feed, top
+-- repositories and daily summaries skip them.
+CREATE TABLE hidden_activity_types (
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type    text NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE daily_summaries
+    DROP COLUMN IF EXISTS lines_added,
+    DROP COLUMN IF EXISTS lines_removed;
+
+DROP TABLE IF EXISTS commit_stats;
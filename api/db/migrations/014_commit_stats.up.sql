@@ -0,0 +1,17 @@
+-- commit_stats: line and file counts of GitHub commits, cached per
+-- repository so a commit is fetched once however many pushes or users
+-- include it. NULL counts mark commits the API couldn't return.
+CREATE TABLE commit_stats (
+    repo          text NOT NULL,
+    sha           text NOT NULL,
+    additions     int,
+    deletions     int,
+    changed_files int,
+    extensions    jsonb,
+    fetched_at    timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (repo, sha)
+);
+
+ALTER TABLE daily_summaries
+    ADD COLUMN lines_added int DEFAULT 0,
+    ADD COLUMN lines_removed int DEFAULT 0;
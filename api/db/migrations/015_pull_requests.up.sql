@@ -0,0 +1,23 @@
+-- pull_requests: the lifecycle of each pull request a user opened, built
+-- from their pull_request activities and, for GitHub, refined from the
+-- pull request API. state is open, closed or merged; state_at is when that
+-- state was last known to hold, so newer information wins.
+CREATE TABLE pull_requests (
+    id              bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id         bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source          text NOT NULL,
+    repo            text NOT NULL,
+    number          int NOT NULL,
+    title           text NOT NULL DEFAULT '',
+    state           text NOT NULL,
+    state_at        timestamptz NOT NULL,
+    opened_at       timestamptz NOT NULL,
+    first_review_at timestamptz,
+    approved_at     timestamptz,
+    merged_at       timestamptz,
+    closed_at       timestamptz,
+    fetched_at      timestamptz,
+    UNIQUE (user_id, source, repo, number)
+);
+
+CREATE INDEX idx_pull_requests_user_opened ON pull_requests (user_id, opened_at);
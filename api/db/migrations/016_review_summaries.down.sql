@@ -0,0 +1,2 @@
+ALTER TABLE daily_summaries
+    DROP COLUMN IF EXISTS total_reviews;
@@ -0,0 +1,19 @@
+ALTER TABLE daily_summaries
+    ADD COLUMN total_reviews int DEFAULT 0;
+
+-- Fill in the days already aggregated. Reviews of the user's own pull
+-- requests are skipped once review enrichment has identified them, which
+-- re-aggregates the affected days.
+UPDATE daily_summaries d
+SET total_reviews = r.count
+FROM (
+    SELECT a.user_id,
+           (a.occurred_at AT TIME ZONE u.timezone)::date AS date,
+           count(*)::int AS count
+    FROM activities a
+    JOIN users u ON u.id = a.user_id
+    WHERE a.type = 'review'
+      AND a.type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = a.user_id)
+    GROUP BY 1, 2
+) r
+WHERE d.user_id = r.user_id AND d.date = r.date;
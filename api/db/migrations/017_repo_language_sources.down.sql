@@ -0,0 +1,8 @@
+DELETE FROM repo_languages WHERE source <> 'github';
+
+ALTER TABLE repo_languages
+    DROP CONSTRAINT repo_languages_pkey,
+    ADD PRIMARY KEY (repo);
+
+ALTER TABLE repo_languages
+    DROP COLUMN IF EXISTS source;
@@ -0,0 +1,9 @@
+-- repo_languages: key the cache by the GitHub deployment as well, since
+-- github.com and an Enterprise Server can both have a repo of the same
+-- name. Existing rows were all fetched from github.com.
+ALTER TABLE repo_languages
+    ADD COLUMN source text NOT NULL DEFAULT 'github';
+
+ALTER TABLE repo_languages
+    DROP CONSTRAINT repo_languages_pkey,
+    ADD PRIMARY KEY (source, repo);
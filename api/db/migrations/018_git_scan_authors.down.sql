@@ -0,0 +1,2 @@
+ALTER TABLE git_scan_state
+    DROP COLUMN IF EXISTS authors_digest;
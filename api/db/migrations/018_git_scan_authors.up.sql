@@ -0,0 +1,5 @@
+-- git_scan_state: record which user emails a scan matched authors against.
+-- Commits by unknown emails are skipped, so when the emails change the
+-- repository is read again from the history floor rather than from tips.
+ALTER TABLE git_scan_state
+    ADD COLUMN authors_digest text NOT NULL DEFAULT '';
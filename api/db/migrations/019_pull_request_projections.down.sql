@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_activities_user_type_created;
+DROP TABLE IF EXISTS pull_request_projections;
@@ -0,0 +1,10 @@
+-- pull_request_projections: how far each user's pull_request activities
+-- have been folded into pull_requests. projected_until is the created_at of
+-- the newest activity projected; the next projection only refolds the pull
+-- requests that have activities stored after it.
+CREATE TABLE pull_request_projections (
+    user_id         bigint PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    projected_until timestamptz NOT NULL
+);
+
+CREATE INDEX idx_activities_user_type_created ON activities (user_id, type, created_at);
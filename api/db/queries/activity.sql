@@ -1,20 +1,69 @@
--- name: InsertActivity :exec
+-- name: InsertActivity :execrows
 INSERT INTO activities (user_id, source, type, payload, occurred_at, external_id)
 VALUES ($1, $2, $3, $4, $5, $6)
 ON CONFLICT (user_id, source, external_id) DO NOTHING;
 
+-- name: UpsertActivity :execrows
+INSERT INTO activities (user_id, source, type, payload, occurred_at, external_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, source, external_id)
+DO UPDATE SET payload = EXCLUDED.payload,
+              occurred_at = EXCLUDED.occurred_at
+WHERE activities.payload IS DISTINCT FROM EXCLUDED.payload
+   OR activities.occurred_at IS DISTINCT FROM EXCLUDED.occurred_at;
+
+-- name: RekeyActivity :execrows
+-- Moves a row stored under the key a provider used before to its current
+-- key, unless a row with the current key already exists.
+UPDATE activities SET external_id = $4, payload = $5
+WHERE user_id = $1 AND source = $2 AND external_id = $3
+  AND NOT EXISTS (
+      SELECT 1 FROM activities a
+      WHERE a.user_id = $1 AND a.source = $2 AND a.external_id = $4
+  );
+
 -- name: ListActivitiesByUser :many
 SELECT id, user_id, source, type, payload, occurred_at, external_id, created_at
 FROM activities
 WHERE user_id = $1
   AND ($4::text = '' OR source = $4)
+  AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1)
 ORDER BY occurred_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: CountActivitiesByUser :one
 SELECT count(*) FROM activities
 WHERE user_id = $1
-  AND ($2::text = '' OR source = $2);
+  AND ($2::text = '' OR source = $2)
+  AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1);
+
+-- name: GetEarliestPushTime :one
+-- Ignores push rows seeded by the contribution history import.
+SELECT min(occurred_at)::timestamptz AS earliest
+FROM activities
+WHERE user_id = $1 AND source = $2 AND type = 'push'
+  AND external_id NOT LIKE 'contrib:%';
+
+-- name: ListUnenrichedPushes :many
+-- Pushes with commits whose stats haven't been looked up yet, newest first.
+SELECT id, payload, occurred_at
+FROM activities
+WHERE user_id = $1 AND source = $2 AND type = 'push'
+  AND jsonb_typeof(payload->'payload'->'commits') = 'array'
+  AND jsonb_array_length(payload->'payload'->'commits') > 0
+  AND payload->'payload'->>'stats_fetched' IS NULL
+ORDER BY occurred_at DESC
+LIMIT $3;
+
+-- name: ListUnenrichedReviews :many
+-- Reviews whose pull request author and request time haven't been looked
+-- up yet, newest first.
+SELECT id, payload, occurred_at
+FROM activities
+WHERE user_id = $1 AND source = $2 AND type = 'review'
+  AND payload->'payload'->>'review_fetched' IS NULL
+ORDER BY occurred_at DESC
+LIMIT $3;
 
--- name: ListDistinctActivityUsers :many
-SELECT DISTINCT user_id FROM activities;
+-- name: UpdateActivityPayload :exec
+UPDATE activities SET payload = $2 WHERE id = $1;
@@ -1,11 +1,17 @@
 -- name: ListTopRepos :many
-SELECT payload->>'repo' AS name,
-       COUNT(*)::int AS count,
-       MAX(occurred_at) AS last_active
+-- Ranks repos by their activities since $2, counted like daily summaries:
+-- per source, so the same name on two hosts is two repos, and without the
+-- activity types the user has hidden. $3 limits it to one source.
+SELECT source,
+       (payload->>'repo')::text AS name,
+       count(*)::int AS count,
+       max(occurred_at)::timestamptz AS last_active
 FROM activities
 WHERE user_id = $1
-  AND occurred_at >= CURRENT_DATE - $2::int
+  AND occurred_at >= $2::timestamptz
+  AND ($3::text = '' OR source = $3)
   AND payload->>'repo' IS NOT NULL
-GROUP BY payload->>'repo'
-ORDER BY count DESC
+  AND type NOT IN (SELECT h.type FROM hidden_activity_types h WHERE h.user_id = $1)
+GROUP BY source, payload->>'repo'
+ORDER BY count DESC, name, source
 LIMIT 10;
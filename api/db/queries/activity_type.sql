@@ -0,0 +1,21 @@
+-- name: ListActivityTypeCounts :many
+SELECT type, count(*) AS count
+FROM activities
+WHERE user_id = $1
+GROUP BY type
+ORDER BY type;
+
+-- name: ListHiddenActivityTypes :many
+SELECT type FROM hidden_activity_types
+WHERE user_id = $1
+ORDER BY type;
+
+-- name: SetHiddenActivityTypes :exec
+-- Replaces the user's hidden types with $2 in one statement.
+WITH removed AS (
+    DELETE FROM hidden_activity_types
+    WHERE user_id = $1 AND NOT (type = ANY($2::text[]))
+)
+INSERT INTO hidden_activity_types (user_id, type)
+SELECT $1, unnest($2::text[])
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,21 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, name, prefix, key_hash)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, name, prefix, key_hash, last_used_at, created_at;
+
+-- name: ListAPIKeysByUser :many
+SELECT id, user_id, name, prefix, key_hash, last_used_at, created_at
+FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteAPIKey :execrows
+DELETE FROM api_keys
+WHERE id = $1 AND user_id = $2;
+
+-- name: TouchAPIKey :one
+-- Resolves a key hash to its owner and records when it was last used.
+UPDATE api_keys
+SET last_used_at = now()
+WHERE key_hash = $1
+RETURNING user_id;
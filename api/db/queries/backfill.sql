@@ -0,0 +1,14 @@
+-- name: CreateSummaryBackfill :one
+INSERT INTO summary_backfills (user_id, start_date, end_date, total_days)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, start_date, end_date, total_days, done_days, status, error, created_at, updated_at;
+
+-- name: GetSummaryBackfill :one
+SELECT id, user_id, start_date, end_date, total_days, done_days, status, error, created_at, updated_at
+FROM summary_backfills
+WHERE id = $1;
+
+-- name: UpdateSummaryBackfillProgress :exec
+UPDATE summary_backfills
+SET done_days = $2, status = $3, error = $4, updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,18 @@
+-- name: ListCommitStats :many
+SELECT repo, sha, additions, deletions, changed_files, extensions, fetched_at
+FROM commit_stats
+WHERE repo = $1 AND sha = ANY($2::text[]);
+
+-- name: InsertCommitStats :exec
+-- A commit's stats never change, so the first row stored with stats wins.
+-- A row without them, stored for a user who couldn't read the commit, is
+-- replaced once another user's fetch succeeds.
+INSERT INTO commit_stats (repo, sha, additions, deletions, changed_files, extensions)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (repo, sha) DO UPDATE
+SET additions = EXCLUDED.additions,
+    deletions = EXCLUDED.deletions,
+    changed_files = EXCLUDED.changed_files,
+    extensions = EXCLUDED.extensions,
+    fetched_at = now()
+WHERE commit_stats.additions IS NULL;
@@ -8,7 +8,7 @@ DO UPDATE SET access_token = EXCLUDED.access_token,
 RETURNING id, user_id, provider, created_at;
 
 -- name: GetDataSourceByUserAndProvider :one
-SELECT id, user_id, provider, access_token, refresh_token, expires_at, created_at
+SELECT id, user_id, provider, access_token, refresh_token, expires_at, created_at, webhook_secret, sync_etag, next_sync_at, history_imported_at
 FROM data_sources
 WHERE user_id = $1 AND provider = $2;
 
@@ -21,3 +21,35 @@ WHERE user_id = $1;
 SELECT id, user_id, provider, created_at
 FROM data_sources
 WHERE provider = $1;
+
+-- name: SetWebhookSecret :execrows
+UPDATE data_sources
+SET webhook_secret = $3
+WHERE user_id = $1 AND provider = $2;
+
+-- name: GetWebhookSecret :one
+SELECT webhook_secret
+FROM data_sources
+WHERE user_id = $1 AND provider = $2;
+
+-- name: ListDataSourceTokens :many
+SELECT id, access_token, refresh_token
+FROM data_sources
+ORDER BY id;
+
+-- name: UpdateDataSourceTokens :execrows
+-- Only replaces the tokens if they are still the ones read as $4 and $5,
+-- so a token refreshed in the meantime isn't overwritten.
+UPDATE data_sources
+SET access_token = $2, refresh_token = $3
+WHERE id = $1 AND access_token = $4 AND refresh_token IS NOT DISTINCT FROM $5;
+
+-- name: UpdateSyncState :exec
+UPDATE data_sources
+SET sync_etag = $3, next_sync_at = $4
+WHERE user_id = $1 AND provider = $2;
+
+-- name: SetHistoryImported :exec
+UPDATE data_sources
+SET history_imported_at = now()
+WHERE user_id = $1 AND provider = $2;
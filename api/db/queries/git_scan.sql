@@ -0,0 +1,12 @@
+-- name: GetGitScanState :one
+SELECT repo_path, tips, scanned_at, authors_digest
+FROM git_scan_state
+WHERE repo_path = $1;
+
+-- name: UpsertGitScanState :exec
+INSERT INTO git_scan_state (repo_path, tips, scanned_at, authors_digest)
+VALUES ($1, $2, now(), $3)
+ON CONFLICT (repo_path) DO UPDATE
+SET tips = EXCLUDED.tips,
+    scanned_at = EXCLUDED.scanned_at,
+    authors_digest = EXCLUDED.authors_digest;
@@ -0,0 +1,12 @@
+-- name: InsertHeartbeat :execrows
+INSERT INTO heartbeats (user_id, entity, type, category, project, branch, language, editor, is_write, time)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (user_id, time, entity) DO NOTHING;
+
+-- name: ListHeartbeatsInRange :many
+SELECT time, project, language, editor, category
+FROM heartbeats
+WHERE user_id = $1
+  AND time >= $2::timestamptz
+  AND time < $3::timestamptz
+ORDER BY time;
@@ -0,0 +1,73 @@
+-- name: ListPullRequestActivities :many
+-- Returns every activity of the pull requests that have an activity stored
+-- since $2, oldest first, so each is folded from its whole history.
+WITH touched AS (
+    SELECT DISTINCT source,
+           payload->>'repo' AS repo,
+           coalesce(nullif(payload->'payload'->>'number', '0'), payload->'payload'->'pull_request'->>'number') AS number
+    FROM activities
+    WHERE user_id = $1 AND type = 'pull_request' AND created_at >= $2::timestamptz
+)
+SELECT a.source, a.payload, a.occurred_at, a.created_at
+FROM activities a
+JOIN touched t
+  ON t.source = a.source
+ AND t.repo = a.payload->>'repo'
+ AND t.number = coalesce(nullif(a.payload->'payload'->>'number', '0'), a.payload->'payload'->'pull_request'->>'number')
+WHERE a.user_id = $1 AND a.type = 'pull_request'
+ORDER BY a.occurred_at;
+
+-- name: GetPullRequestProjection :one
+SELECT projected_until FROM pull_request_projections WHERE user_id = $1;
+
+-- name: UpsertPullRequestProjection :exec
+-- Only moves forward, since projections can overlap.
+INSERT INTO pull_request_projections (user_id, projected_until)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET projected_until = GREATEST(pull_request_projections.projected_until, EXCLUDED.projected_until);
+
+-- name: UpsertPullRequest :exec
+-- Lifecycle timestamps only move earlier, since each marks the first time
+-- something happened. state and closed_at follow the newest information.
+INSERT INTO pull_requests (user_id, source, repo, number, title, state, state_at, opened_at, first_review_at, approved_at, merged_at, closed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (user_id, source, repo, number)
+DO UPDATE SET
+    title = CASE WHEN EXCLUDED.title <> '' THEN EXCLUDED.title ELSE pull_requests.title END,
+    opened_at = LEAST(pull_requests.opened_at, EXCLUDED.opened_at),
+    first_review_at = LEAST(pull_requests.first_review_at, EXCLUDED.first_review_at),
+    approved_at = LEAST(pull_requests.approved_at, EXCLUDED.approved_at),
+    merged_at = LEAST(pull_requests.merged_at, EXCLUDED.merged_at),
+    state = CASE WHEN EXCLUDED.state_at >= pull_requests.state_at THEN EXCLUDED.state ELSE pull_requests.state END,
+    closed_at = CASE WHEN EXCLUDED.state_at >= pull_requests.state_at THEN EXCLUDED.closed_at ELSE pull_requests.closed_at END,
+    state_at = GREATEST(pull_requests.state_at, EXCLUDED.state_at);
+
+-- name: ListPullRequestsToFetch :many
+-- Pull requests never fetched from the API, and open ones not fetched
+-- within the last hour, newest first.
+SELECT id, repo, number
+FROM pull_requests
+WHERE user_id = $1 AND source = $2
+  AND (fetched_at IS NULL
+       OR (state = 'open' AND fetched_at < now() - interval '1 hour'))
+ORDER BY opened_at DESC
+LIMIT $3;
+
+-- name: MarkPullRequestFetched :exec
+UPDATE pull_requests SET fetched_at = now() WHERE id = $1;
+
+-- name: ListPullRequestWeeklyMetrics :many
+-- Groups pull requests by the week they were opened in, in timezone $3.
+-- Medians are 0 when no pull request of the week was reviewed or merged.
+SELECT date_trunc('week', opened_at AT TIME ZONE $3::text)::date AS week,
+       count(*)::int AS opened,
+       count(merged_at)::int AS merged,
+       count(*) FILTER (WHERE closed_at IS NOT NULL AND merged_at IS NULL)::int AS closed,
+       count(first_review_at)::int AS reviewed,
+       COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY extract(epoch FROM first_review_at - opened_at)), 0)::float8 AS median_first_review_seconds,
+       COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY extract(epoch FROM merged_at - opened_at)), 0)::float8 AS median_merge_seconds
+FROM pull_requests
+WHERE user_id = $1 AND opened_at >= $2::timestamptz
+GROUP BY 1
+ORDER BY 1;
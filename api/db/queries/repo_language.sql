@@ -0,0 +1,10 @@
+-- name: GetRepoLanguages :one
+SELECT repo, languages, fetched_at, source
+FROM repo_languages
+WHERE source = $1 AND repo = $2;
+
+-- name: UpsertRepoLanguages :exec
+INSERT INTO repo_languages (source, repo, languages, fetched_at)
+VALUES ($1, $2, $3, now())
+ON CONFLICT (source, repo)
+DO UPDATE SET languages = EXCLUDED.languages, fetched_at = EXCLUDED.fetched_at;
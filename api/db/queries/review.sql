@@ -0,0 +1,63 @@
+-- Reviews of the user's own pull requests are left out of every query, as
+-- in AggregateDailySummary.
+
+-- name: ListDailyReviewCounts :many
+SELECT (occurred_at AT TIME ZONE $3::text)::date AS date,
+       count(*)::int AS count
+FROM activities
+WHERE user_id = $1 AND type = 'review'
+  AND occurred_at >= $2::timestamptz
+  AND NOT COALESCE(payload->'payload'->'review'->'user'->>'login'
+                   = payload->'payload'->'pull_request'->'user'->>'login', false)
+GROUP BY 1
+ORDER BY 1;
+
+-- name: ListReviewStateCounts :many
+SELECT COALESCE(lower(payload->'payload'->'review'->>'state'), '')::text AS state,
+       count(*)::int AS count
+FROM activities
+WHERE user_id = $1 AND type = 'review'
+  AND occurred_at >= $2::timestamptz
+  AND NOT COALESCE(payload->'payload'->'review'->'user'->>'login'
+                   = payload->'payload'->'pull_request'->'user'->>'login', false)
+GROUP BY 1;
+
+-- name: GetReviewTurnaround :one
+-- Median time from review request to review, over the reviews whose
+-- request time is known. median_seconds is 0 when reviews is 0.
+SELECT count(*)::int AS reviews,
+       COALESCE(percentile_cont(0.5) WITHIN GROUP (
+           ORDER BY extract(epoch FROM occurred_at - (payload->'payload'->>'requested_at')::timestamptz)), 0)::float8 AS median_seconds
+FROM activities
+WHERE user_id = $1 AND type = 'review'
+  AND occurred_at >= $2::timestamptz
+  AND payload->'payload'->>'requested_at' IS NOT NULL
+  AND NOT COALESCE(payload->'payload'->'review'->'user'->>'login'
+                   = payload->'payload'->'pull_request'->'user'->>'login', false);
+
+-- name: ListTopReviewedRepos :many
+SELECT (payload->>'repo')::text AS name,
+       count(*)::int AS count
+FROM activities
+WHERE user_id = $1 AND type = 'review'
+  AND occurred_at >= $2::timestamptz
+  AND payload->>'repo' IS NOT NULL
+  AND NOT COALESCE(payload->'payload'->'review'->'user'->>'login'
+                   = payload->'payload'->'pull_request'->'user'->>'login', false)
+GROUP BY 1
+ORDER BY count DESC, name
+LIMIT 10;
+
+-- name: ListTopReviewedAuthors :many
+-- Authors are only known for reviews that have been enriched.
+SELECT (payload->'payload'->'pull_request'->'user'->>'login')::text AS name,
+       count(*)::int AS count
+FROM activities
+WHERE user_id = $1 AND type = 'review'
+  AND occurred_at >= $2::timestamptz
+  AND payload->'payload'->'pull_request'->'user'->>'login' IS NOT NULL
+  AND NOT COALESCE(payload->'payload'->'review'->'user'->>'login'
+                   = payload->'payload'->'pull_request'->'user'->>'login', false)
+GROUP BY 1
+ORDER BY count DESC, name
+LIMIT 10;
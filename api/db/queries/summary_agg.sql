@@ -2,7 +2,10 @@
 SELECT DATE_TRUNC('week', date)::date AS period,
        COALESCE(SUM(total_commits), 0)::int AS total_commits,
        COALESCE(SUM(total_prs), 0)::int AS total_prs,
-       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes
+       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes,
+       COALESCE(SUM(lines_added), 0)::int AS lines_added,
+       COALESCE(SUM(lines_removed), 0)::int AS lines_removed,
+       COALESCE(SUM(total_reviews), 0)::int AS total_reviews
 FROM daily_summaries
 WHERE user_id = $1
   AND date >= CURRENT_DATE - ($2::int * 7)
@@ -13,7 +16,10 @@ ORDER BY period;
 SELECT DATE_TRUNC('month', date)::date AS period,
        COALESCE(SUM(total_commits), 0)::int AS total_commits,
        COALESCE(SUM(total_prs), 0)::int AS total_prs,
-       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes
+       COALESCE(SUM(coding_minutes), 0)::int AS coding_minutes,
+       COALESCE(SUM(lines_added), 0)::int AS lines_added,
+       COALESCE(SUM(lines_removed), 0)::int AS lines_removed,
+       COALESCE(SUM(total_reviews), 0)::int AS total_reviews
 FROM daily_summaries
 WHERE user_id = $1
   AND date >= CURRENT_DATE - ($2::int * 30)
@@ -26,3 +32,14 @@ FROM daily_summaries
 WHERE user_id = $1
   AND date >= CURRENT_DATE - $2::int
 ORDER BY date;
+
+-- name: ListLanguageTotals :many
+SELECT (l->>'name')::text AS name,
+       SUM((l->>'weight')::numeric)::float8 AS weight
+FROM daily_summaries ds,
+     jsonb_array_elements(COALESCE(ds.top_languages, '[]'::jsonb)) AS l
+WHERE ds.user_id = $1
+  AND ds.date >= $2::date
+  AND ds.date <= $3::date
+GROUP BY l->>'name'
+ORDER BY weight DESC;
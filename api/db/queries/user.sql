@@ -1,15 +1,15 @@
 -- name: CreateUser :one
 INSERT INTO users (email, name, password)
 VALUES ($1, $2, $3)
-RETURNING id, email, name, avatar_url, created_at, updated_at;
+RETURNING id, email, name, avatar_url, created_at, updated_at, timezone;
 
 -- name: GetUserByEmail :one
-SELECT id, email, name, avatar_url, password, created_at, updated_at
+SELECT id, email, name, avatar_url, password, created_at, updated_at, timezone
 FROM users
 WHERE email = $1;
 
 -- name: GetUserByID :one
-SELECT id, email, name, avatar_url, created_at, updated_at
+SELECT id, email, name, avatar_url, created_at, updated_at, timezone
 FROM users
 WHERE id = $1;
 
@@ -17,4 +17,30 @@ WHERE id = $1;
 UPDATE users
 SET name = $2, avatar_url = $3, updated_at = now()
 WHERE id = $1
-RETURNING id, email, name, avatar_url, created_at, updated_at;
+RETURNING id, email, name, avatar_url, created_at, updated_at, timezone;
+
+-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, email, name, avatar_url, created_at, updated_at, timezone;
+
+-- name: GetUserTimezone :one
+SELECT timezone FROM users WHERE id = $1;
+
+-- name: ListActiveUserTimezones :many
+SELECT u.id, u.timezone
+FROM users u
+WHERE EXISTS (SELECT 1 FROM activities a WHERE a.user_id = u.id)
+ORDER BY u.id;
+
+-- name: ListUsersByEmails :many
+-- Matches case-insensitively; pass lowercased emails.
+SELECT id, lower(email)::text AS email
+FROM users
+WHERE lower(email) = ANY($1::text[]);
+
+-- name: GetUserEmailsDigest :one
+-- Changes whenever a user is added or an email changes.
+SELECT md5(coalesce(string_agg(lower(email), ',' ORDER BY lower(email)), ''))::text AS digest
+FROM users;
@@ -6,7 +6,9 @@ import (
 
 	"github.com/labstack/echo/v5"
 
+	"github.com/ethanwang/devpulse/api/internal/apperror"
 	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+	"github.com/ethanwang/devpulse/api/internal/validate"
 )
 
 type Handler struct {
@@ -20,6 +22,75 @@ func NewHandler(svc *Service) *Handler {
 func (h *Handler) RegisterRoutes(g *echo.Group) {
 	g.GET("/activities", h.List)
 	g.GET("/activities/top-repos", h.TopRepos)
+	g.GET("/activities/types", h.Types)
+	g.PUT("/activities/types", h.UpdateTypes)
+}
+
+// RegisterIngestRoutes mounts the ingestion routes. The caller is
+// responsible for applying JWT or API key middleware to the group.
+func (h *Handler) RegisterIngestRoutes(g *echo.Group) {
+	g.POST("/activities/batch", h.Batch)
+}
+
+// Batch ingests activities from scripts and tools. Invalid items are
+// reported in the results without failing the rest of the batch.
+func (h *Handler) Batch(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req BatchRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	resp, err := h.svc.Batch(c.Request().Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) Types(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.Types(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateTypes sets which activity types are left off the dashboard.
+func (h *Handler) UpdateTypes(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req UpdateTypesRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	resp, err := h.svc.SetHiddenTypes(c.Request().Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (h *Handler) TopRepos(c *echo.Context) error {
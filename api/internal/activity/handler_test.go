@@ -1,12 +1,18 @@
 package activity
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
 )
 
 func TestList_MissingAuth(t *testing.T) {
@@ -30,3 +36,56 @@ func TestTopRepos_MissingAuth(t *testing.T) {
 	err := h.TopRepos(c)
 	assert.Error(t, err)
 }
+
+func TestBatch_Empty(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/activities/batch", strings.NewReader(`{"activities":[]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(mw.ContextKeyUserID, int64(1))
+
+	h := NewHandler(nil)
+	err := h.Batch(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+func TestBatch_ReportsInvalidItems(t *testing.T) {
+	body := `{"activities":[{"source":"ci","type":"deploy","externalId":"run-1"}]}`
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/activities/batch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(mw.ContextKeyUserID, int64(1))
+
+	h := NewHandler(NewService(nil, nil, nil))
+	require.NoError(t, h.Batch(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp BatchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Invalid)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, StatusInvalid, resp.Results[0].Status)
+	assert.NotEmpty(t, resp.Results[0].Error)
+}
+
+func TestUpdateTypes_MissingHidden(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/api/activities/types", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(mw.ContextKeyUserID, int64(1))
+
+	h := NewHandler(nil)
+	err := h.UpdateTypes(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
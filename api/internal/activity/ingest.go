@@ -0,0 +1,144 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/bitbucket"
+	"github.com/ethanwang/devpulse/api/internal/gitea"
+	"github.com/ethanwang/devpulse/api/internal/github"
+	"github.com/ethanwang/devpulse/api/internal/gitlab"
+	"github.com/ethanwang/devpulse/api/internal/heartbeat"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+	"github.com/ethanwang/devpulse/api/internal/validate"
+	"github.com/ethanwang/devpulse/api/internal/wakatime"
+)
+
+// maxFutureSkew is how far ahead of the server clock an activity may be
+// dated, to allow for clients with a drifting clock.
+const maxFutureSkew = 24 * time.Hour
+
+// reservedSources are the sources the built-in providers store activities
+// under. Ingested activities can't use them, so they can't collide with or
+// impersonate synced ones. The git hook agent's "git" source stays open.
+var reservedSources = map[string]bool{
+	bitbucket.ProviderName:        true,
+	gitea.ProviderName:            true,
+	github.ProviderName:           true,
+	github.EnterpriseProviderName: true,
+	gitlab.ProviderName:           true,
+	heartbeat.SourceName:          true,
+	wakatime.ProviderName:         true,
+}
+
+// Batch item statuses.
+const (
+	StatusCreated   = "created"
+	StatusDuplicate = "duplicate"
+	StatusInvalid   = "invalid"
+)
+
+// BatchRequest holds up to 500 activities.
+type BatchRequest struct {
+	Activities []IngestActivity `json:"activities" validate:"required,min=1,max=500"`
+}
+
+// IngestActivity is an activity reported by a script or tool. Pushes,
+// pull requests and coding time only count toward daily summaries when
+// their payload has the shape the built-in providers store.
+type IngestActivity struct {
+	Source     string          `json:"source" validate:"required,max=50"`
+	Type       string          `json:"type" validate:"required,max=50"`
+	OccurredAt time.Time       `json:"occurredAt" validate:"required"`
+	ExternalID string          `json:"externalId" validate:"required,max=255"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// ItemResult reports what happened to one activity of a batch, by its
+// position in the request.
+type ItemResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Created    int          `json:"created"`
+	Duplicates int          `json:"duplicates"`
+	Invalid    int          `json:"invalid"`
+	Results    []ItemResult `json:"results"`
+}
+
+// Batch stores the activities that pass validation. Activities whose
+// (source, externalId) the user already has are reported as duplicates, so
+// a client can safely resend a batch it isn't sure was received.
+func (s *Service) Batch(ctx context.Context, userID int64, req BatchRequest) (*BatchResponse, error) {
+	resp := &BatchResponse{Results: make([]ItemResult, 0, len(req.Activities))}
+	var inserted []time.Time
+	now := time.Now()
+	for i, item := range req.Activities {
+		payload, err := checkItem(item, now)
+		if err != nil {
+			resp.Invalid++
+			resp.Results = append(resp.Results, ItemResult{Index: i, Status: StatusInvalid, Error: err.Error()})
+			continue
+		}
+
+		n, err := s.q.InsertActivity(ctx, dbgen.InsertActivityParams{
+			UserID:     userID,
+			Source:     item.Source,
+			Type:       item.Type,
+			Payload:    payload,
+			OccurredAt: pgtype.Timestamptz{Time: item.OccurredAt, Valid: true},
+			ExternalID: pgtype.Text{String: item.ExternalID, Valid: true},
+		})
+		if err != nil {
+			return nil, apperror.Internalf("insert activity: %w", err)
+		}
+		if n == 0 {
+			resp.Duplicates++
+			resp.Results = append(resp.Results, ItemResult{Index: i, Status: StatusDuplicate})
+			continue
+		}
+		resp.Created++
+		resp.Results = append(resp.Results, ItemResult{Index: i, Status: StatusCreated})
+		inserted = append(inserted, item.OccurredAt)
+	}
+
+	if err := summary.Reaggregate(ctx, s.q, s.river, userID, inserted); err != nil {
+		// The activities are stored; a resend would only find duplicates
+		// and not reschedule either, so don't fail the request.
+		slog.Error("enqueue reaggregation failed", "user_id", userID, "error", err)
+	}
+	return resp, nil
+}
+
+// checkItem validates one activity and returns its payload, defaulting to
+// an empty object.
+func checkItem(item IngestActivity, now time.Time) (json.RawMessage, error) {
+	if err := validate.Struct(item); err != nil {
+		return nil, err
+	}
+	if reservedSources[item.Source] {
+		return nil, apperror.BadRequest("source " + item.Source + " is reserved for a built-in provider")
+	}
+	if item.OccurredAt.After(now.Add(maxFutureSkew)) {
+		return nil, apperror.BadRequest("occurredAt is in the future")
+	}
+
+	payload := bytes.TrimSpace(item.Payload)
+	if len(payload) == 0 || bytes.Equal(payload, []byte("null")) {
+		return json.RawMessage(`{}`), nil
+	}
+	if payload[0] != '{' {
+		return nil, apperror.BadRequest("payload must be a JSON object")
+	}
+	return json.RawMessage(payload), nil
+}
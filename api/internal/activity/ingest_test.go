@@ -0,0 +1,55 @@
+package activity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckItem(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	valid := IngestActivity{
+		Source:     "ci",
+		Type:       "deploy",
+		OccurredAt: now.Add(-time.Hour),
+		ExternalID: "run-1",
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(a *IngestActivity)
+		wantPayload string
+		wantErr     bool
+	}{
+		{name: "missing payload defaults to object", modify: func(a *IngestActivity) {}, wantPayload: `{}`},
+		{name: "null payload", modify: func(a *IngestActivity) { a.Payload = json.RawMessage(`null`) }, wantPayload: `{}`},
+		{name: "object payload", modify: func(a *IngestActivity) { a.Payload = json.RawMessage(` {"repo":"o/r"} `) }, wantPayload: `{"repo":"o/r"}`},
+		{name: "array payload", modify: func(a *IngestActivity) { a.Payload = json.RawMessage(`[1]`) }, wantErr: true},
+		{name: "missing source", modify: func(a *IngestActivity) { a.Source = "" }, wantErr: true},
+		{name: "built-in provider source", modify: func(a *IngestActivity) { a.Source = "github" }, wantErr: true},
+		{name: "heartbeat source", modify: func(a *IngestActivity) { a.Source = "heartbeat" }, wantErr: true},
+		{name: "git agent source", modify: func(a *IngestActivity) { a.Source = "git" }, wantPayload: `{}`},
+		{name: "missing external id", modify: func(a *IngestActivity) { a.ExternalID = "" }, wantErr: true},
+		{name: "missing time", modify: func(a *IngestActivity) { a.OccurredAt = time.Time{} }, wantErr: true},
+		{name: "slightly ahead", modify: func(a *IngestActivity) { a.OccurredAt = now.Add(time.Hour) }, wantPayload: `{}`},
+		{name: "far future", modify: func(a *IngestActivity) { a.OccurredAt = now.Add(48 * time.Hour) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := valid
+			tt.modify(&item)
+
+			payload, err := checkItem(item, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wantPayload, string(payload))
+		})
+	}
+}
@@ -3,10 +3,16 @@ package activity
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
 	dbgen "github.com/ethanwang/devpulse/api/db/generated"
 	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/summary"
 )
 
 type ActivityResponse struct {
@@ -25,11 +31,17 @@ type ListResponse struct {
 }
 
 type Service struct {
-	q *dbgen.Queries
+	q     *dbgen.Queries
+	river *riverlib.Client[pgx.Tx]
+	types []string
 }
 
-func NewService(q *dbgen.Queries) *Service {
-	return &Service{q: q}
+// NewService creates the activity service. The River client is used to
+// re-aggregate the days that ingested activities land on. types lists the
+// activity types the enabled providers produce, so users can hide a type
+// before they have any activities of it.
+func NewService(q *dbgen.Queries, river *riverlib.Client[pgx.Tx], types []string) *Service {
+	return &Service{q: q, river: river, types: types}
 }
 
 func (s *Service) List(ctx context.Context, userID int64, page, perPage int, source string) (*ListResponse, error) {
@@ -80,6 +92,7 @@ func (s *Service) List(ctx context.Context, userID int64, page, perPage int, sou
 
 type RepoStats struct {
 	Name       string `json:"name"`
+	Source     string `json:"source"`
 	Count      int    `json:"count"`
 	LastActive string `json:"lastActive"`
 }
@@ -88,14 +101,28 @@ type TopReposResponse struct {
 	Repos []RepoStats `json:"repos"`
 }
 
+// TopRepos ranks the user's repos by their activities over the last days,
+// counted from the start of the local day, optionally for one source.
 func (s *Service) TopRepos(ctx context.Context, userID int64, days int, source string) (*TopReposResponse, error) {
 	if days < 1 || days > 365 {
 		days = 30
 	}
 
+	tz, err := s.q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("user not found")
+		}
+		return nil, apperror.Internalf("get timezone: %w", err)
+	}
+	loc := summary.LoadLocation(tz)
+	now := time.Now().In(loc)
+	since := time.Date(now.Year(), now.Month(), now.Day()-days, 0, 0, 0, 0, loc)
+
 	rows, err := s.q.ListTopRepos(ctx, dbgen.ListTopReposParams{
 		UserID:  userID,
-		Column2: int32(days),
+		Column2: pgtype.Timestamptz{Time: since, Valid: true},
+		Column3: source,
 	})
 	if err != nil {
 		return nil, apperror.Internalf("list top repos: %w", err)
@@ -103,15 +130,11 @@ func (s *Service) TopRepos(ctx context.Context, userID int64, days int, source s
 
 	repos := make([]RepoStats, 0, len(rows))
 	for _, r := range rows {
-		name, _ := r.Name.(string)
-		lastActive := ""
-		if t, ok := r.LastActive.(time.Time); ok {
-			lastActive = t.Format(time.DateOnly)
-		}
 		repos = append(repos, RepoStats{
-			Name:       name,
+			Name:       r.Name,
+			Source:     r.Source,
 			Count:      int(r.Count),
-			LastActive: lastActive,
+			LastActive: r.LastActive.Time.In(loc).Format(time.DateOnly),
 		})
 	}
 
@@ -0,0 +1,124 @@
+package activity
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/backfill"
+)
+
+// rebuildDays bounds how far back stored summaries are rebuilt when the
+// hidden types change. Older days keep their counts.
+const rebuildDays = 366
+
+// TypeSetting reports how many activities of a type the user has and
+// whether the type is left off their dashboard.
+type TypeSetting struct {
+	Type   string `json:"type"`
+	Count  int64  `json:"count"`
+	Hidden bool   `json:"hidden"`
+}
+
+type TypesResponse struct {
+	Types []TypeSetting `json:"types"`
+}
+
+// UpdateTypesRequest replaces the set of hidden activity types. An empty
+// list shows every type.
+type UpdateTypesRequest struct {
+	Hidden []string `json:"hidden" validate:"required,max=50,dive,required,max=50"`
+}
+
+// Types lists the activity types the enabled providers produce plus any
+// other types the user has activities of, such as ingested ones.
+func (s *Service) Types(ctx context.Context, userID int64) (*TypesResponse, error) {
+	counts, err := s.q.ListActivityTypeCounts(ctx, userID)
+	if err != nil {
+		return nil, apperror.Internalf("count activity types: %w", err)
+	}
+	hidden, err := s.q.ListHiddenActivityTypes(ctx, userID)
+	if err != nil {
+		return nil, apperror.Internalf("list hidden activity types: %w", err)
+	}
+	return typeSettings(s.types, counts, hidden), nil
+}
+
+// SetHiddenTypes replaces the activity types left off the user's dashboard.
+// The activity feed and top repositories change right away; stored daily
+// summaries are rebuilt by a backfill.
+func (s *Service) SetHiddenTypes(ctx context.Context, userID int64, req UpdateTypesRequest) (*TypesResponse, error) {
+	before, err := s.q.ListHiddenActivityTypes(ctx, userID)
+	if err != nil {
+		return nil, apperror.Internalf("list hidden activity types: %w", err)
+	}
+	slices.Sort(before)
+
+	hidden := slices.Clone(req.Hidden)
+	slices.Sort(hidden)
+	hidden = slices.Compact(hidden)
+
+	if err := s.q.SetHiddenActivityTypes(ctx, dbgen.SetHiddenActivityTypesParams{
+		UserID:  userID,
+		Column2: hidden,
+	}); err != nil {
+		return nil, apperror.Internalf("set hidden activity types: %w", err)
+	}
+
+	if !slices.Equal(before, hidden) {
+		if err := s.rebuildSummaries(ctx, userID); err != nil {
+			return nil, apperror.Internalf("rebuild summaries: %w", err)
+		}
+	}
+	return s.Types(ctx, userID)
+}
+
+// rebuildSummaries backfills the user's stored summaries, up to
+// rebuildDays before the latest one.
+func (s *Service) rebuildSummaries(ctx context.Context, userID int64) error {
+	r, err := s.q.GetSummaryDateRange(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !r.FirstDate.Valid {
+		return nil
+	}
+	start := r.FirstDate.Time
+	if floor := r.LastDate.Time.AddDate(0, 0, -(rebuildDays - 1)); start.Before(floor) {
+		start = floor
+	}
+	_, err = backfill.Enqueue(ctx, s.q, s.river, userID, start, r.LastDate.Time)
+	return err
+}
+
+// typeSettings merges the known types, the user's counts and their hidden
+// types into one list sorted by type.
+func typeSettings(known []string, counts []dbgen.ListActivityTypeCountsRow, hidden []string) *TypesResponse {
+	byType := make(map[string]*TypeSetting)
+	setting := func(t string) *TypeSetting {
+		if byType[t] == nil {
+			byType[t] = &TypeSetting{Type: t}
+		}
+		return byType[t]
+	}
+	for _, t := range known {
+		setting(t)
+	}
+	for _, c := range counts {
+		setting(c.Type).Count = c.Count
+	}
+	for _, t := range hidden {
+		setting(t).Hidden = true
+	}
+
+	types := make([]TypeSetting, 0, len(byType))
+	for _, ts := range byType {
+		types = append(types, *ts)
+	}
+	slices.SortFunc(types, func(a, b TypeSetting) int {
+		return cmp.Compare(a.Type, b.Type)
+	})
+	return &TypesResponse{Types: types}
+}
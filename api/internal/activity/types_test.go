@@ -0,0 +1,34 @@
+package activity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+func TestTypeSettings(t *testing.T) {
+	known := []string{"coding", "push", "star"}
+	counts := []dbgen.ListActivityTypeCountsRow{
+		{Type: "deploy", Count: 4},
+		{Type: "push", Count: 12},
+	}
+	hidden := []string{"star", "wiki"}
+
+	resp := typeSettings(known, counts, hidden)
+
+	assert.Equal(t, []TypeSetting{
+		{Type: "coding"},
+		{Type: "deploy", Count: 4},
+		{Type: "push", Count: 12},
+		{Type: "star", Hidden: true},
+		{Type: "wiki", Hidden: true},
+	}, resp.Types)
+}
+
+func TestTypeSettings_Empty(t *testing.T) {
+	resp := typeSettings(nil, nil, nil)
+	assert.NotNil(t, resp.Types)
+	assert.Empty(t, resp.Types)
+}
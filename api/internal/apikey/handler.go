@@ -0,0 +1,82 @@
+package apikey
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+	"github.com/ethanwang/devpulse/api/internal/validate"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterRoutes mounts the key management routes. The caller is
+// responsible for applying JWT middleware to the group.
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/api-keys", h.List)
+	g.POST("/api-keys", h.Create)
+	g.DELETE("/api-keys/:id", h.Delete)
+}
+
+func (h *Handler) List(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.List(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) Create(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req CreateRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	resp, err := h.svc.Create(c.Request().Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *Handler) Delete(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("invalid api key id")
+	}
+
+	if err := h.svc.Delete(c.Request().Context(), userID, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
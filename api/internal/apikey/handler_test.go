@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+)
+
+func TestCreate_MissingName(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/api-keys", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(mw.ContextKeyUserID, int64(1))
+
+	h := NewHandler(nil)
+	err := h.Create(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+func TestDelete_InvalidID(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/api-keys/abc", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(mw.ContextKeyUserID, int64(1))
+	c.SetPathValues(echo.PathValues{{Name: "id", Value: "abc"}})
+
+	h := NewHandler(nil)
+	err := h.Delete(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
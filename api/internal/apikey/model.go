@@ -0,0 +1,26 @@
+package apikey
+
+import "time"
+
+type CreateRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// KeyInfo describes a key without revealing it.
+type KeyInfo struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateResponse carries the new key. It is the only time the key is shown.
+type CreateResponse struct {
+	KeyInfo
+	Key string `json:"key"`
+}
+
+type ListResponse struct {
+	Keys []KeyInfo `json:"keys"`
+}
@@ -0,0 +1,117 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+)
+
+// prefixLen is how much of a key is kept in clear to tell keys apart.
+const prefixLen = 8
+
+type Service struct {
+	q *dbgen.Queries
+}
+
+func NewService(q *dbgen.Queries) *Service {
+	return &Service{q: q}
+}
+
+// Create generates a new key for the user. Only its hash is stored.
+func (s *Service) Create(ctx context.Context, userID int64, req CreateRequest) (*CreateResponse, error) {
+	key, err := generateKey()
+	if err != nil {
+		return nil, apperror.Internalf("generate api key: %w", err)
+	}
+
+	row, err := s.q.CreateAPIKey(ctx, dbgen.CreateAPIKeyParams{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  key[:prefixLen],
+		KeyHash: hashKey(key),
+	})
+	if err != nil {
+		return nil, apperror.Internalf("create api key: %w", err)
+	}
+
+	return &CreateResponse{KeyInfo: toKeyInfo(row), Key: key}, nil
+}
+
+func (s *Service) List(ctx context.Context, userID int64) (*ListResponse, error) {
+	rows, err := s.q.ListAPIKeysByUser(ctx, userID)
+	if err != nil {
+		return nil, apperror.Internalf("list api keys: %w", err)
+	}
+
+	keys := make([]KeyInfo, 0, len(rows))
+	for _, r := range rows {
+		keys = append(keys, toKeyInfo(r))
+	}
+	return &ListResponse{Keys: keys}, nil
+}
+
+// Delete revokes one of the user's keys.
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	n, err := s.q.DeleteAPIKey(ctx, dbgen.DeleteAPIKeyParams{ID: id, UserID: userID})
+	if err != nil {
+		return apperror.Internalf("delete api key: %w", err)
+	}
+	if n == 0 {
+		return apperror.NotFound("api key not found")
+	}
+	return nil
+}
+
+// Authenticate returns the ID of the user who owns key.
+func (s *Service) Authenticate(ctx context.Context, key string) (int64, error) {
+	userID, err := s.q.TouchAPIKey(ctx, hashKey(key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, apperror.Unauthorized("invalid api key")
+	}
+	if err != nil {
+		return 0, apperror.Internalf("look up api key: %w", err)
+	}
+	return userID, nil
+}
+
+// generateKey returns a random key formatted as a version 4 UUID, the only
+// format WakaTime clients accept in their api_key setting.
+func generateKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32]), nil
+}
+
+// hashKey hashes a key for storage. UUIDs are case-insensitive, so the key
+// is lowercased first.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(key))))
+	return hex.EncodeToString(sum[:])
+}
+
+func toKeyInfo(r dbgen.ApiKey) KeyInfo {
+	info := KeyInfo{
+		ID:        r.ID,
+		Name:      r.Name,
+		Prefix:    r.Prefix,
+		CreatedAt: r.CreatedAt.Time,
+	}
+	if r.LastUsedAt.Valid {
+		info.LastUsedAt = &r.LastUsedAt.Time
+	}
+	return info
+}
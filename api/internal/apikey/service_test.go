@@ -0,0 +1,32 @@
+package apikey
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateKey(t *testing.T) {
+	// The format wakatime-cli validates api_key against.
+	uuid4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	a, err := generateKey()
+	require.NoError(t, err)
+	b, err := generateKey()
+	require.NoError(t, err)
+
+	assert.Regexp(t, uuid4, a)
+	assert.NotEqual(t, a, b)
+}
+
+func TestHashKey(t *testing.T) {
+	key := "9b2f4c1e-3d5a-4b6c-8d7e-0f1a2b3c4d5e"
+
+	assert.Len(t, hashKey(key), 64)
+	assert.Equal(t, hashKey(key), hashKey(strings.ToUpper(key)))
+	assert.Equal(t, hashKey(key), hashKey(" "+key+"\n"))
+	assert.NotEqual(t, hashKey(key), hashKey("0b2f4c1e-3d5a-4b6c-8d7e-0f1a2b3c4d5e"))
+}
@@ -25,6 +25,10 @@ func Unauthorized(detail string) *AppError {
 	return &AppError{Code: http.StatusUnauthorized, Title: "Unauthorized", Detail: detail}
 }
 
+func Forbidden(detail string) *AppError {
+	return &AppError{Code: http.StatusForbidden, Title: "Forbidden", Detail: detail}
+}
+
 func Conflict(detail string) *AppError {
 	return &AppError{Code: http.StatusConflict, Title: "Conflict", Detail: detail}
 }
@@ -26,6 +26,7 @@ func (h *Handler) RegisterPublicRoutes(api *echo.Group) {
 // The caller is responsible for applying JWT middleware to the group.
 func (h *Handler) RegisterProtectedRoutes(api *echo.Group) {
 	api.GET("/me", h.Me)
+	api.PUT("/me/timezone", h.UpdateTimezone)
 }
 
 func (h *Handler) Register(c *echo.Context) error {
@@ -72,3 +73,24 @@ func (h *Handler) Me(c *echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, user)
 }
+
+func (h *Handler) UpdateTimezone(c *echo.Context) error {
+	userID, ok := c.Get("userID").(int64)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "not authenticated"})
+	}
+
+	var req UpdateTimezoneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	user, err := h.svc.SetTimezone(c.Request().Context(), userID, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, user)
+}
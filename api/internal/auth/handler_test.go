@@ -109,3 +109,33 @@ func TestLogin_MissingPassword(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "Password")
 }
+
+func TestUpdateTimezone_MissingAuth(t *testing.T) {
+	e := setupEcho()
+	body := `{"timezone":"Asia/Shanghai"}`
+	req := httptest.NewRequest(http.MethodPut, "/api/me/timezone", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := auth.NewHandler(nil)
+	_ = h.UpdateTimezone(c)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestUpdateTimezone_Invalid(t *testing.T) {
+	e := setupEcho()
+	body := `{"timezone":"Mars/Olympus_Mons"}`
+	req := httptest.NewRequest(http.MethodPut, "/api/me/timezone", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("userID", int64(1))
+
+	h := auth.NewHandler(nil)
+	err := h.UpdateTimezone(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "IANA time zone")
+}
@@ -15,6 +15,10 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"required,timezone"`
+}
+
 // Response DTOs
 
 type UserResponse struct {
@@ -22,6 +26,7 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	AvatarURL *string   `json:"avatarUrl,omitempty"`
+	Timezone  string    `json:"timezone"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
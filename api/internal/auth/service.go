@@ -75,13 +75,30 @@ func (s *Service) GetMe(ctx context.Context, userID int64) (*UserResponse, error
 	return toUserResponseFromGetByID(row), nil
 }
 
+// SetTimezone stores the user's IANA timezone. It applies to days
+// aggregated from now on; existing summaries keep their buckets.
+func (s *Service) SetTimezone(ctx context.Context, userID int64, req UpdateTimezoneRequest) (*UserResponse, error) {
+	row, err := s.q.UpdateUserTimezone(ctx, dbgen.UpdateUserTimezoneParams{
+		ID:       userID,
+		Timezone: req.Timezone,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("user not found")
+		}
+		return nil, apperror.Internalf("update timezone: %w", err)
+	}
+	return toUserResponseFromUpdateTimezone(row), nil
+}
+
 // Converters: sqlc row types → response DTOs
 
 func toUserResponse(row dbgen.CreateUserRow) *UserResponse {
 	resp := &UserResponse{
-		ID:    row.ID,
-		Email: row.Email,
-		Name:  row.Name,
+		ID:       row.ID,
+		Email:    row.Email,
+		Name:     row.Name,
+		Timezone: row.Timezone,
 	}
 	if row.AvatarUrl.Valid {
 		resp.AvatarURL = &row.AvatarUrl.String
@@ -97,9 +114,10 @@ func toUserResponse(row dbgen.CreateUserRow) *UserResponse {
 
 func toUserResponseFromFull(u dbgen.User) *UserResponse {
 	resp := &UserResponse{
-		ID:    u.ID,
-		Email: u.Email,
-		Name:  u.Name,
+		ID:       u.ID,
+		Email:    u.Email,
+		Name:     u.Name,
+		Timezone: u.Timezone,
 	}
 	if u.AvatarUrl.Valid {
 		resp.AvatarURL = &u.AvatarUrl.String
@@ -115,9 +133,29 @@ func toUserResponseFromFull(u dbgen.User) *UserResponse {
 
 func toUserResponseFromGetByID(row dbgen.GetUserByIDRow) *UserResponse {
 	resp := &UserResponse{
-		ID:    row.ID,
-		Email: row.Email,
-		Name:  row.Name,
+		ID:       row.ID,
+		Email:    row.Email,
+		Name:     row.Name,
+		Timezone: row.Timezone,
+	}
+	if row.AvatarUrl.Valid {
+		resp.AvatarURL = &row.AvatarUrl.String
+	}
+	if row.CreatedAt.Valid {
+		resp.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		resp.UpdatedAt = row.UpdatedAt.Time
+	}
+	return resp
+}
+
+func toUserResponseFromUpdateTimezone(row dbgen.UpdateUserTimezoneRow) *UserResponse {
+	resp := &UserResponse{
+		ID:       row.ID,
+		Email:    row.Email,
+		Name:     row.Name,
+		Timezone: row.Timezone,
 	}
 	if row.AvatarUrl.Valid {
 		resp.AvatarURL = &row.AvatarUrl.String
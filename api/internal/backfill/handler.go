@@ -0,0 +1,57 @@
+package backfill
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/validate"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterRoutes mounts the backfill routes. The caller is responsible for
+// applying JWT and admin middleware to the group.
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.POST("/summaries/backfill", h.Create)
+	g.GET("/summaries/backfill/:id", h.Get)
+}
+
+func (h *Handler) Create(c *echo.Context) error {
+	var req CreateRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	resp, err := h.svc.Create(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+func (h *Handler) Get(c *echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.BadRequest("invalid backfill id")
+	}
+
+	resp, err := h.svc.Get(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
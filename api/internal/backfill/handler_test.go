@@ -0,0 +1,61 @@
+package backfill
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+)
+
+func TestCreate_MissingUserID(t *testing.T) {
+	e := echo.New()
+	body := `{"from":"2026-01-01","to":"2026-01-31"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/summaries/backfill", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(nil)
+	err := h.Create(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+func TestCreate_InvalidRange(t *testing.T) {
+	e := echo.New()
+	body := `{"userId":1,"from":"2026-02-01","to":"2026-01-01"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/summaries/backfill", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(NewService(nil, nil))
+	err := h.Create(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+func TestGet_InvalidID(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/summaries/backfill/abc", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPathValues(echo.PathValues{{Name: "id", Value: "abc"}})
+
+	h := NewHandler(nil)
+	err := h.Get(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
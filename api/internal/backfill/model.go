@@ -0,0 +1,34 @@
+package backfill
+
+import "time"
+
+// Backfill statuses.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// maxDays bounds a single backfill. Longer rebuilds can be split into
+// several requests.
+const maxDays = 3 * 366
+
+type CreateRequest struct {
+	UserID int64  `json:"userId" validate:"required"`
+	From   string `json:"from" validate:"required"`
+	To     string `json:"to" validate:"required"`
+}
+
+type Response struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"userId"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Status    string    `json:"status"`
+	TotalDays int32     `json:"totalDays"`
+	DoneDays  int32     `json:"doneDays"`
+	Error     *string   `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,129 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+)
+
+type Service struct {
+	q     *dbgen.Queries
+	river *riverlib.Client[pgx.Tx]
+}
+
+func NewService(q *dbgen.Queries, river *riverlib.Client[pgx.Tx]) *Service {
+	return &Service{q: q, river: river}
+}
+
+// Create records a backfill for the user's local days from..to (inclusive,
+// YYYY-MM-DD) and enqueues the job that runs it.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (*Response, error) {
+	start, end, err := ParseRange(req.From, req.To)
+	if err != nil {
+		return nil, apperror.BadRequest(err.Error())
+	}
+
+	if _, err := s.q.GetUserByID(ctx, req.UserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("user not found")
+		}
+		return nil, apperror.Internalf("get user: %w", err)
+	}
+
+	row, err := Enqueue(ctx, s.q, s.river, req.UserID, start, end)
+	if err != nil {
+		return nil, apperror.Internalf("enqueue backfill: %w", err)
+	}
+	return toResponse(row), nil
+}
+
+// Get returns a backfill's progress.
+func (s *Service) Get(ctx context.Context, id int64) (*Response, error) {
+	row, err := s.q.GetSummaryBackfill(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("backfill not found")
+		}
+		return nil, apperror.Internalf("get backfill: %w", err)
+	}
+	return toResponse(row), nil
+}
+
+// ParseRange parses an inclusive YYYY-MM-DD date range and checks its length.
+func ParseRange(from, to string) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.DateOnly, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("from must be a date in YYYY-MM-DD format")
+	}
+	end, err := time.Parse(time.DateOnly, to)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("to must be a date in YYYY-MM-DD format")
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	if dayCount(start, end) > maxDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("range must not exceed %d days", maxDays)
+	}
+	return start, end, nil
+}
+
+// Enqueue creates the progress row for a backfill of start..end and inserts
+// its job. It is shared by the admin endpoint, the backfill command and
+// other jobs that import history.
+func Enqueue(ctx context.Context, q *dbgen.Queries, client *riverlib.Client[pgx.Tx], userID int64, start, end time.Time) (dbgen.SummaryBackfill, error) {
+	row, err := q.CreateSummaryBackfill(ctx, dbgen.CreateSummaryBackfillParams{
+		UserID:    userID,
+		StartDate: pgtype.Date{Time: start, Valid: true},
+		EndDate:   pgtype.Date{Time: end, Valid: true},
+		TotalDays: int32(dayCount(start, end)),
+	})
+	if err != nil {
+		return dbgen.SummaryBackfill{}, fmt.Errorf("create backfill: %w", err)
+	}
+
+	if _, err := client.Insert(ctx, Args{BackfillID: row.ID}, nil); err != nil {
+		_ = q.UpdateSummaryBackfillProgress(ctx, dbgen.UpdateSummaryBackfillProgressParams{
+			ID:     row.ID,
+			Status: StatusFailed,
+			Error:  pgtype.Text{String: "could not enqueue job", Valid: true},
+		})
+		return dbgen.SummaryBackfill{}, fmt.Errorf("insert job: %w", err)
+	}
+	return row, nil
+}
+
+// dayCount returns the number of calendar days in start..end inclusive.
+func dayCount(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+func toResponse(row dbgen.SummaryBackfill) *Response {
+	resp := &Response{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		From:      row.StartDate.Time.Format(time.DateOnly),
+		To:        row.EndDate.Time.Format(time.DateOnly),
+		Status:    row.Status,
+		TotalDays: row.TotalDays,
+		DoneDays:  row.DoneDays,
+	}
+	if row.Error.Valid {
+		resp.Error = &row.Error.String
+	}
+	if row.CreatedAt.Valid {
+		resp.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		resp.UpdatedAt = row.UpdatedAt.Time
+	}
+	return resp
+}
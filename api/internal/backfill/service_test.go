@@ -0,0 +1,47 @@
+package backfill
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	start, end, err := ParseRange("2026-01-01", "2026-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), end)
+	assert.Equal(t, 31, dayCount(start, end))
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		wantErr  string
+	}{
+		{name: "bad from", from: "01/01/2026", to: "2026-01-31", wantErr: "from must be a date"},
+		{name: "bad to", from: "2026-01-01", to: "soon", wantErr: "to must be a date"},
+		{name: "reversed", from: "2026-02-01", to: "2026-01-01", wantErr: "must not be after"},
+		{name: "too long", from: "2020-01-01", to: "2026-01-01", wantErr: "must not exceed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseRange(tt.from, tt.to)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDayCount_SingleDay(t *testing.T) {
+	day := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 1, dayCount(day, day))
+}
+
+func TestArgs_Kind(t *testing.T) {
+	assert.Equal(t, "summary_backfill", Args{}.Kind())
+}
@@ -0,0 +1,109 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+)
+
+// jobTimeout bounds one attempt. Progress is saved per day, so a retry
+// resumes where the previous attempt stopped.
+const jobTimeout = time.Hour
+
+// Args are the arguments for the summary backfill job.
+type Args struct {
+	BackfillID int64 `json:"backfill_id"`
+}
+
+func (Args) Kind() string { return "summary_backfill" }
+
+// Worker re-aggregates every day of a backfill's range from activities.
+type Worker struct {
+	riverlib.WorkerDefaults[Args]
+	q          *dbgen.Queries
+	aggregator *summary.Aggregator
+}
+
+func NewWorker(q *dbgen.Queries, aggregator *summary.Aggregator) *Worker {
+	return &Worker{q: q, aggregator: aggregator}
+}
+
+func (w *Worker) Timeout(job *riverlib.Job[Args]) time.Duration {
+	return jobTimeout
+}
+
+func (w *Worker) Work(ctx context.Context, job *riverlib.Job[Args]) error {
+	bf, err := w.q.GetSummaryBackfill(ctx, job.Args.BackfillID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("backfill %d not found", job.Args.BackfillID))
+	}
+	if err != nil {
+		return err
+	}
+	if bf.Status == StatusCompleted {
+		return nil
+	}
+
+	tz, err := w.q.GetUserTimezone(ctx, bf.UserID)
+	if err != nil {
+		return w.fail(ctx, job, bf.ID, bf.DoneDays, err)
+	}
+	loc := summary.LoadLocation(tz)
+
+	done := bf.DoneDays
+	if err := w.progress(ctx, bf.ID, done, StatusRunning); err != nil {
+		return err
+	}
+
+	for day := bf.StartDate.Time.AddDate(0, 0, int(done)); !day.After(bf.EndDate.Time); day = day.AddDate(0, 0, 1) {
+		if err := w.aggregator.AggregateDay(ctx, bf.UserID, day.Format(time.DateOnly), loc); err != nil {
+			return w.fail(ctx, job, bf.ID, done, fmt.Errorf("aggregate %s: %w", day.Format(time.DateOnly), err))
+		}
+		done++
+		if err := w.progress(ctx, bf.ID, done, StatusRunning); err != nil {
+			return err
+		}
+	}
+
+	if err := w.progress(ctx, bf.ID, done, StatusCompleted); err != nil {
+		return err
+	}
+	slog.Info("summary backfill complete", "backfill_id", bf.ID, "user_id", bf.UserID, "days", done)
+	return nil
+}
+
+func (w *Worker) progress(ctx context.Context, id int64, done int32, status string) error {
+	return w.q.UpdateSummaryBackfillProgress(ctx, dbgen.UpdateSummaryBackfillProgressParams{
+		ID:       id,
+		DoneDays: done,
+		Status:   status,
+	})
+}
+
+// fail records the error and returns it so River retries. The status only
+// becomes failed once no attempts are left.
+func (w *Worker) fail(ctx context.Context, job *riverlib.Job[Args], id int64, done int32, cause error) error {
+	status := StatusRunning
+	if job.Attempt >= job.MaxAttempts {
+		status = StatusFailed
+	}
+	err := w.q.UpdateSummaryBackfillProgress(ctx, dbgen.UpdateSummaryBackfillProgressParams{
+		ID:       id,
+		DoneDays: done,
+		Status:   status,
+		Error:    pgtype.Text{String: cause.Error(), Valid: true},
+	})
+	if err != nil {
+		slog.Error("record backfill failure", "backfill_id", id, "error", err)
+	}
+	return cause
+}
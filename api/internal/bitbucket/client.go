@@ -0,0 +1,196 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxPages bounds each paginated listing within one sync.
+const maxPages = 5
+
+// rateLimitWait is how long to back off from a 429 that doesn't say when
+// to retry.
+const rateLimitWait = time.Minute
+
+// Client calls the Bitbucket Cloud REST API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string // for testing; defaults to "https://api.bitbucket.org/2.0"
+}
+
+// NewClient creates a new Bitbucket API client.
+// If httpClient is nil, a default http.Client is used.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{httpClient: httpClient, baseURL: "https://api.bitbucket.org/2.0"}
+}
+
+// RateLimitError is returned when Bitbucket rejects a request with 429.
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("bitbucket rate limit exceeded, retry at %s", e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// Wait returns how long to wait from now before retrying, at least one second.
+func (e *RateLimitError) Wait(now time.Time) time.Duration {
+	return max(e.ResetAt.Sub(now), time.Second)
+}
+
+// APIError is returned for any other non-success response.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("bitbucket api returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("bitbucket api returned %d: %s", e.StatusCode, e.Message)
+}
+
+// FetchUser returns the account the token belongs to.
+func (c *Client) FetchUser(ctx context.Context, token string) (*User, error) {
+	var user User
+	if err := c.get(ctx, token, c.baseURL+"/user", &user); err != nil {
+		return nil, fmt.Errorf("fetch user: %w", err)
+	}
+	return &user, nil
+}
+
+// FetchRepositories returns the repositories the user contributes to that
+// were updated after since, most recently updated first.
+func (c *Client) FetchRepositories(ctx context.Context, token string, since time.Time) ([]Repository, error) {
+	reqURL := c.baseURL + "/repositories?role=contributor&sort=-updated_on&pagelen=100"
+	return paginate(ctx, c, token, reqURL, func(r Repository) bool {
+		return r.UpdatedOn.After(since)
+	})
+}
+
+// FetchCommits returns the commits on all branches of repo ("workspace/slug")
+// dated after since. Bitbucket lists commits in topological order, where an
+// old commit of one branch can come before new commits of another, so all
+// maxPages pages are read and filtered by date rather than stopping early.
+func (c *Client) FetchCommits(ctx context.Context, token, repo string, since time.Time) ([]Commit, error) {
+	reqURL := fmt.Sprintf("%s/repositories/%s/commits?pagelen=100", c.baseURL, repo)
+	all, err := paginate(ctx, c, token, reqURL, func(Commit) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, cm := range all {
+		if cm.Date.After(since) {
+			commits = append(commits, cm)
+		}
+	}
+	return commits, nil
+}
+
+// FetchBranches returns the branches of repo with the commits they point at.
+func (c *Client) FetchBranches(ctx context.Context, token, repo string) ([]Branch, error) {
+	reqURL := fmt.Sprintf("%s/repositories/%s/refs/branches?pagelen=100", c.baseURL, repo)
+	return paginate(ctx, c, token, reqURL, func(Branch) bool { return true })
+}
+
+// FetchStateChangedAt returns when pull request id of repo last changed to
+// state, from its activity log, which lists the newest entries first.
+// Returns the zero time if the change isn't within maxPages pages.
+func (c *Client) FetchStateChangedAt(ctx context.Context, token, repo string, id int, state string) (time.Time, error) {
+	reqURL := fmt.Sprintf("%s/repositories/%s/pullrequests/%d/activity?pagelen=50", c.baseURL, repo, id)
+	var changedAt time.Time
+	_, err := paginate(ctx, c, token, reqURL, func(a PullRequestActivity) bool {
+		if a.Update != nil && a.Update.State == state {
+			changedAt = a.Update.Date
+			return false
+		}
+		return true
+	})
+	return changedAt, err
+}
+
+// FetchPullRequests returns the pull requests of repo in any state that
+// were updated after since, most recently updated first.
+func (c *Client) FetchPullRequests(ctx context.Context, token, repo string, since time.Time) ([]PullRequest, error) {
+	query := url.Values{
+		"state":   {"OPEN", "MERGED", "DECLINED", "SUPERSEDED"},
+		"sort":    {"-updated_on"},
+		"pagelen": {"50"},
+	}
+	reqURL := fmt.Sprintf("%s/repositories/%s/pullrequests?%s", c.baseURL, repo, query.Encode())
+	return paginate(ctx, c, token, reqURL, func(pr PullRequest) bool {
+		return pr.UpdatedOn.After(since)
+	})
+}
+
+// paginate follows "next" links until maxPages, a short page, or the first
+// value for which keep returns false. Listings are sorted newest first, so
+// everything after that value is older too.
+func paginate[T any](ctx context.Context, c *Client, token, reqURL string, keep func(T) bool) ([]T, error) {
+	var values []T
+	for n := 0; n < maxPages && reqURL != ""; n++ {
+		var p page[T]
+		if err := c.get(ctx, token, reqURL, &p); err != nil {
+			return nil, err
+		}
+		for _, v := range p.Values {
+			if !keep(v) {
+				return values, nil
+			}
+			values = append(values, v)
+		}
+		reqURL = p.Next
+	}
+	return values, nil
+}
+
+func (c *Client) get(ctx context.Context, token, reqURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, time.Now())
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// responseError converts a non-success response into a *RateLimitError or
+// *APIError.
+func responseError(resp *http.Response, now time.Time) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resetAt := now.Add(rateLimitWait)
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			resetAt = now.Add(time.Duration(secs) * time.Second)
+		}
+		return &RateLimitError{ResetAt: resetAt}
+	}
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(raw, &body)
+	return &APIError{StatusCode: resp.StatusCode, Message: body.Error.Message}
+}
@@ -0,0 +1,90 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient creates a Client pointed at the given test server URL.
+func newTestClient(serverURL string) *Client {
+	c := NewClient(nil)
+	c.baseURL = serverURL
+	return c
+}
+
+func TestFetchCommits_FollowsNextAndFiltersBySince(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repositories/ws/repo/commits", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(page[Commit]{Values: []Commit{
+				{Hash: "c", Date: since.Add(time.Hour)},
+				{Hash: "old", Date: since.Add(-time.Hour)},
+				// Topological order: a newer commit of another branch
+				// after older ones is still returned.
+				{Hash: "d", Date: since.Add(30 * time.Minute)},
+				{Hash: "older", Date: since.Add(-2 * time.Hour)},
+			}})
+			return
+		}
+		json.NewEncoder(w).Encode(page[Commit]{
+			Values: []Commit{{Hash: "a", Date: since.Add(3 * time.Hour)}, {Hash: "b", Date: since.Add(2 * time.Hour)}},
+			Next:   srv.URL + "/repositories/ws/repo/commits?page=2",
+		})
+	}))
+	defer srv.Close()
+
+	commits, err := newTestClient(srv.URL).FetchCommits(context.Background(), "test-token", "ws/repo", since)
+
+	require.NoError(t, err)
+	require.Len(t, commits, 4)
+	assert.Equal(t, "c", commits[2].Hash)
+	assert.Equal(t, "d", commits[3].Hash)
+}
+
+func TestFetchPullRequests_AllStates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.ElementsMatch(t, []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"}, r.URL.Query()["state"])
+		json.NewEncoder(w).Encode(page[PullRequest]{})
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).FetchPullRequests(context.Background(), "test-token", "ws/repo", time.Time{})
+	require.NoError(t, err)
+}
+
+func TestFetchUser_RateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).FetchUser(context.Background(), "test-token")
+
+	var rl *RateLimitError
+	require.ErrorAs(t, err, &rl)
+	assert.Equal(t, rateLimitWait, rl.Wait(time.Now()).Round(time.Second))
+}
+
+func TestFetchUser_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"type":"error","error":{"message":"Access token expired."}}`))
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).FetchUser(context.Background(), "test-token")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "Access token expired.", apiErr.Message)
+}
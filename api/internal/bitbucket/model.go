@@ -0,0 +1,68 @@
+package bitbucket
+
+import "time"
+
+// page is the envelope of paginated Bitbucket Cloud responses.
+// https://developer.atlassian.com/cloud/bitbucket/rest/intro/#pagination
+type page[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+}
+
+// User is the authenticated account.
+type User struct {
+	AccountID   string `json:"account_id"`
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"display_name"`
+}
+
+// Repository is a repository the user has a role in.
+type Repository struct {
+	FullName  string    `json:"full_name"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+// Commit is a commit on any branch of a repository.
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+	Author  Author    `json:"author"`
+}
+
+// Author is a commit author. User is nil when the author's email isn't
+// linked to a Bitbucket account.
+type Author struct {
+	Raw  string `json:"raw"`
+	User *User  `json:"user"`
+}
+
+// PullRequest is a pull request in a repository.
+type PullRequest struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Author    User      `json:"author"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+// Branch is a branch of a repository and the commit it points at.
+type Branch struct {
+	Name   string `json:"name"`
+	Target struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+// PullRequestActivity is an entry of a pull request's activity log. Update
+// is set for entries that changed the pull request, including its state.
+type PullRequestActivity struct {
+	Update *PullRequestUpdate `json:"update"`
+}
+
+// PullRequestUpdate is the state of a pull request after an update.
+type PullRequestUpdate struct {
+	State string    `json:"state"`
+	Date  time.Time `json:"date"`
+}
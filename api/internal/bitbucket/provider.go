@@ -0,0 +1,253 @@
+package bitbucket
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+// ProviderName is the data source and activity source key for Bitbucket.
+const ProviderName = "bitbucket"
+
+// initialSyncDays is how far back the first sync reads.
+const initialSyncDays = 30
+
+// syncOverlap re-reads this much before the previous sync. Commit dates
+// are author dates, so a commit pushed after the last sync can be dated
+// before it; re-reading is free since rows dedupe on external ID.
+const syncOverlap = 72 * time.Hour
+
+// maxRepos bounds how many recently updated repositories a sync reads.
+const maxRepos = 30
+
+// Provider syncs Bitbucket Cloud. Bitbucket has no user activity feed, so
+// each sync reads the commits and pull requests of recently updated
+// repositories the user contributes to and keeps the user's own.
+type Provider struct {
+	client *Client
+	oauth  provider.OAuthConfig
+}
+
+// NewProvider creates the Bitbucket provider for the given OAuth consumer.
+// Scopes are set on the consumer, not requested in the flow.
+func NewProvider(client *Client, creds provider.Credentials) *Provider {
+	return &Provider{
+		client: client,
+		oauth: provider.OAuthConfig{
+			Credentials:  creds,
+			AuthorizeURL: "https://bitbucket.org/site/oauth2/authorize",
+			TokenURL:     "https://bitbucket.org/site/oauth2/access_token",
+			BasicAuth:    true,
+		},
+	}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+func (p *Provider) OAuthConfig() provider.OAuthConfig { return p.oauth }
+
+func (p *Provider) ExchangeCode(ctx context.Context, code, verifier string) (*provider.Token, error) {
+	return provider.ExchangeCode(ctx, p.oauth, code, verifier)
+}
+
+// RefreshToken renews an access token. Bitbucket access tokens expire
+// after two hours.
+func (p *Provider) RefreshToken(ctx context.Context, refreshToken string) (*provider.Token, error) {
+	return provider.RefreshToken(ctx, p.oauth, refreshToken)
+}
+
+// syncCursor is the state a sync passes on to the next one.
+type syncCursor struct {
+	// Since is when the sync started.
+	Since time.Time `json:"since"`
+	// Heads fingerprints each repository's branch heads, so the commits of
+	// repositories nobody pushed to since aren't read again.
+	Heads map[string]string `json:"heads,omitempty"`
+}
+
+// parseCursor decodes a cursor. Cursors from before branch heads were
+// tracked are just the RFC 3339 start time.
+func parseCursor(cursor string) syncCursor {
+	var c syncCursor
+	if err := json.Unmarshal([]byte(cursor), &c); err == nil {
+		return c
+	}
+	if since, err := time.Parse(time.RFC3339, cursor); err == nil {
+		c.Since = since
+	}
+	return c
+}
+
+// Fetch reads activity since the previous fetch. Rate limit responses are
+// returned as *RateLimitError.
+func (p *Provider) Fetch(ctx context.Context, token, cursor string) (*provider.FetchResult, error) {
+	now := time.Now().UTC()
+	prev := parseCursor(cursor)
+	since := now.AddDate(0, 0, -initialSyncDays)
+	if !prev.Since.IsZero() {
+		since = prev.Since.Add(-syncOverlap)
+	}
+	next := syncCursor{Since: now, Heads: make(map[string]string, len(prev.Heads))}
+	for repo, heads := range prev.Heads {
+		next.Heads[repo] = heads
+	}
+
+	user, err := p.client.FetchUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := p.client.FetchRepositories(ctx, token, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(repos) > maxRepos {
+		repos = repos[:maxRepos]
+	}
+
+	result := &provider.FetchResult{}
+	for _, repo := range repos {
+		branches, err := p.client.FetchBranches(ctx, token, repo.FullName)
+		if err != nil {
+			return nil, err
+		}
+		heads := headsFingerprint(branches)
+		if heads != prev.Heads[repo.FullName] {
+			commits, err := p.client.FetchCommits(ctx, token, repo.FullName, since)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range commits {
+				if c.Author.User != nil && c.Author.User.AccountID == user.AccountID {
+					result.Activities = append(result.Activities, commitActivity(repo.FullName, c))
+				}
+			}
+			next.Heads[repo.FullName] = heads
+		}
+
+		prs, err := p.client.FetchPullRequests(ctx, token, repo.FullName, since)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if pr.Author.AccountID != user.AccountID {
+				continue
+			}
+			closedAt, err := p.closedAt(ctx, token, repo.FullName, pr, prev.Since)
+			if err != nil {
+				return nil, err
+			}
+			result.Activities = append(result.Activities, pullRequestActivities(repo.FullName, pr, since, closedAt)...)
+		}
+	}
+
+	raw, _ := json.Marshal(next)
+	result.Cursor = string(raw)
+	return result, nil
+}
+
+// closedAt returns when a merged or declined pull request was closed, or
+// the zero time if it's open or was already closed by the previous sync.
+// Bitbucket doesn't report the close time on the pull request, so it is
+// read from the activity log; if the log doesn't have it, the last update
+// time stands in.
+func (p *Provider) closedAt(ctx context.Context, token, repo string, pr PullRequest, lastSync time.Time) (time.Time, error) {
+	if pr.State == "OPEN" || (!lastSync.IsZero() && !pr.UpdatedOn.After(lastSync)) {
+		return time.Time{}, nil
+	}
+	at, err := p.client.FetchStateChangedAt(ctx, token, repo, pr.ID, pr.State)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if at.IsZero() {
+		at = pr.UpdatedOn
+	}
+	return at, nil
+}
+
+// headsFingerprint identifies a repository's set of branch heads.
+func headsFingerprint(branches []Branch) string {
+	refs := make([]string, 0, len(branches))
+	for _, b := range branches {
+		refs = append(refs, b.Name+"="+b.Target.Hash)
+	}
+	sort.Strings(refs)
+	sum := sha256.Sum256([]byte(strings.Join(refs, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (p *Provider) SupportedTypes() []string {
+	return []string{"pull_request", "push"}
+}
+
+// commitActivity stores a commit as a single-commit push in GitHub's
+// payload shape, so the daily aggregation counts it by SHA.
+func commitActivity(repo string, c Commit) provider.Activity {
+	payload, _ := json.Marshal(map[string]any{
+		"repo": repo,
+		"payload": map[string]any{
+			"head":          c.Hash,
+			"size":          1,
+			"distinct_size": 1,
+			"commits":       []map[string]string{{"sha": c.Hash, "message": c.Message}},
+		},
+	})
+	return provider.Activity{
+		Type:       "push",
+		OccurredAt: c.Date,
+		ExternalID: fmt.Sprintf("push:%s:%s", repo, c.Hash),
+		Payload:    payload,
+	}
+}
+
+// pullRequestActivities returns the opened event of a pull request created
+// after since, and its merged or declined event dated closedAt unless
+// that is zero.
+func pullRequestActivities(repo string, pr PullRequest, since, closedAt time.Time) []provider.Activity {
+	var acts []provider.Activity
+	if pr.CreatedOn.After(since) {
+		acts = append(acts, pullRequestActivity(repo, pr, "opened", "open", false, pr.CreatedOn))
+	}
+	if closedAt.IsZero() {
+		return acts
+	}
+	switch pr.State {
+	case "MERGED":
+		acts = append(acts, pullRequestActivity(repo, pr, "merged", "closed", true, closedAt))
+	case "DECLINED", "SUPERSEDED":
+		acts = append(acts, pullRequestActivity(repo, pr, "declined", "closed", false, closedAt))
+	}
+	return acts
+}
+
+func pullRequestActivity(repo string, pr PullRequest, event, state string, merged bool, at time.Time) provider.Activity {
+	action := "opened"
+	if state == "closed" {
+		action = "closed"
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"repo": repo,
+		"payload": map[string]any{
+			"action": action,
+			"number": pr.ID,
+			"pull_request": map[string]any{
+				"number": pr.ID,
+				"title":  pr.Title,
+				"state":  state,
+				"merged": merged,
+			},
+		},
+	})
+	return provider.Activity{
+		Type:       "pull_request",
+		OccurredAt: at,
+		ExternalID: fmt.Sprintf("pull_request:%s:%d:%s", repo, pr.ID, event),
+		Payload:    payload,
+	}
+}
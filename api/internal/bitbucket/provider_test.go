@@ -0,0 +1,129 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+func TestProvider_Fetch(t *testing.T) {
+	now := time.Now().UTC()
+	me := User{AccountID: "me"}
+	other := User{AccountID: "other"}
+	mergedAt := now.Add(-90 * time.Minute)
+	commitReads := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/ws/repo/refs/branches":
+			b := Branch{Name: "main"}
+			b.Target.Hash = "mine"
+			json.NewEncoder(w).Encode(page[Branch]{Values: []Branch{b}})
+		case "/repositories/ws/repo/pullrequests/3/activity":
+			// Newest first: a comment after the merge moved updated_on
+			json.NewEncoder(w).Encode(page[PullRequestActivity]{Values: []PullRequestActivity{
+				{},
+				{Update: &PullRequestUpdate{State: "MERGED", Date: mergedAt}},
+				{Update: &PullRequestUpdate{State: "OPEN", Date: now.Add(-2 * time.Hour)}},
+			}})
+		case "/user":
+			json.NewEncoder(w).Encode(me)
+		case "/repositories":
+			assert.Equal(t, "contributor", r.URL.Query().Get("role"))
+			json.NewEncoder(w).Encode(page[Repository]{Values: []Repository{{FullName: "ws/repo", UpdatedOn: now}}})
+		case "/repositories/ws/repo/commits":
+			commitReads++
+			json.NewEncoder(w).Encode(page[Commit]{Values: []Commit{
+				{Hash: "mine", Date: now.Add(-time.Hour), Message: "Fix", Author: Author{User: &me}},
+				{Hash: "theirs", Date: now.Add(-time.Hour), Author: Author{User: &other}},
+				{Hash: "unlinked", Date: now.Add(-time.Hour), Author: Author{Raw: "Someone <s@example.com>"}},
+			}})
+		case "/repositories/ws/repo/pullrequests":
+			json.NewEncoder(w).Encode(page[PullRequest]{Values: []PullRequest{
+				{ID: 3, Title: "Add x", State: "MERGED", Author: me, CreatedOn: now.Add(-2 * time.Hour), UpdatedOn: now.Add(-time.Hour)},
+				{ID: 4, State: "OPEN", Author: other, CreatedOn: now.Add(-time.Hour), UpdatedOn: now.Add(-time.Hour)},
+			}})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewProvider(newTestClient(srv.URL), provider.Credentials{})
+	result, err := p.Fetch(context.Background(), "test-token", "")
+
+	require.NoError(t, err)
+	ids := make([]string, 0, len(result.Activities))
+	for _, act := range result.Activities {
+		ids = append(ids, act.ExternalID)
+	}
+	assert.Equal(t, []string{
+		"push:ws/repo:mine",
+		"pull_request:ws/repo:3:opened",
+		"pull_request:ws/repo:3:merged",
+	}, ids)
+	assert.Equal(t, mergedAt, result.Activities[2].OccurredAt)
+
+	// Unchanged branch heads skip the commits, and the merge is already
+	// stored since the pull request hasn't changed since.
+	cursor := parseCursor(result.Cursor)
+	assert.False(t, cursor.Since.IsZero())
+	assert.Contains(t, cursor.Heads, "ws/repo")
+	result, err = p.Fetch(context.Background(), "test-token", result.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, 1, commitReads)
+	require.Len(t, result.Activities, 1)
+	assert.Equal(t, "pull_request:ws/repo:3:opened", result.Activities[0].ExternalID)
+}
+
+func TestParseCursor_Legacy(t *testing.T) {
+	since := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	c := parseCursor(since.Format(time.RFC3339))
+	assert.Equal(t, since, c.Since)
+	assert.Empty(t, c.Heads)
+
+	assert.True(t, parseCursor("").Since.IsZero())
+}
+
+func TestPullRequestActivities_OpenedBeforeSince(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	pr := PullRequest{ID: 5, State: "DECLINED", CreatedOn: since.Add(-24 * time.Hour), UpdatedOn: since.Add(time.Hour)}
+
+	acts := pullRequestActivities("ws/repo", pr, since, since.Add(time.Hour))
+
+	require.Len(t, acts, 1)
+	assert.Equal(t, "pull_request:ws/repo:5:declined", acts[0].ExternalID)
+	var body struct {
+		Payload struct {
+			Action string `json:"action"`
+		} `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(acts[0].Payload, &body))
+	assert.Equal(t, "closed", body.Payload.Action)
+}
+
+func TestCommitActivity(t *testing.T) {
+	c := Commit{Hash: "abc", Date: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Message: "Merge branch 'x'"}
+
+	act := commitActivity("ws/repo", c)
+
+	assert.Equal(t, "push", act.Type)
+	assert.Equal(t, c.Date, act.OccurredAt)
+	assert.JSONEq(t, `{"repo":"ws/repo","payload":{"head":"abc","size":1,"distinct_size":1,
+		"commits":[{"sha":"abc","message":"Merge branch 'x'"}]}}`, string(act.Payload))
+}
+
+func TestProvider_OAuthConfig(t *testing.T) {
+	p := NewProvider(nil, provider.Credentials{})
+	assert.Equal(t, "bitbucket", p.Name())
+	assert.True(t, p.OAuthConfig().BasicAuth)
+	assert.False(t, p.OAuthConfig().PKCE)
+}
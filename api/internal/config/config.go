@@ -2,31 +2,151 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DatabaseURL        string
-	JWTSecret          string
-	Port               string
+	DatabaseURL string
+	JWTSecret   string
+	Port        string
+
+	// GitHubWebURL and GitHubAPIURL locate the deployment behind the
+	// "github" provider, github.com by default. The OAuth app is registered
+	// on GitHubWebURL.
+	GitHubWebURL       string
+	GitHubAPIURL       string
 	GitHubClientID     string
 	GitHubClientSecret string
 	GitHubCallbackURL  string
+
+	// GitHubEnterpriseURL enables the "github_enterprise" provider for a
+	// GitHub Enterprise Server, so users can connect it alongside
+	// github.com. GitHubEnterpriseAPIURL defaults to its /api/v3 endpoint.
+	GitHubEnterpriseURL          string
+	GitHubEnterpriseAPIURL       string
+	GitHubEnterpriseClientID     string
+	GitHubEnterpriseClientSecret string
+	GitHubEnterpriseCallbackURL  string
+
+	WakaTimeClientID     string
+	WakaTimeClientSecret string
+	WakaTimeCallbackURL  string
+
+	// GitLabBaseURL is the GitLab instance to connect to; set it for a
+	// self-hosted GitLab. The provider is enabled once GitLabClientID is set.
+	GitLabBaseURL      string
+	GitLabClientID     string
+	GitLabClientSecret string
+	GitLabCallbackURL  string
+
+	// GiteaBaseURL enables the Gitea/Forgejo provider for that instance.
+	// Users can connect with a personal access token; the OAuth app is
+	// optional.
+	GiteaBaseURL      string
+	GiteaClientID     string
+	GiteaClientSecret string
+	GiteaCallbackURL  string
+
+	// The Bitbucket Cloud provider is enabled once BitbucketClientID is set.
+	BitbucketClientID     string
+	BitbucketClientSecret string
+	BitbucketCallbackURL  string
+
+	// GitScanRepos lists local repositories, or glob patterns of them, that
+	// the server scans for commits. Scanning is off when it's empty.
+	GitScanRepos []string
+
+	// TokenEncryptionKeys is a comma-separated "id:base64key" keyring used to
+	// encrypt data source tokens at rest.
+	TokenEncryptionKeys string
+	TokenPrimaryKeyID   string
+
+	// ExcludeMergeCommits drops commits whose message starts with "Merge "
+	// from daily commit totals.
+	ExcludeMergeCommits bool
+
+	// AdminUserIDs may use the /api/admin endpoints.
+	AdminUserIDs []int64
 }
 
 func Load() *Config {
 	// Best-effort: load .env from project root (api/../.env) and api/.env
 	_ = godotenv.Load("../.env", ".env")
 
-	return &Config{
-		DatabaseURL:        getEnv("DATABASE_URL", "postgres://localhost:5432/devpulse_dev?sslmode=disable"),
-		JWTSecret:          getEnv("JWT_SECRET", "devpulse-dev-secret-change-me"),
-		Port:               getEnv("PORT", "8080"),
+	cfg := &Config{
+		DatabaseURL: getEnv("DATABASE_URL", "postgres://localhost:5432/devpulse_dev?sslmode=disable"),
+		JWTSecret:   getEnv("JWT_SECRET", "devpulse-dev-secret-change-me"),
+		Port:        getEnv("PORT", "8080"),
+
+		GitHubWebURL:       getEnv("GITHUB_WEB_URL", "https://github.com"),
+		GitHubAPIURL:       getEnv("GITHUB_API_URL", "https://api.github.com"),
 		GitHubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
 		GitHubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
 		GitHubCallbackURL:  getEnv("GITHUB_CALLBACK_URL", "http://localhost:3000/auth/github/callback"),
+
+		GitHubEnterpriseURL:          strings.TrimRight(getEnv("GITHUB_ENTERPRISE_URL", ""), "/"),
+		GitHubEnterpriseAPIURL:       getEnv("GITHUB_ENTERPRISE_API_URL", ""),
+		GitHubEnterpriseClientID:     getEnv("GITHUB_ENTERPRISE_CLIENT_ID", ""),
+		GitHubEnterpriseClientSecret: getEnv("GITHUB_ENTERPRISE_CLIENT_SECRET", ""),
+		GitHubEnterpriseCallbackURL:  getEnv("GITHUB_ENTERPRISE_CALLBACK_URL", "http://localhost:3000/auth/github_enterprise/callback"),
+
+		WakaTimeClientID:     getEnv("WAKATIME_CLIENT_ID", ""),
+		WakaTimeClientSecret: getEnv("WAKATIME_CLIENT_SECRET", ""),
+		WakaTimeCallbackURL:  getEnv("WAKATIME_CALLBACK_URL", "http://localhost:3000/auth/wakatime/callback"),
+
+		GitLabBaseURL:      getEnv("GITLAB_BASE_URL", "https://gitlab.com"),
+		GitLabClientID:     getEnv("GITLAB_CLIENT_ID", ""),
+		GitLabClientSecret: getEnv("GITLAB_CLIENT_SECRET", ""),
+		GitLabCallbackURL:  getEnv("GITLAB_CALLBACK_URL", "http://localhost:3000/auth/gitlab/callback"),
+
+		GiteaBaseURL:      getEnv("GITEA_BASE_URL", ""),
+		GiteaClientID:     getEnv("GITEA_CLIENT_ID", ""),
+		GiteaClientSecret: getEnv("GITEA_CLIENT_SECRET", ""),
+		GiteaCallbackURL:  getEnv("GITEA_CALLBACK_URL", "http://localhost:3000/auth/gitea/callback"),
+
+		BitbucketClientID:     getEnv("BITBUCKET_CLIENT_ID", ""),
+		BitbucketClientSecret: getEnv("BITBUCKET_CLIENT_SECRET", ""),
+		BitbucketCallbackURL:  getEnv("BITBUCKET_CALLBACK_URL", "http://localhost:3000/auth/bitbucket/callback"),
+
+		GitScanRepos: parseList(getEnv("GIT_SCAN_REPOS", "")),
+
+		TokenEncryptionKeys: getEnv("TOKEN_ENCRYPTION_KEYS", "dev:ZGV2cHVsc2UtZGV2LXRva2VuLWtleS1jaGFuZ2UtbWU="),
+		TokenPrimaryKeyID:   getEnv("TOKEN_PRIMARY_KEY_ID", ""),
+
+		ExcludeMergeCommits: getEnv("EXCLUDE_MERGE_COMMITS", "false") == "true",
+
+		AdminUserIDs: parseIDs(getEnv("ADMIN_USER_IDS", "")),
+	}
+	if cfg.GitHubEnterpriseURL != "" && cfg.GitHubEnterpriseAPIURL == "" {
+		cfg.GitHubEnterpriseAPIURL = cfg.GitHubEnterpriseURL + "/api/v3"
+	}
+	return cfg
+}
+
+// parseIDs parses a comma-separated list of user IDs, skipping invalid entries.
+func parseIDs(s string) []int64 {
+	var ids []int64
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// parseList splits a comma-separated list, dropping empty entries.
+func parseList(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
 	}
+	return items
 }
 
 func getEnv(key, fallback string) string {
@@ -5,7 +5,9 @@ import (
 
 	"github.com/labstack/echo/v5"
 
+	"github.com/ethanwang/devpulse/api/internal/apperror"
 	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+	"github.com/ethanwang/devpulse/api/internal/validate"
 )
 
 type Handler struct {
@@ -18,6 +20,7 @@ func NewHandler(svc *Service) *Handler {
 
 func (h *Handler) RegisterRoutes(g *echo.Group) {
 	g.GET("/data-sources", h.List)
+	g.PUT("/data-sources/:provider/token", h.ConnectToken)
 }
 
 func (h *Handler) List(c *echo.Context) error {
@@ -33,3 +36,25 @@ func (h *Handler) List(c *echo.Context) error {
 
 	return c.JSON(http.StatusOK, resp)
 }
+
+// ConnectToken connects a provider with a personal access token.
+func (h *Handler) ConnectToken(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req ConnectTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := h.svc.ConnectToken(c.Request().Context(), userID, c.Param("provider"), req); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "connected"})
+}
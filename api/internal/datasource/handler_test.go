@@ -3,10 +3,15 @@ package datasource
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v5"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ethanwang/devpulse/api/internal/gitea"
+	"github.com/ethanwang/devpulse/api/internal/github"
+	"github.com/ethanwang/devpulse/api/internal/provider"
 )
 
 func TestList_MissingAuth(t *testing.T) {
@@ -19,3 +24,70 @@ func TestList_MissingAuth(t *testing.T) {
 	err := h.List(c)
 	assert.Error(t, err)
 }
+
+func newTokenContext(e *echo.Echo, name, body string) *echo.Context {
+	req := httptest.NewRequest(http.MethodPut, "/api/data-sources/"+name+"/token", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetPathValues(echo.PathValues{{Name: "provider", Value: name}})
+	c.Set("userID", int64(42))
+	return c
+}
+
+func TestConnectToken_MissingAuth(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/api/data-sources/gitea/token", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	h := NewHandler(nil)
+	err := h.ConnectToken(c)
+	assert.Error(t, err)
+}
+
+func TestConnectToken_MissingToken(t *testing.T) {
+	h := NewHandler(nil)
+	err := h.ConnectToken(newTokenContext(echo.New(), "gitea", `{}`))
+	assert.Error(t, err)
+}
+
+func TestConnectToken_Unsupported(t *testing.T) {
+	registry := provider.NewRegistry(github.NewProvider(github.ProviderName, "https://github.com", nil, provider.Credentials{}))
+	h := NewHandler(NewService(nil, nil, registry))
+
+	err := h.ConnectToken(newTokenContext(echo.New(), "github", `{"token":"abc"}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not accept personal access tokens")
+
+	err = h.ConnectToken(newTokenContext(echo.New(), "unknown", `{"token":"abc"}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown provider")
+}
+
+func TestConnectToken_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token bad-token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	registry := provider.NewRegistry(gitea.NewProvider(gitea.NewClient(nil, srv.URL), provider.Credentials{}))
+	h := NewHandler(NewService(nil, nil, registry))
+
+	err := h.ConnectToken(newTokenContext(echo.New(), "gitea", `{"token":"bad-token"}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected the token")
+}
+
+func TestConnectToken_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	registry := provider.NewRegistry(gitea.NewProvider(gitea.NewClient(nil, srv.URL), provider.Credentials{}))
+	h := NewHandler(NewService(nil, nil, registry))
+
+	err := h.ConnectToken(newTokenContext(echo.New(), "gitea", `{"token":"abc"}`))
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "rejected the token")
+}
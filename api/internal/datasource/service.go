@@ -2,9 +2,14 @@ package datasource
 
 import (
 	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
 
 	dbgen "github.com/ethanwang/devpulse/api/db/generated"
 	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
 )
 
 type SourceInfo struct {
@@ -18,33 +23,100 @@ type ListResponse struct {
 	Sources []SourceInfo `json:"sources"`
 }
 
+// ConnectTokenRequest connects a provider with a personal access token.
+type ConnectTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 type Service struct {
-	q *dbgen.Queries
+	q         *dbgen.Queries
+	keyring   *tokencrypt.Keyring
+	providers *provider.Registry
 }
 
-func NewService(q *dbgen.Queries) *Service {
-	return &Service{q: q}
+func NewService(q *dbgen.Queries, keyring *tokencrypt.Keyring, providers *provider.Registry) *Service {
+	return &Service{q: q, keyring: keyring, providers: providers}
 }
 
+// List returns every registered provider, connected or not, followed by
+// any connected sources whose provider is no longer enabled.
 func (s *Service) List(ctx context.Context, userID int64) (*ListResponse, error) {
 	rows, err := s.q.ListDataSourcesByUser(ctx, userID)
 	if err != nil {
 		return nil, apperror.Internalf("list data sources: %w", err)
 	}
 
-	sources := make([]SourceInfo, 0, len(rows))
+	return &ListResponse{Sources: listSources(s.providers.Names(), rows)}, nil
+}
+
+func listSources(providers []string, rows []dbgen.ListDataSourcesByUserRow) []SourceInfo {
+	connected := make(map[string]dbgen.ListDataSourcesByUserRow, len(rows))
+	for _, r := range rows {
+		connected[r.Provider] = r
+	}
+
+	sources := make([]SourceInfo, 0, len(providers)+len(rows))
+	for _, name := range providers {
+		r, ok := connected[name]
+		if !ok {
+			sources = append(sources, SourceInfo{Provider: name})
+			continue
+		}
+		sources = append(sources, connectedSource(r))
+		delete(connected, name)
+	}
 	for _, r := range rows {
-		connectedAt := ""
-		if r.CreatedAt.Valid {
-			connectedAt = r.CreatedAt.Time.Format("2006-01-02T15:04:05Z")
+		if _, ok := connected[r.Provider]; ok {
+			sources = append(sources, connectedSource(r))
+		}
+	}
+	return sources
+}
+
+func connectedSource(r dbgen.ListDataSourcesByUserRow) SourceInfo {
+	connectedAt := ""
+	if r.CreatedAt.Valid {
+		connectedAt = r.CreatedAt.Time.Format("2006-01-02T15:04:05Z")
+	}
+	return SourceInfo{
+		ID:          r.ID,
+		Provider:    r.Provider,
+		Connected:   true,
+		ConnectedAt: connectedAt,
+	}
+}
+
+// ConnectToken verifies a personal access token with the provider and
+// stores it as the user's data source, as an alternative to OAuth.
+func (s *Service) ConnectToken(ctx context.Context, userID int64, name string, req ConnectTokenRequest) error {
+	p, ok := s.providers.Get(name)
+	if !ok {
+		return apperror.NotFound("unknown provider: " + name)
+	}
+	verifier, ok := p.(provider.TokenVerifier)
+	if !ok {
+		return apperror.BadRequest(name + " does not accept personal access tokens")
+	}
+	if err := verifier.VerifyToken(ctx, req.Token); err != nil {
+		if errors.Is(err, provider.ErrTokenRejected) {
+			return apperror.BadRequest(name + " rejected the token")
 		}
-		sources = append(sources, SourceInfo{
-			ID:          r.ID,
-			Provider:    r.Provider,
-			Connected:   true,
-			ConnectedAt: connectedAt,
-		})
+		return apperror.Internalf("verify %s token: %w", name, err)
 	}
 
-	return &ListResponse{Sources: sources}, nil
+	accessToken, err := s.keyring.Encrypt([]byte(req.Token))
+	if err != nil {
+		return apperror.Internalf("encrypt %s token: %w", name, err)
+	}
+	_, err = s.q.UpsertDataSource(ctx, dbgen.UpsertDataSourceParams{
+		UserID:       userID,
+		Provider:     name,
+		AccessToken:  accessToken,
+		RefreshToken: nil,
+		ExpiresAt:    pgtype.Timestamptz{},
+	})
+	if err != nil {
+		return apperror.Internalf("save %s token: %w", name, err)
+	}
+	return nil
 }
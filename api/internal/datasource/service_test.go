@@ -0,0 +1,28 @@
+package datasource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+func TestListSources(t *testing.T) {
+	connectedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	rows := []dbgen.ListDataSourcesByUserRow{
+		{ID: 7, Provider: "github", CreatedAt: pgtype.Timestamptz{Time: connectedAt, Valid: true}},
+		{ID: 9, Provider: "legacy", CreatedAt: pgtype.Timestamptz{Time: connectedAt, Valid: true}},
+	}
+
+	sources := listSources([]string{"bitbucket", "github", "wakatime"}, rows)
+
+	assert.Equal(t, []SourceInfo{
+		{Provider: "bitbucket"},
+		{ID: 7, Provider: "github", Connected: true, ConnectedAt: "2026-03-01T10:00:00Z"},
+		{Provider: "wakatime"},
+		{ID: 9, Provider: "legacy", Connected: true, ConnectedAt: "2026-03-01T10:00:00Z"},
+	}, sources)
+}
@@ -0,0 +1,52 @@
+package gitagent
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SourceName is the activity source commits are reported under.
+const SourceName = "git"
+
+// Activity is one item of a POST /api/activities/batch request.
+type Activity struct {
+	Source     string          `json:"source"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	ExternalID string          `json:"externalId"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// CommitActivity reports a commit as a one-commit "push" in the payload
+// shape of GitHub push events. The daily summary counts commits by SHA, so
+// a commit that later arrives from a forge as well is counted once.
+func CommitActivity(c Commit) Activity {
+	commit := map[string]any{
+		"sha":       c.SHA,
+		"message":   c.Message,
+		"author":    map[string]string{"email": c.AuthorEmail},
+		"additions": c.Additions,
+		"deletions": c.Deletions,
+	}
+	push := map[string]any{
+		"head":          c.SHA,
+		"size":          1,
+		"distinct_size": 1,
+		"commits":       []any{commit},
+	}
+	if c.Branch != "" {
+		push["ref"] = "refs/heads/" + c.Branch
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"repo":    c.Repo,
+		"payload": push,
+	})
+
+	return Activity{
+		Source:     SourceName,
+		Type:       "push",
+		OccurredAt: c.CommittedAt.UTC(),
+		ExternalID: c.SHA,
+		Payload:    payload,
+	}
+}
@@ -0,0 +1,112 @@
+package gitagent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBatch matches the server's limit per batch request.
+const maxBatch = 500
+
+// Client uploads activities to the DevPulse batch ingestion API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+// NewClient creates a Client for the configured server. A nil httpClient
+// uses one with a short timeout, since uploads run from git hooks.
+func NewClient(httpClient *http.Client, cfg Config) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(cfg.URL, "/"),
+		apiKey:     cfg.APIKey,
+	}
+}
+
+// Rejected is an activity the server refused as invalid. Resending it
+// would fail again, so it is dropped rather than requeued.
+type Rejected struct {
+	Activity Activity
+	Error    string
+}
+
+// UploadResult summarizes an upload.
+type UploadResult struct {
+	Created    int
+	Duplicates int
+	Rejected   []Rejected
+}
+
+// Upload sends activities in batches of up to maxBatch. Activities the
+// server already has count as duplicates, so a failed upload can safely be
+// retried as a whole.
+func (c *Client) Upload(ctx context.Context, acts []Activity) (*UploadResult, error) {
+	result := &UploadResult{}
+	for start := 0; start < len(acts); start += maxBatch {
+		batch := acts[start:min(start+maxBatch, len(acts))]
+		if err := c.uploadBatch(ctx, batch, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) uploadBatch(ctx context.Context, batch []Activity, result *UploadResult) error {
+	body, err := json.Marshal(map[string]any{"activities": batch})
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/activities/batch", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("upload: server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var out struct {
+		Results []struct {
+			Index  int    `json:"index"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("decode upload response: %w", err)
+	}
+	for _, r := range out.Results {
+		switch r.Status {
+		case "created":
+			result.Created++
+		case "duplicate":
+			result.Duplicates++
+		default:
+			if r.Index >= 0 && r.Index < len(batch) {
+				result.Rejected = append(result.Rejected, Rejected{Activity: batch[r.Index], Error: r.Error})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package gitagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/activities/batch", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var req struct {
+			Activities []Activity `json:"activities"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Activities, 3)
+		assert.Equal(t, "git", req.Activities[0].Source)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[
+			{"index":0,"status":"created"},
+			{"index":1,"status":"duplicate"},
+			{"index":2,"status":"invalid","error":"ExternalID is required"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	acts := []Activity{
+		CommitActivity(Commit{SHA: "aaa", CommittedAt: time.Now()}),
+		CommitActivity(Commit{SHA: "bbb", CommittedAt: time.Now()}),
+		CommitActivity(Commit{SHA: "", CommittedAt: time.Now()}),
+	}
+	client := NewClient(srv.Client(), Config{URL: srv.URL + "/", APIKey: "test-key"})
+	result, err := client.Upload(context.Background(), acts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Duplicates)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, "ExternalID is required", result.Rejected[0].Error)
+}
+
+func TestUpload_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail":"invalid api key"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.Client(), Config{URL: srv.URL, APIKey: "bad"})
+	_, err := client.Upload(context.Background(), []Activity{CommitActivity(Commit{SHA: "aaa", CommittedAt: time.Now()})})
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestCommitActivity(t *testing.T) {
+	act := CommitActivity(Commit{
+		SHA:         "abc123",
+		Repo:        "octocat/hello",
+		Branch:      "main",
+		Message:     "Fix bug",
+		AuthorEmail: "dev@example.com",
+		Additions:   4,
+		Deletions:   1,
+		CommittedAt: time.Date(2026, 3, 10, 9, 30, 0, 0, time.FixedZone("CST", 8*3600)),
+	})
+
+	assert.Equal(t, "git", act.Source)
+	assert.Equal(t, "push", act.Type)
+	assert.Equal(t, "abc123", act.ExternalID)
+	assert.Equal(t, time.Date(2026, 3, 10, 1, 30, 0, 0, time.UTC), act.OccurredAt)
+	assert.JSONEq(t, `{
+		"repo": "octocat/hello",
+		"payload": {
+			"ref": "refs/heads/main",
+			"head": "abc123",
+			"size": 1,
+			"distinct_size": 1,
+			"commits": [{
+				"sha": "abc123",
+				"message": "Fix bug",
+				"author": {"email": "dev@example.com"},
+				"additions": 4,
+				"deletions": 1
+			}]
+		}
+	}`, string(act.Payload))
+}
@@ -0,0 +1,66 @@
+// Package gitagent implements devpulse-agent, which reports commits from
+// local repositories to DevPulse through git hooks.
+package gitagent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config is where and as whom the agent uploads.
+type Config struct {
+	URL    string `json:"url"`
+	APIKey string `json:"apiKey"`
+}
+
+// Dir returns the agent's state directory holding its config and queue.
+// DEVPULSE_AGENT_DIR overrides the default under the user config dir.
+func Dir() (string, error) {
+	if dir := os.Getenv("DEVPULSE_AGENT_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "devpulse"), nil
+}
+
+// LoadConfig reads agent.json from dir. DEVPULSE_URL and DEVPULSE_API_KEY
+// override the stored values.
+func LoadConfig(dir string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(filepath.Join(dir, "agent.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parse agent.json: %w", err)
+		}
+	}
+
+	if v := os.Getenv("DEVPULSE_URL"); v != "" {
+		cfg.URL = v
+	}
+	if v := os.Getenv("DEVPULSE_API_KEY"); v != "" {
+		cfg.APIKey = v
+	}
+	return cfg, nil
+}
+
+// SaveConfig writes agent.json to dir, readable only by the user since it
+// holds the API key.
+func SaveConfig(dir string, cfg Config) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "agent.json"), data, 0o600)
+}
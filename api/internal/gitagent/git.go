@@ -0,0 +1,132 @@
+package gitagent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is the metadata the agent reports for one commit.
+type Commit struct {
+	SHA         string
+	Repo        string
+	Branch      string
+	Message     string
+	AuthorEmail string
+	Additions   int
+	Deletions   int
+	CommittedAt time.Time
+}
+
+// ReadCommit reads rev from the repository containing dir with the git CLI.
+func ReadCommit(ctx context.Context, dir, rev string) (*Commit, error) {
+	// %x00 separators keep the subject's spaces intact.
+	out, err := git(ctx, dir, "show", "-s", "--format=%H%x00%ae%x00%cI%x00%s", rev)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.SplitN(strings.TrimSpace(out), "\x00", 4)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("unexpected git show output %q", out)
+	}
+	committedAt, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("parse commit time: %w", err)
+	}
+
+	c := &Commit{
+		SHA:         fields[0],
+		AuthorEmail: fields[1],
+		CommittedAt: committedAt,
+		Message:     fields[3],
+	}
+
+	// --root diffs a repository's first commit against the empty tree.
+	numstat, err := git(ctx, dir, "diff-tree", "--root", "--no-commit-id", "--numstat", "-r", c.SHA)
+	if err != nil {
+		return nil, err
+	}
+	c.Additions, c.Deletions = parseNumstat(numstat)
+
+	if branch, err := git(ctx, dir, "symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		c.Branch = strings.TrimSpace(branch)
+	}
+
+	toplevel, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	remote, _ := git(ctx, dir, "remote", "get-url", "origin")
+	c.Repo = RepoName(strings.TrimSpace(remote), strings.TrimSpace(toplevel))
+	return c, nil
+}
+
+// HooksDir returns the hooks directory of the repository containing dir,
+// honoring core.hooksPath.
+func HooksDir(ctx context.Context, dir string) (string, error) {
+	out, err := git(ctx, dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(out)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+// parseNumstat sums the lines added and removed in `git diff --numstat`
+// output. Binary files, shown as "-", count as zero.
+func parseNumstat(out string) (additions, deletions int) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		a, _ := strconv.Atoi(fields[0])
+		d, _ := strconv.Atoi(fields[1])
+		additions += a
+		deletions += d
+	}
+	return additions, deletions
+}
+
+// RepoName derives "owner/name" from the origin URL, in either the
+// scp-like "git@host:owner/name.git" or URL form. Repositories without a
+// remote are named after their directory, without a bare repository's
+// ".git" suffix.
+func RepoName(remote, dir string) string {
+	path := remote
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+		if j := strings.Index(path, "/"); j >= 0 {
+			path = path[j+1:]
+		} else {
+			path = ""
+		}
+	} else if i := strings.Index(path, ":"); i >= 0 {
+		path = path[i+1:]
+	}
+	path = strings.Trim(strings.TrimSuffix(strings.TrimSpace(path), ".git"), "/")
+	if path == "" {
+		return strings.TrimSuffix(filepath.Base(dir), ".git")
+	}
+	return path
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,124 @@
+package gitagent
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRepo creates a repository with one commit and returns its directory.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Dev", "GIT_AUTHOR_EMAIL=dev@example.com",
+			"GIT_COMMITTER_NAME=Dev", "GIT_COMMITTER_EMAIL=dev@example.com",
+			"GIT_COMMITTER_DATE=2026-03-10T09:30:00+08:00",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	run("init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	run("add", ".")
+	run("commit", "-q", "-m", "Add main: first commit")
+	run("remote", "add", "origin", "git@github.com:octocat/hello.git")
+	return dir
+}
+
+func TestReadCommit(t *testing.T) {
+	dir := newRepo(t)
+
+	c, err := ReadCommit(context.Background(), dir, "HEAD")
+	require.NoError(t, err)
+
+	assert.Len(t, c.SHA, 40)
+	assert.Equal(t, "octocat/hello", c.Repo)
+	assert.Equal(t, "main", c.Branch)
+	assert.Equal(t, "Add main: first commit", c.Message)
+	assert.Equal(t, "dev@example.com", c.AuthorEmail)
+	assert.Equal(t, 3, c.Additions)
+	assert.Equal(t, 0, c.Deletions)
+	assert.Equal(t, "2026-03-10T01:30:00Z", c.CommittedAt.UTC().Format("2006-01-02T15:04:05Z07:00"))
+}
+
+func TestInstall(t *testing.T) {
+	dir := newRepo(t)
+	ctx := context.Background()
+
+	installed, err := Install(ctx, dir, "/usr/local/bin/devpulse-agent", false)
+	require.NoError(t, err)
+	require.Len(t, installed, 2)
+
+	script, err := os.ReadFile(filepath.Join(dir, ".git", "hooks", "post-commit"))
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "AGENT='/usr/local/bin/devpulse-agent'")
+	info, err := os.Stat(filepath.Join(dir, ".git", "hooks", "pre-push"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0o100)
+
+	// Reinstalling replaces the agent's own hooks.
+	_, err = Install(ctx, dir, "/opt/devpulse-agent", false)
+	require.NoError(t, err)
+
+	// A foreign hook is only replaced with force.
+	foreign := filepath.Join(dir, ".git", "hooks", "pre-push")
+	require.NoError(t, os.WriteFile(foreign, []byte("#!/bin/sh\nmake lint\n"), 0o644))
+	_, err = Install(ctx, dir, "/opt/devpulse-agent", false)
+	assert.ErrorIs(t, err, ErrHookExists)
+
+	_, err = Install(ctx, dir, "/opt/devpulse-agent", true)
+	require.NoError(t, err)
+	script, err = os.ReadFile(foreign)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(script), hookMarker))
+	info, err = os.Stat(foreign)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0o100)
+}
+
+func TestParseNumstat(t *testing.T) {
+	out := "10\t2\tmain.go\n-\t-\tlogo.png\n0\t5\tREADME.md\n"
+	additions, deletions := parseNumstat(out)
+	assert.Equal(t, 10, additions)
+	assert.Equal(t, 7, deletions)
+}
+
+func TestRepoName(t *testing.T) {
+	tests := []struct {
+		remote string
+		dir    string
+		want   string
+	}{
+		{remote: "git@github.com:octocat/hello.git", want: "octocat/hello"},
+		{remote: "https://github.com/octocat/hello.git", want: "octocat/hello"},
+		{remote: "https://gitlab.example.com/group/sub/project", want: "group/sub/project"},
+		{remote: "ssh://git@git.example.com:2222/team/tool.git", want: "team/tool"},
+		{remote: "", want: "scratch"},
+		{remote: "", dir: "/srv/git/tool.git", want: "tool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			dir := tt.dir
+			if dir == "" {
+				dir = "/home/dev/scratch"
+			}
+			assert.Equal(t, tt.want, RepoName(tt.remote, dir))
+		})
+	}
+}
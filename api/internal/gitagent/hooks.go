@@ -0,0 +1,70 @@
+package gitagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies hooks written by the agent, so reinstalling
+// replaces them while foreign hooks are left alone.
+const hookMarker = "# installed by devpulse-agent"
+
+// hooks are the git hooks the agent installs. post-commit queues the new
+// commit; pre-push uploads whatever is queued. Both upload in the
+// background and never fail the git command.
+var hooks = map[string]string{
+	"post-commit": `"$AGENT" record --quiet || true
+("$AGENT" flush --quiet &) >/dev/null 2>&1
+`,
+	"pre-push": `("$AGENT" flush --quiet &) >/dev/null 2>&1
+exit 0
+`,
+}
+
+// ErrHookExists is returned when a hook the agent didn't write is in the
+// way.
+var ErrHookExists = errors.New("hook already exists")
+
+// Install writes the agent's hooks into the repository containing dir.
+// agent is the absolute path of the devpulse-agent executable. Existing
+// hooks from other tools are only replaced when force is set.
+func Install(ctx context.Context, dir, agent string, force bool) ([]string, error) {
+	hooksDir, err := HooksDir(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var installed []string
+	for _, name := range []string{"post-commit", "pre-push"} {
+		path := filepath.Join(hooksDir, name)
+		existing, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return installed, err
+		}
+		if len(existing) > 0 && !strings.Contains(string(existing), hookMarker) && !force {
+			return installed, fmt.Errorf("%s: %w", path, ErrHookExists)
+		}
+
+		script := fmt.Sprintf("#!/bin/sh\n%s\nAGENT=%s\n%s", hookMarker, shellQuote(agent), hooks[name])
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			return installed, err
+		}
+		// WriteFile keeps the mode of a file it overwrites.
+		if err := os.Chmod(path, 0o755); err != nil {
+			return installed, err
+		}
+		installed = append(installed, path)
+	}
+	return installed, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
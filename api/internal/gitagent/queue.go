@@ -0,0 +1,153 @@
+package gitagent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Queue is an append-only file of activities waiting to be uploaded, so
+// commits made offline are sent later. Hooks from several repositories may
+// use it at once: appends are single writes to an O_APPEND file, and a
+// flush first renames the file away so new appends start a fresh one.
+type Queue struct {
+	path string
+}
+
+func NewQueue(dir string) *Queue {
+	return &Queue{path: filepath.Join(dir, "queue.jsonl")}
+}
+
+// Append adds activities to the queue.
+func (q *Queue) Append(acts ...Activity) error {
+	if len(acts) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, a := range acts {
+		line, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(q.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Len returns the number of queued activities.
+func (q *Queue) Len() (int, error) {
+	acts, err := readQueue(q.path)
+	return len(acts), err
+}
+
+// Flush hands the queued activities to upload and removes them once it
+// succeeds. On failure they are put back for the next flush.
+func (q *Queue) Flush(ctx context.Context, upload func(context.Context, []Activity) error) (int, error) {
+	if err := q.mergeLeftovers(); err != nil {
+		return 0, err
+	}
+
+	sending := q.path + ".sending-" + strconv.Itoa(os.Getpid())
+	if err := os.Rename(q.path, sending); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	acts, err := readQueue(sending)
+	if err != nil {
+		return 0, err
+	}
+	if len(acts) > 0 {
+		if err := upload(ctx, acts); err != nil {
+			if requeueErr := q.Append(acts...); requeueErr != nil {
+				return 0, fmt.Errorf("%w (requeue failed, pending activities kept in %s: %v)", err, sending, requeueErr)
+			}
+			os.Remove(sending)
+			return 0, err
+		}
+	}
+	// Another flush may have merged the file back already.
+	if err := os.Remove(sending); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	return len(acts), nil
+}
+
+// mergeLeftovers appends the activities of sending files left behind by a
+// flush that was killed or couldn't requeue back to the queue. A file may
+// also belong to a flush still in progress; its activities are then sent
+// twice, which is harmless since the server reports resends as duplicates.
+func (q *Queue) mergeLeftovers() error {
+	entries, err := os.ReadDir(filepath.Dir(q.path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	prefix := filepath.Base(q.path) + ".sending-"
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		path := filepath.Join(filepath.Dir(q.path), e.Name())
+		acts, err := readQueue(path)
+		if err != nil {
+			return err
+		}
+		if err := q.Append(acts...); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// readQueue reads a queue file, skipping lines a crash left truncated.
+func readQueue(path string) ([]Activity, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var acts []Activity
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var a Activity
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil {
+			continue
+		}
+		acts = append(acts, a)
+	}
+	return acts, scanner.Err()
+}
@@ -0,0 +1,103 @@
+package gitagent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueFlush(t *testing.T) {
+	dir := t.TempDir()
+	q := NewQueue(dir)
+	a := CommitActivity(Commit{SHA: "aaa", Repo: "o/r", CommittedAt: time.Now()})
+	b := CommitActivity(Commit{SHA: "bbb", Repo: "o/r", CommittedAt: time.Now()})
+
+	require.NoError(t, q.Append(a))
+	require.NoError(t, q.Append(b))
+	n, err := q.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// A failed upload keeps everything queued.
+	_, err = q.Flush(context.Background(), func(context.Context, []Activity) error {
+		return errors.New("offline")
+	})
+	require.Error(t, err)
+	n, err = q.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	var sent []string
+	n, err = q.Flush(context.Background(), func(_ context.Context, acts []Activity) error {
+		for _, a := range acts {
+			sent = append(sent, a.ExternalID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.ElementsMatch(t, []string{"aaa", "bbb"}, sent)
+
+	n, err = q.Len()
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestQueueFlush_Empty(t *testing.T) {
+	q := NewQueue(t.TempDir())
+	n, err := q.Flush(context.Background(), func(context.Context, []Activity) error {
+		t.Fatal("upload called for an empty queue")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestQueueFlush_MergesLeftovers(t *testing.T) {
+	dir := t.TempDir()
+	q := NewQueue(dir)
+	require.NoError(t, q.Append(CommitActivity(Commit{SHA: "aaa", CommittedAt: time.Now()})))
+	// A flush killed mid-upload left its activities behind.
+	require.NoError(t, os.Rename(filepath.Join(dir, "queue.jsonl"), filepath.Join(dir, "queue.jsonl.sending-1")))
+	require.NoError(t, q.Append(CommitActivity(Commit{SHA: "bbb", CommittedAt: time.Now()})))
+
+	var sent []string
+	n, err := q.Flush(context.Background(), func(_ context.Context, acts []Activity) error {
+		for _, a := range acts {
+			sent = append(sent, a.ExternalID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.ElementsMatch(t, []string{"aaa", "bbb"}, sent)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestQueue_SkipsTruncatedLines(t *testing.T) {
+	dir := t.TempDir()
+	q := NewQueue(dir)
+	require.NoError(t, q.Append(CommitActivity(Commit{SHA: "aaa", CommittedAt: time.Now()})))
+
+	f, err := os.OpenFile(filepath.Join(dir, "queue.jsonl"), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"source":"git","ty`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	n, err := q.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
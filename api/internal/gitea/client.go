@@ -0,0 +1,106 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxPages bounds how far back a single sync pages through the feed.
+const maxPages = 10
+
+// pageSize is the feed page size; Gitea caps it at its MAX_RESPONSE_ITEMS.
+const pageSize = 50
+
+// Client calls the API of one Gitea or Forgejo instance.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string // instance URL, e.g. "https://codeberg.org"
+}
+
+// NewClient creates an API client for the instance at baseURL.
+// If httpClient is nil, a default http.Client is used.
+func NewClient(httpClient *http.Client, baseURL string) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{httpClient: httpClient, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// APIError is returned for any non-success response.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gitea api returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("gitea api returned %d: %s", e.StatusCode, e.Message)
+}
+
+// FetchUser returns the user the token belongs to.
+func (c *Client) FetchUser(ctx context.Context, token string) (*User, error) {
+	var user User
+	if err := c.get(ctx, token, c.baseURL+"/api/v1/user", &user); err != nil {
+		return nil, fmt.Errorf("fetch user: %w", err)
+	}
+	return &user, nil
+}
+
+// FetchActivities returns the activities performed by username that are
+// newer than sinceID, newest first. With sinceID 0 it returns up to
+// maxPages pages.
+func (c *Client) FetchActivities(ctx context.Context, token, username string, sinceID int64) ([]Activity, error) {
+	var activities []Activity
+	for page := 1; page <= maxPages; page++ {
+		reqURL := fmt.Sprintf("%s/api/v1/users/%s/activities/feeds?only-performed-by=true&limit=%d&page=%d",
+			c.baseURL, url.PathEscape(username), pageSize, page)
+		var batch []Activity
+		if err := c.get(ctx, token, reqURL, &batch); err != nil {
+			return nil, fmt.Errorf("fetch activities page %d: %w", page, err)
+		}
+
+		for _, act := range batch {
+			if act.ID <= sinceID {
+				return activities, nil
+			}
+			activities = append(activities, act)
+		}
+		if len(batch) < pageSize {
+			break // No more pages
+		}
+	}
+	return activities, nil
+}
+
+func (c *Client) get(ctx context.Context, token, reqURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	// Gitea accepts both OAuth and personal access tokens this way.
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Message string `json:"message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		_ = json.Unmarshal(raw, &body)
+		return &APIError{StatusCode: resp.StatusCode, Message: body.Message}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,41 @@
+package gitea
+
+import "time"
+
+// Activity is an entry of a Gitea or Forgejo user activity feed.
+// https://gitea.com/api/swagger#/user/userListActivityFeeds
+type Activity struct {
+	ID        int64     `json:"id"`
+	OpType    string    `json:"op_type"`
+	Repo      *Repo     `json:"repo"`
+	RefName   string    `json:"ref_name"`
+	CommentID int64     `json:"comment_id"`
+	Content   string    `json:"content"`
+	Created   time.Time `json:"created"`
+}
+
+// Repo identifies the repository an activity belongs to.
+type Repo struct {
+	FullName string `json:"full_name"`
+}
+
+// PushContent is the JSON encoded in Content for commit_repo activities.
+// The feed only keeps the latest few commits of a push, so Commits can
+// be shorter than Len.
+type PushContent struct {
+	Commits    []PushCommit `json:"Commits"`
+	HeadCommit *PushCommit  `json:"HeadCommit"`
+	Len        int          `json:"Len"`
+}
+
+// PushCommit is a commit within a PushContent.
+type PushCommit struct {
+	Sha1    string `json:"Sha1"`
+	Message string `json:"Message"`
+}
+
+// User is the authenticated user.
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
@@ -0,0 +1,202 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+// ProviderName is the data source and activity source key for Gitea and
+// Forgejo, which share the same API.
+const ProviderName = "gitea"
+
+// Provider syncs a Gitea or Forgejo instance through the user activity feed.
+type Provider struct {
+	client *Client
+	oauth  provider.OAuthConfig
+}
+
+// NewProvider creates the Gitea provider. creds may be empty when users
+// only connect with personal access tokens.
+func NewProvider(client *Client, creds provider.Credentials) *Provider {
+	return &Provider{
+		client: client,
+		oauth: provider.OAuthConfig{
+			Credentials:  creds,
+			AuthorizeURL: client.baseURL + "/login/oauth/authorize",
+			TokenURL:     client.baseURL + "/login/oauth/access_token",
+			Scopes:       []string{"read:user", "read:repository"},
+			PKCE:         true,
+		},
+	}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+func (p *Provider) OAuthConfig() provider.OAuthConfig { return p.oauth }
+
+func (p *Provider) ExchangeCode(ctx context.Context, code, verifier string) (*provider.Token, error) {
+	return provider.ExchangeCode(ctx, p.oauth, code, verifier)
+}
+
+// RefreshToken renews an OAuth access token. Personal access tokens don't
+// expire and are stored without a refresh token.
+func (p *Provider) RefreshToken(ctx context.Context, refreshToken string) (*provider.Token, error) {
+	return provider.RefreshToken(ctx, p.oauth, refreshToken)
+}
+
+// VerifyToken checks a personal access token against the instance.
+func (p *Provider) VerifyToken(ctx context.Context, token string) error {
+	_, err := p.client.FetchUser(ctx, token)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%w: %w", provider.ErrTokenRejected, err)
+	}
+	return err
+}
+
+// Fetch reads the feed entries newer than cursor, the ID of the newest
+// entry seen by the previous fetch.
+func (p *Provider) Fetch(ctx context.Context, token, cursor string) (*provider.FetchResult, error) {
+	user, err := p.client.FetchUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	sinceID, _ := strconv.ParseInt(cursor, 10, 64)
+	feed, err := p.client.FetchActivities(ctx, token, user.Login, sinceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &provider.FetchResult{Cursor: cursor}
+	if len(feed) == 0 {
+		result.NotModified = true
+		return result, nil
+	}
+	// The feed is newest first.
+	result.Cursor = strconv.FormatInt(feed[0].ID, 10)
+
+	for _, a := range feed {
+		if act, ok := toActivity(a); ok {
+			result.Activities = append(result.Activities, act)
+		}
+	}
+	return result, nil
+}
+
+func (p *Provider) SupportedTypes() []string {
+	return []string{"create", "pull_request", "push", "review"}
+}
+
+// toActivity maps a feed entry to an activity using GitHub's type
+// vocabulary and payload shape. Entries for issues, deletions and other
+// operations are dropped.
+func toActivity(a Activity) (provider.Activity, bool) {
+	var repo string
+	if a.Repo != nil {
+		repo = a.Repo.FullName
+	}
+	act := provider.Activity{OccurredAt: a.Created, ExternalID: strconv.FormatInt(a.ID, 10)}
+	var payload map[string]any
+
+	switch a.OpType {
+	case "commit_repo", "mirror_sync_push":
+		var content PushContent
+		if err := json.Unmarshal([]byte(a.Content), &content); err != nil || content.Len == 0 {
+			return act, false
+		}
+		act.Type = "push"
+		payload = map[string]any{
+			"ref":           a.RefName,
+			"size":          content.Len,
+			"distinct_size": content.Len,
+		}
+		// The feed truncates long pushes; a partial list would make the
+		// daily total count only the listed commits.
+		if len(content.Commits) == content.Len {
+			commits := make([]map[string]string, 0, len(content.Commits))
+			for _, c := range content.Commits {
+				commits = append(commits, map[string]string{"sha": c.Sha1, "message": c.Message})
+			}
+			payload["commits"] = commits
+		}
+		if content.HeadCommit != nil && content.HeadCommit.Sha1 != "" {
+			payload["head"] = content.HeadCommit.Sha1
+			act.ExternalID = fmt.Sprintf("push:%s:%s", repo, content.HeadCommit.Sha1)
+		}
+
+	case "create_pull_request", "merge_pull_request", "auto_merge_pull_request", "close_pull_request", "reopen_pull_request":
+		number, title := splitIndex(a.Content)
+		action, state, merged := "opened", "open", false
+		switch a.OpType {
+		case "merge_pull_request", "auto_merge_pull_request":
+			action, state, merged = "closed", "closed", true
+		case "close_pull_request":
+			action, state = "closed", "closed"
+		case "reopen_pull_request":
+			action = "reopened"
+		}
+		act.Type = "pull_request"
+		payload = map[string]any{
+			"action": action,
+			"number": number,
+			"pull_request": map[string]any{
+				"number": number,
+				"title":  title,
+				"state":  state,
+				"merged": merged,
+			},
+		}
+		act.ExternalID = fmt.Sprintf("pull_request:%s:%d:%s", repo, number, a.OpType)
+
+	case "approve_pull_request", "reject_pull_request", "comment_pull":
+		number, _ := splitIndex(a.Content)
+		state := map[string]string{
+			"approve_pull_request": "approved",
+			"reject_pull_request":  "changes_requested",
+			"comment_pull":         "commented",
+		}[a.OpType]
+		act.Type = "review"
+		payload = map[string]any{
+			"action": "created",
+			"number": number,
+			"review": map[string]any{
+				"id":           a.CommentID,
+				"state":        state,
+				"submitted_at": a.Created,
+			},
+		}
+
+	case "push_tag":
+		act.Type = "create"
+		payload = map[string]any{"ref": strings.TrimPrefix(a.RefName, "refs/tags/"), "ref_type": "tag"}
+
+	case "create_repo":
+		act.Type = "create"
+		payload = map[string]any{"ref_type": "repository"}
+
+	default:
+		return act, false
+	}
+
+	body := map[string]any{"payload": payload}
+	if repo != "" {
+		body["repo"] = repo
+	}
+	act.Payload, _ = json.Marshal(body)
+	return act, true
+}
+
+// splitIndex parses the "index|text" content of pull request entries.
+func splitIndex(content string) (int, string) {
+	index, text, _ := strings.Cut(content, "|")
+	n, _ := strconv.Atoi(index)
+	return n, text
+}
@@ -0,0 +1,139 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+func decodePayload(t *testing.T, act provider.Activity) map[string]any {
+	t.Helper()
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(act.Payload, &body))
+	return body
+}
+
+func TestToActivity_Push(t *testing.T) {
+	a := Activity{
+		ID:      1,
+		OpType:  "commit_repo",
+		Repo:    &Repo{FullName: "me/project"},
+		RefName: "refs/heads/main",
+		Content: `{"Commits":[{"Sha1":"bbb","Message":"Second"},{"Sha1":"aaa","Message":"First"}],"HeadCommit":{"Sha1":"bbb"},"Len":2}`,
+		Created: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	act, ok := toActivity(a)
+
+	require.True(t, ok)
+	assert.Equal(t, "push", act.Type)
+	assert.Equal(t, "push:me/project:bbb", act.ExternalID)
+	body := decodePayload(t, act)
+	assert.Equal(t, "me/project", body["repo"])
+	payload := body["payload"].(map[string]any)
+	assert.Len(t, payload["commits"], 2)
+	assert.Equal(t, 2.0, payload["distinct_size"])
+}
+
+func TestToActivity_TruncatedPushOmitsCommits(t *testing.T) {
+	a := Activity{
+		ID:      1,
+		OpType:  "commit_repo",
+		Content: `{"Commits":[{"Sha1":"bbb"}],"HeadCommit":{"Sha1":"bbb"},"Len":8}`,
+	}
+
+	act, ok := toActivity(a)
+
+	require.True(t, ok)
+	payload := decodePayload(t, act)["payload"].(map[string]any)
+	assert.NotContains(t, payload, "commits")
+	assert.Equal(t, 8.0, payload["distinct_size"])
+}
+
+func TestToActivity_PullRequests(t *testing.T) {
+	tests := []struct {
+		opType string
+		action string
+		merged bool
+	}{
+		{"create_pull_request", "opened", false},
+		{"merge_pull_request", "closed", true},
+		{"close_pull_request", "closed", false},
+		{"reopen_pull_request", "reopened", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.opType, func(t *testing.T) {
+			act, ok := toActivity(Activity{ID: 2, OpType: tt.opType, Repo: &Repo{FullName: "me/project"}, Content: "12|Add feature"})
+
+			require.True(t, ok)
+			assert.Equal(t, "pull_request", act.Type)
+			payload := decodePayload(t, act)["payload"].(map[string]any)
+			assert.Equal(t, tt.action, payload["action"])
+			assert.Equal(t, 12.0, payload["number"])
+			pr := payload["pull_request"].(map[string]any)
+			assert.Equal(t, "Add feature", pr["title"])
+			assert.Equal(t, tt.merged, pr["merged"])
+		})
+	}
+}
+
+func TestToActivity_Reviews(t *testing.T) {
+	act, ok := toActivity(Activity{ID: 3, OpType: "reject_pull_request", CommentID: 30, Content: "12|Needs tests"})
+
+	require.True(t, ok)
+	assert.Equal(t, "review", act.Type)
+	review := decodePayload(t, act)["payload"].(map[string]any)["review"].(map[string]any)
+	assert.Equal(t, "changes_requested", review["state"])
+}
+
+func TestToActivity_Dropped(t *testing.T) {
+	for _, opType := range []string{"create_issue", "delete_branch", "star_repo"} {
+		_, ok := toActivity(Activity{ID: 4, OpType: opType})
+		assert.False(t, ok, opType)
+	}
+}
+
+func TestProvider_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token test-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/user":
+			json.NewEncoder(w).Encode(User{ID: 1, Login: "me"})
+		case "/api/v1/users/me/activities/feeds":
+			assert.Equal(t, "true", r.URL.Query().Get("only-performed-by"))
+			json.NewEncoder(w).Encode([]Activity{
+				{ID: 9, OpType: "create_pull_request", Content: "1|x"},
+				{ID: 8, OpType: "star_repo"},
+				{ID: 7, OpType: "create_repo"},
+			})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewProvider(NewClient(nil, srv.URL), provider.Credentials{})
+	result, err := p.Fetch(context.Background(), "test-token", "7")
+
+	require.NoError(t, err)
+	assert.Equal(t, "9", result.Cursor)
+	require.Len(t, result.Activities, 1)
+	assert.Equal(t, "pull_request", result.Activities[0].Type)
+}
+
+func TestProvider_OAuthConfig(t *testing.T) {
+	p := NewProvider(NewClient(nil, "https://codeberg.org/"), provider.Credentials{})
+
+	cfg := p.OAuthConfig()
+	assert.Equal(t, "https://codeberg.org/login/oauth/authorize", cfg.AuthorizeURL)
+	assert.Equal(t, "https://codeberg.org/login/oauth/access_token", cfg.TokenURL)
+	assert.Equal(t, "gitea", p.Name())
+}
@@ -0,0 +1,130 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+// ActivityParams converts a GitHub event into an activity row for the given
+// user, with source the provider name of the deployment it came from.
+// Events from the Events API and from webhooks produce the same row, so the
+// (user_id, source, external_id) dedup index keeps the two paths from
+// double counting.
+func ActivityParams(userID int64, source string, evt Event) dbgen.InsertActivityParams {
+	return toActivity(evt).InsertParams(userID, source)
+}
+
+func toActivity(evt Event) provider.Activity {
+	payload, _ := json.Marshal(map[string]any{
+		"repo":    evt.Repo.Name,
+		"payload": evt.Payload,
+	})
+
+	act := provider.Activity{
+		Type:       mapEventType(evt.Type),
+		OccurredAt: evt.CreatedAt,
+		ExternalID: externalID(evt),
+		Payload:    payload,
+	}
+	// Events API rows used to be keyed by the event ID
+	if act.ExternalID != evt.ID {
+		act.LegacyExternalID = evt.ID
+	}
+	return act
+}
+
+// externalID derives a dedup key from the event content rather than the
+// Events API event ID, which webhook deliveries don't carry. Falls back to
+// the event ID when the payload lacks the identifying fields.
+func externalID(evt Event) string {
+	p := evt.Payload
+	repo := evt.Repo.Name
+	switch evt.Type {
+	case "PushEvent":
+		if p.Head != "" {
+			return fmt.Sprintf("push:%s:%s", repo, p.Head)
+		}
+	case "PullRequestEvent":
+		if number := prNumber(p); number != 0 && p.Action != "" {
+			return fmt.Sprintf("pull_request:%s:%d:%s", repo, number, p.Action)
+		}
+	case "PullRequestReviewEvent":
+		if p.Review != nil && p.Review.ID != 0 {
+			return fmt.Sprintf("review:%s:%d", repo, p.Review.ID)
+		}
+	case "CreateEvent":
+		if p.RefType != "" {
+			return fmt.Sprintf("create:%s:%s:%s", repo, p.RefType, p.Ref)
+		}
+	case "DeleteEvent":
+		if p.RefType != "" {
+			return fmt.Sprintf("delete:%s:%s:%s", repo, p.RefType, p.Ref)
+		}
+	case "IssuesEvent":
+		if p.Issue != nil && p.Issue.Number != 0 && p.Action != "" {
+			return fmt.Sprintf("issue:%s:%d:%s", repo, p.Issue.Number, p.Action)
+		}
+	case "IssueCommentEvent":
+		if p.Comment != nil && p.Comment.ID != 0 {
+			return fmt.Sprintf("issue_comment:%s:%d", repo, p.Comment.ID)
+		}
+	case "PullRequestReviewCommentEvent":
+		if p.Comment != nil && p.Comment.ID != 0 {
+			return fmt.Sprintf("review_comment:%s:%d", repo, p.Comment.ID)
+		}
+	case "ReleaseEvent":
+		if p.Release != nil && p.Release.ID != 0 {
+			return fmt.Sprintf("release:%s:%d", repo, p.Release.ID)
+		}
+	case "ForkEvent":
+		if p.Forkee != nil && p.Forkee.FullName != "" {
+			return fmt.Sprintf("fork:%s:%s", repo, p.Forkee.FullName)
+		}
+	case "WatchEvent":
+		// Starring is a toggle; re-starring the same repository counts once
+		return fmt.Sprintf("star:%s", repo)
+	}
+	return evt.ID
+}
+
+func prNumber(p Payload) int {
+	if p.Number != 0 {
+		return p.Number
+	}
+	if p.PullRequest != nil {
+		return p.PullRequest.Number
+	}
+	return 0
+}
+
+func mapEventType(ghType string) string {
+	switch ghType {
+	case "PushEvent":
+		return "push"
+	case "PullRequestEvent":
+		return "pull_request"
+	case "PullRequestReviewEvent":
+		return "review"
+	case "CreateEvent":
+		return "create"
+	case "DeleteEvent":
+		return "delete"
+	case "IssuesEvent":
+		return "issue"
+	case "IssueCommentEvent":
+		return "issue_comment"
+	case "PullRequestReviewCommentEvent":
+		return "review_comment"
+	case "ReleaseEvent":
+		return "release"
+	case "ForkEvent":
+		return "fork"
+	case "WatchEvent":
+		return "star"
+	default:
+		return ghType
+	}
+}
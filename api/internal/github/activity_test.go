@@ -0,0 +1,77 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapEventType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"PushEvent", "push"},
+		{"PullRequestEvent", "pull_request"},
+		{"PullRequestReviewEvent", "review"},
+		{"CreateEvent", "create"},
+		{"DeleteEvent", "delete"},
+		{"IssuesEvent", "issue"},
+		{"IssueCommentEvent", "issue_comment"},
+		{"PullRequestReviewCommentEvent", "review_comment"},
+		{"ReleaseEvent", "release"},
+		{"ForkEvent", "fork"},
+		{"WatchEvent", "star"},
+		{"UnknownEvent", "UnknownEvent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mapEventType(tt.input))
+		})
+	}
+}
+
+func TestExternalID_MatchesAcrossPollingAndWebhooks(t *testing.T) {
+	polled := Event{
+		ID:      "1234567890",
+		Type:    "PushEvent",
+		Repo:    Repo{Name: "user/repo"},
+		Payload: Payload{Head: "def456", Size: 2},
+	}
+	assert.Equal(t, "push:user/repo:def456", externalID(polled))
+
+	pr := Event{
+		ID:      "1234567891",
+		Type:    "PullRequestEvent",
+		Repo:    Repo{Name: "user/repo"},
+		Payload: Payload{Action: "closed", PullRequest: &PullRequest{Number: 7}},
+	}
+	assert.Equal(t, "pull_request:user/repo:7:closed", externalID(pr))
+}
+
+func TestExternalID_NewEventTypes(t *testing.T) {
+	tests := []struct {
+		evt      Event
+		expected string
+	}{
+		{Event{Type: "DeleteEvent", Payload: Payload{Ref: "feature", RefType: "branch"}}, "delete:user/repo:branch:feature"},
+		{Event{Type: "IssuesEvent", Payload: Payload{Action: "opened", Issue: &Issue{Number: 3}}}, "issue:user/repo:3:opened"},
+		{Event{Type: "IssueCommentEvent", Payload: Payload{Action: "created", Comment: &Comment{ID: 41}}}, "issue_comment:user/repo:41"},
+		{Event{Type: "PullRequestReviewCommentEvent", Payload: Payload{Action: "created", Comment: &Comment{ID: 42}}}, "review_comment:user/repo:42"},
+		{Event{Type: "ReleaseEvent", Payload: Payload{Action: "published", Release: &Release{ID: 5, TagName: "v1.0.0"}}}, "release:user/repo:5"},
+		{Event{Type: "ForkEvent", Payload: Payload{Forkee: &Forkee{FullName: "me/repo"}}}, "fork:user/repo:me/repo"},
+		{Event{Type: "WatchEvent", Payload: Payload{Action: "started"}}, "star:user/repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.evt.Type, func(t *testing.T) {
+			tt.evt.ID = "1234567893"
+			tt.evt.Repo = Repo{Name: "user/repo"}
+			assert.Equal(t, tt.expected, externalID(tt.evt))
+		})
+	}
+}
+
+func TestExternalID_FallsBackToEventID(t *testing.T) {
+	evt := Event{ID: "1234567892", Type: "PushEvent", Repo: Repo{Name: "user/repo"}}
+	assert.Equal(t, "1234567892", externalID(evt))
+}
@@ -5,35 +5,68 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // SupportedEventTypes lists the event types we process.
 var SupportedEventTypes = map[string]bool{
-	"PushEvent":              true,
-	"PullRequestEvent":       true,
-	"PullRequestReviewEvent": true,
-	"CreateEvent":            true,
+	"PushEvent":                     true,
+	"PullRequestEvent":              true,
+	"PullRequestReviewEvent":        true,
+	"CreateEvent":                   true,
+	"DeleteEvent":                   true,
+	"ForkEvent":                     true,
+	"IssueCommentEvent":             true,
+	"IssuesEvent":                   true,
+	"PullRequestReviewCommentEvent": true,
+	"ReleaseEvent":                  true,
+	"WatchEvent":                    true,
 }
 
-// Client calls the GitHub API.
+// Client calls the GitHub API of github.com or a GitHub Enterprise Server.
 type Client struct {
 	httpClient *http.Client
-	baseURL    string // for testing; defaults to "https://api.github.com"
+	baseURL    string // REST API root, e.g. "https://api.github.com"
 }
 
-// NewClient creates a new GitHub API client.
-// If httpClient is nil, a default http.Client is used.
-func NewClient(httpClient *http.Client) *Client {
+// NewClient creates a GitHub API client for the REST API at baseURL:
+// "https://api.github.com", or "https://HOST/api/v3" for an Enterprise
+// Server. If httpClient is nil, a default http.Client is used.
+func NewClient(httpClient *http.Client, baseURL string) *Client {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
-	return &Client{httpClient: httpClient, baseURL: "https://api.github.com"}
+	return &Client{httpClient: httpClient, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// graphqlURL returns the GraphQL endpoint next to the REST API. Enterprise
+// Servers serve it at /api/graphql rather than under /api/v3.
+func (c *Client) graphqlURL() string {
+	if root, ok := strings.CutSuffix(c.baseURL, "/api/v3"); ok {
+		return root + "/api/graphql"
+	}
+	return c.baseURL + "/graphql"
+}
+
+// EventsResult is the outcome of a FetchUserEvents call.
+type EventsResult struct {
+	Events []Event
+	// ETag of the first page, to send back on the next poll.
+	ETag string
+	// PollInterval is the minimum wait GitHub asks for before the next poll.
+	PollInterval time.Duration
+	// NotModified is set when the first page matched the given ETag, in
+	// which case Events is empty and the request didn't count against quota.
+	NotModified bool
 }
 
 // FetchUserEvents fetches all recent events for the authenticated user.
-// GitHub returns max 10 pages of 30 events (300 total).
-func (c *Client) FetchUserEvents(ctx context.Context, token string) ([]Event, error) {
-	var allEvents []Event
+// GitHub returns max 10 pages of 30 events (300 total). If etag is set it is
+// sent as If-None-Match on the first page. Rate limit responses are returned
+// as *RateLimitError, other failures as *APIError.
+func (c *Client) FetchUserEvents(ctx context.Context, token, etag string) (*EventsResult, error) {
+	result := &EventsResult{}
 
 	for page := 1; page <= 10; page++ {
 		url := fmt.Sprintf("%s/user/events?per_page=30&page=%d", c.baseURL, page)
@@ -43,6 +76,9 @@ func (c *Client) FetchUserEvents(ctx context.Context, token string) ([]Event, er
 		}
 		req.Header.Set("Authorization", "Bearer "+token)
 		req.Header.Set("Accept", "application/vnd.github+json")
+		if page == 1 && etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
 
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
@@ -50,8 +86,18 @@ func (c *Client) FetchUserEvents(ctx context.Context, token string) ([]Event, er
 		}
 		defer resp.Body.Close()
 
+		if page == 1 {
+			result.ETag = resp.Header.Get("ETag")
+			result.PollInterval = pollInterval(resp)
+			if resp.StatusCode == http.StatusNotModified {
+				result.ETag = etag
+				result.NotModified = true
+				return result, nil
+			}
+		}
+
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("github api returned %d", resp.StatusCode)
+			return nil, responseError(resp, time.Now())
 		}
 
 		var events []Event
@@ -59,12 +105,101 @@ func (c *Client) FetchUserEvents(ctx context.Context, token string) ([]Event, er
 			return nil, fmt.Errorf("decode events: %w", err)
 		}
 
-		allEvents = append(allEvents, events...)
+		result.Events = append(result.Events, events...)
 
 		if len(events) < 30 {
 			break // No more pages
 		}
 	}
 
-	return allEvents, nil
+	return result, nil
+}
+
+// FetchRepoLanguages returns the number of bytes of code per language for a
+// repository given as "owner/name".
+func (c *Client) FetchRepoLanguages(ctx context.Context, token, repo string) (map[string]int64, error) {
+	var languages map[string]int64
+	url := fmt.Sprintf("%s/repos/%s/languages", c.baseURL, repo)
+	if err := c.getJSON(ctx, token, url, &languages); err != nil {
+		return nil, fmt.Errorf("fetch languages for %s: %w", repo, err)
+	}
+	return languages, nil
+}
+
+// FetchCommit returns a commit of a repository given as "owner/name",
+// including its line stats and changed files.
+func (c *Client) FetchCommit(ctx context.Context, token, repo, sha string) (*CommitDetail, error) {
+	var commit CommitDetail
+	url := fmt.Sprintf("%s/repos/%s/commits/%s", c.baseURL, repo, sha)
+	if err := c.getJSON(ctx, token, url, &commit); err != nil {
+		return nil, fmt.Errorf("fetch commit %s in %s: %w", sha, repo, err)
+	}
+	return &commit, nil
+}
+
+// FetchPullRequest returns a pull request of a repository given as
+// "owner/name".
+func (c *Client) FetchPullRequest(ctx context.Context, token, repo string, number int) (*PullRequestDetail, error) {
+	var pr PullRequestDetail
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d", c.baseURL, repo, number)
+	if err := c.getJSON(ctx, token, url, &pr); err != nil {
+		return nil, fmt.Errorf("fetch pull request %s#%d: %w", repo, number, err)
+	}
+	return &pr, nil
+}
+
+// FetchPullRequestReviews returns the first 100 reviews of a pull request,
+// oldest first. That's enough to find when reviewing started.
+func (c *Client) FetchPullRequestReviews(ctx context.Context, token, repo string, number int) ([]PullRequestReview, error) {
+	var reviews []PullRequestReview
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?per_page=100", c.baseURL, repo, number)
+	if err := c.getJSON(ctx, token, url, &reviews); err != nil {
+		return nil, fmt.Errorf("fetch reviews of %s#%d: %w", repo, number, err)
+	}
+	return reviews, nil
+}
+
+// FetchAuthenticatedUser returns the account the token belongs to.
+func (c *Client) FetchAuthenticatedUser(ctx context.Context, token string) (*Actor, error) {
+	var user Actor
+	if err := c.getJSON(ctx, token, c.baseURL+"/user", &user); err != nil {
+		return nil, fmt.Errorf("fetch user: %w", err)
+	}
+	return &user, nil
+}
+
+// FetchIssueEvents returns the first 100 events of an issue or pull
+// request, oldest first.
+func (c *Client) FetchIssueEvents(ctx context.Context, token, repo string, number int) ([]IssueEvent, error) {
+	var events []IssueEvent
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/events?per_page=100", c.baseURL, repo, number)
+	if err := c.getJSON(ctx, token, url, &events); err != nil {
+		return nil, fmt.Errorf("fetch events of %s#%d: %w", repo, number, err)
+	}
+	return events, nil
+}
+
+// getJSON GETs url and decodes the JSON response into v. Rate limit
+// responses are returned as *RateLimitError, other failures as *APIError.
+func (c *Client) getJSON(ctx context.Context, token, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, time.Now())
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
 }
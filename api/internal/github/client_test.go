@@ -15,9 +15,7 @@ import (
 
 // newTestClient creates a Client pointed at the given test server URL.
 func newTestClient(serverURL string) *Client {
-	c := NewClient(nil)
-	c.baseURL = serverURL
-	return c
+	return NewClient(nil, serverURL)
 }
 
 func TestFetchUserEvents_Success(t *testing.T) {
@@ -58,9 +56,10 @@ func TestFetchUserEvents_Success(t *testing.T) {
 	defer srv.Close()
 
 	client := newTestClient(srv.URL)
-	result, err := client.FetchUserEvents(context.Background(), "test-token")
+	res, err := client.FetchUserEvents(context.Background(), "test-token", "")
 
 	require.NoError(t, err)
+	result := res.Events
 	assert.Len(t, result, 2)
 
 	// Verify PushEvent parsing
@@ -118,9 +117,10 @@ func TestFetchUserEvents_Pagination(t *testing.T) {
 	defer srv.Close()
 
 	client := newTestClient(srv.URL)
-	result, err := client.FetchUserEvents(context.Background(), "test-token")
+	res, err := client.FetchUserEvents(context.Background(), "test-token", "")
 
 	require.NoError(t, err)
+	result := res.Events
 	assert.Len(t, result, 35) // 30 from page 1 + 5 from page 2
 
 	// Verify first event is from page 1
@@ -142,7 +142,7 @@ func TestFetchUserEvents_AuthHeader(t *testing.T) {
 	defer srv.Close()
 
 	client := newTestClient(srv.URL)
-	_, err := client.FetchUserEvents(context.Background(), "ghp_my-secret-token")
+	_, err := client.FetchUserEvents(context.Background(), "ghp_my-secret-token", "")
 
 	require.NoError(t, err)
 	assert.Equal(t, "Bearer ghp_my-secret-token", receivedAuth)
@@ -157,11 +157,248 @@ func TestFetchUserEvents_Non200(t *testing.T) {
 	defer srv.Close()
 
 	client := newTestClient(srv.URL)
-	result, err := client.FetchUserEvents(context.Background(), "bad-token")
+	result, err := client.FetchUserEvents(context.Background(), "bad-token", "")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "401")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "Bad credentials", apiErr.Message)
+}
+
+func TestFetchUserEvents_NotModified(t *testing.T) {
+	var receivedETag string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedETag = r.Header.Get("If-None-Match")
+		w.Header().Set("X-Poll-Interval", "60")
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	result, err := client.FetchUserEvents(context.Background(), "test-token", `W/"abc"`)
+
+	require.NoError(t, err)
+	assert.Equal(t, `W/"abc"`, receivedETag)
+	assert.True(t, result.NotModified)
+	assert.Empty(t, result.Events)
+	assert.Equal(t, `W/"abc"`, result.ETag)
+	assert.Equal(t, time.Minute, result.PollInterval)
+}
+
+func TestFetchUserEvents_ETagAndPollInterval(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `W/"def"`)
+		w.Header().Set("X-Poll-Interval", "90")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Event{{ID: "1", Type: "PushEvent"}})
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	result, err := client.FetchUserEvents(context.Background(), "test-token", "")
+
+	require.NoError(t, err)
+	assert.False(t, result.NotModified)
+	assert.Len(t, result.Events, 1)
+	assert.Equal(t, `W/"def"`, result.ETag)
+	assert.Equal(t, 90*time.Second, result.PollInterval)
+}
+
+func TestFetchUserEvents_PrimaryRateLimit(t *testing.T) {
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	_, err := client.FetchUserEvents(context.Background(), "test-token", "")
+
+	var rlErr *RateLimitError
+	require.ErrorAs(t, err, &rlErr)
+	assert.False(t, rlErr.Secondary)
+	assert.True(t, rlErr.ResetAt.Equal(reset))
+}
+
+func TestFetchUserEvents_SecondaryRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantWait time.Duration
+	}{
+		{name: "retry after header", header: "120", wantWait: 2 * time.Minute},
+		{name: "message only", wantWait: secondaryLimitWait},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("Retry-After", tt.header)
+				}
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+			}))
+			defer srv.Close()
+
+			client := newTestClient(srv.URL)
+			before := time.Now()
+			_, err := client.FetchUserEvents(context.Background(), "test-token", "")
+
+			var rlErr *RateLimitError
+			require.ErrorAs(t, err, &rlErr)
+			assert.True(t, rlErr.Secondary)
+			assert.InDelta(t, tt.wantWait.Seconds(), rlErr.Wait(before).Seconds(), 1)
+		})
+	}
+}
+
+func TestRateLimitError_WaitFloor(t *testing.T) {
+	err := &RateLimitError{ResetAt: time.Now().Add(-time.Minute)}
+	assert.Equal(t, time.Second, err.Wait(time.Now()))
+}
+
+func TestFetchRepoLanguages(t *testing.T) {
+	var receivedPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Go":12345,"Shell":67}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	langs, err := client.FetchRepoLanguages(context.Background(), "test-token", "user/repo")
+
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/user/repo/languages", receivedPath)
+	assert.Equal(t, map[string]int64{"Go": 12345, "Shell": 67}, langs)
+}
+
+func TestFetchRepoLanguages_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	_, err := client.FetchRepoLanguages(context.Background(), "test-token", "user/gone")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestFetchCommit(t *testing.T) {
+	var receivedPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sha":"abc123","stats":{"additions":12,"deletions":3,"total":15},"files":[{"filename":"main.go"},{"filename":"go.mod"}]}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	commit, err := client.FetchCommit(context.Background(), "test-token", "user/repo", "abc123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/user/repo/commits/abc123", receivedPath)
+	assert.Equal(t, 12, commit.Stats.Additions)
+	assert.Equal(t, 3, commit.Stats.Deletions)
+	require.Len(t, commit.Files, 2)
+	assert.Equal(t, "go.mod", commit.Files[1].Filename)
+}
+
+func TestFetchPullRequest(t *testing.T) {
+	var receivedPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":7,"title":"Add feature","state":"closed","user":{"login":"me"},"created_at":"2026-03-01T09:00:00Z","merged_at":"2026-03-02T09:00:00Z","closed_at":"2026-03-02T09:00:00Z"}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	pr, err := client.FetchPullRequest(context.Background(), "test-token", "user/repo", 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/user/repo/pulls/7", receivedPath)
+	assert.Equal(t, "me", pr.User.Login)
+	require.NotNil(t, pr.MergedAt)
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), *pr.MergedAt)
+}
+
+func TestFetchPullRequestReviews(t *testing.T) {
+	var receivedPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"user":{"login":"alice"},"state":"COMMENTED","submitted_at":"2026-03-01T10:00:00Z"},{"user":{"login":"bob"},"state":"PENDING"}]`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	reviews, err := client.FetchPullRequestReviews(context.Background(), "test-token", "user/repo", 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/user/repo/pulls/7/reviews", receivedPath)
+	require.Len(t, reviews, 2)
+	assert.Equal(t, "alice", reviews[0].User.Login)
+	assert.Nil(t, reviews[1].SubmittedAt)
+}
+
+func TestFetchAuthenticatedUser(t *testing.T) {
+	var receivedPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"login":"me","id":1}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	user, err := client.FetchAuthenticatedUser(context.Background(), "test-token")
+
+	require.NoError(t, err)
+	assert.Equal(t, "/user", receivedPath)
+	assert.Equal(t, "me", user.Login)
+}
+
+func TestFetchIssueEvents(t *testing.T) {
+	var receivedPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"event":"review_requested","created_at":"2026-03-01T10:00:00Z","requested_reviewer":{"login":"me"}},{"event":"review_requested","created_at":"2026-03-01T11:00:00Z","requested_team":{"slug":"core"}}]`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	events, err := client.FetchIssueEvents(context.Background(), "test-token", "user/repo", 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/user/repo/issues/7/events", receivedPath)
+	require.Len(t, events, 2)
+	assert.Equal(t, "me", events[0].RequestedReviewer.Login)
+	assert.Nil(t, events[1].RequestedReviewer)
+	assert.Equal(t, "core", events[1].RequestedTeam.Slug)
 }
 
 func TestSupportedEventTypes(t *testing.T) {
@@ -170,6 +407,13 @@ func TestSupportedEventTypes(t *testing.T) {
 		"PullRequestEvent",
 		"PullRequestReviewEvent",
 		"CreateEvent",
+		"DeleteEvent",
+		"ForkEvent",
+		"IssueCommentEvent",
+		"IssuesEvent",
+		"PullRequestReviewCommentEvent",
+		"ReleaseEvent",
+		"WatchEvent",
 	}
 
 	assert.Len(t, SupportedEventTypes, len(expected))
@@ -179,6 +423,11 @@ func TestSupportedEventTypes(t *testing.T) {
 	}
 
 	// Verify unsupported types return false
-	assert.False(t, SupportedEventTypes["WatchEvent"])
-	assert.False(t, SupportedEventTypes["ForkEvent"])
+	assert.False(t, SupportedEventTypes["GollumEvent"])
+	assert.False(t, SupportedEventTypes["MemberEvent"])
+}
+
+func TestGraphQLURL(t *testing.T) {
+	assert.Equal(t, "https://api.github.com/graphql", NewClient(nil, "https://api.github.com").graphqlURL())
+	assert.Equal(t, "https://ghe.example.com/api/graphql", NewClient(nil, "https://ghe.example.com/api/v3/").graphqlURL())
 }
@@ -0,0 +1,253 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
+)
+
+// enrichBatch is how many pushes are loaded per query.
+const enrichBatch = 50
+
+// enrichTimeout bounds one enrichment attempt. A first run after connecting
+// can look up several hundred commits one request at a time.
+const enrichTimeout = 10 * time.Minute
+
+// enrichDelay is how long enrichment jobs wait before they run, so the
+// requests of one sync or burst of webhooks share a job.
+const enrichDelay = 30 * time.Second
+
+// EnrichCommitsArgs are the arguments for filling in the line and file
+// stats of a user's GitHub pushes.
+type EnrichCommitsArgs struct {
+	UserID int64 `json:"user_id"`
+	// Provider names the GitHub deployment; empty means github.com.
+	Provider string `json:"provider,omitempty"`
+}
+
+func (EnrichCommitsArgs) Kind() string { return "github_commit_enrich" }
+
+// InsertOpts dedupes against jobs that haven't finished yet, so every sync
+// and push webhook can ask for enrichment without piling up jobs.
+func (EnrichCommitsArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		ScheduledAt: time.Now().Add(enrichDelay),
+		UniqueOpts:  riversetup.UniqueUnfinished(enrichDelay),
+	}
+}
+
+// EnrichCommitsWorker looks up the commits of pushes that haven't been
+// enriched yet, stores their stats on the activity and re-aggregates the
+// affected days. Stats are cached per repository in commit_stats, so a
+// commit is only fetched once.
+type EnrichCommitsWorker struct {
+	riverlib.WorkerDefaults[EnrichCommitsArgs]
+	q       *dbgen.Queries
+	hosts   Hosts
+	keyring *tokencrypt.Keyring
+}
+
+func NewEnrichCommitsWorker(q *dbgen.Queries, hosts Hosts, keyring *tokencrypt.Keyring) *EnrichCommitsWorker {
+	return &EnrichCommitsWorker{q: q, hosts: hosts, keyring: keyring}
+}
+
+func (w *EnrichCommitsWorker) Timeout(job *riverlib.Job[EnrichCommitsArgs]) time.Duration {
+	return enrichTimeout
+}
+
+func (w *EnrichCommitsWorker) Work(ctx context.Context, job *riverlib.Job[EnrichCommitsArgs]) error {
+	userID := job.Args.UserID
+	source, gh, err := w.hosts.lookup(job.Args.Provider)
+	if err != nil {
+		return err
+	}
+
+	ds, err := w.q.GetDataSourceByUserAndProvider(ctx, dbgen.GetDataSourceByUserAndProviderParams{
+		UserID:   userID,
+		Provider: source,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("%s data source for user %d not found", source, userID))
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := w.keyring.Decrypt(ds.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	// Days already enriched are re-aggregated even if a later push fails,
+	// since the retry won't list those pushes again.
+	touched, err := w.enrichAll(ctx, gh, userID, source, string(token))
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	if aggErr := summary.Reaggregate(ctx, w.q, client, userID, touched); aggErr != nil {
+		return errors.Join(err, aggErr)
+	}
+
+	var rateLimited *RateLimitError
+	if errors.As(err, &rateLimited) {
+		return riverlib.JobSnooze(rateLimited.Wait(time.Now()))
+	}
+	return err
+}
+
+// enrichAll enriches the user's pushes batch by batch and returns the
+// times of the pushes it updated.
+func (w *EnrichCommitsWorker) enrichAll(ctx context.Context, gh *Client, userID int64, source, token string) ([]time.Time, error) {
+	var touched []time.Time
+	for {
+		rows, err := w.q.ListUnenrichedPushes(ctx, dbgen.ListUnenrichedPushesParams{
+			UserID: userID,
+			Source: source,
+			Limit:  enrichBatch,
+		})
+		if err != nil {
+			return touched, err
+		}
+
+		for _, row := range rows {
+			payload, err := w.enrichPush(ctx, gh, token, row.Payload)
+			if err != nil {
+				return touched, fmt.Errorf("enrich activity %d: %w", row.ID, err)
+			}
+			if err := w.q.UpdateActivityPayload(ctx, dbgen.UpdateActivityPayloadParams{
+				ID:      row.ID,
+				Payload: payload,
+			}); err != nil {
+				return touched, err
+			}
+			touched = append(touched, row.OccurredAt.Time)
+		}
+
+		if len(rows) < enrichBatch {
+			return touched, nil
+		}
+	}
+}
+
+// storedActivity is the activity payload toActivity stores for an event.
+type storedActivity struct {
+	Repo    string  `json:"repo"`
+	Payload Payload `json:"payload"`
+}
+
+// enrichPush adds stats to the commits of a stored push, reading cached
+// stats first, and marks the push as fetched. Commits cached without stats
+// are fetched again with this user's token.
+func (w *EnrichCommitsWorker) enrichPush(ctx context.Context, gh *Client, token string, raw json.RawMessage) (json.RawMessage, error) {
+	var push storedActivity
+	if err := json.Unmarshal(raw, &push); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+
+	shas := make([]string, 0, len(push.Payload.Commits))
+	for _, c := range push.Payload.Commits {
+		shas = append(shas, c.SHA)
+	}
+	cached, err := w.q.ListCommitStats(ctx, dbgen.ListCommitStatsParams{Repo: push.Repo, Column2: shas})
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]dbgen.InsertCommitStatsParams, len(shas))
+	for _, s := range cached {
+		if !s.Additions.Valid {
+			// Another user couldn't read the commit; this one may.
+			continue
+		}
+		stats[s.Sha] = dbgen.InsertCommitStatsParams{
+			Repo:         s.Repo,
+			Sha:          s.Sha,
+			Additions:    s.Additions,
+			Deletions:    s.Deletions,
+			ChangedFiles: s.ChangedFiles,
+			Extensions:   s.Extensions,
+		}
+	}
+
+	for _, sha := range shas {
+		if _, ok := stats[sha]; ok || sha == "" {
+			continue
+		}
+		detail, err := gh.FetchCommit(ctx, token, push.Repo, sha)
+		if err != nil && !resourceUnavailable(err) {
+			return nil, err
+		}
+		s := commitStatsParams(push.Repo, sha, detail)
+		if err := w.q.InsertCommitStats(ctx, s); err != nil {
+			return nil, err
+		}
+		stats[sha] = s
+	}
+
+	for i := range push.Payload.Commits {
+		applyStats(&push.Payload.Commits[i], stats[push.Payload.Commits[i].SHA])
+	}
+	push.Payload.StatsFetched = true
+	return json.Marshal(push)
+}
+
+// resourceUnavailable reports whether the API can't return a commit or pull
+// request to this user: it was force-pushed away, the repository is gone or
+// empty, or access was revoked. Such commits are cached without stats.
+func resourceUnavailable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
+
+// commitStatsParams builds the cache row for a fetched commit. A nil
+// detail yields a row without stats.
+func commitStatsParams(repo, sha string, detail *CommitDetail) dbgen.InsertCommitStatsParams {
+	params := dbgen.InsertCommitStatsParams{Repo: repo, Sha: sha}
+	if detail == nil {
+		return params
+	}
+
+	extensions := make(map[string]int)
+	for _, f := range detail.Files {
+		if ext := strings.ToLower(strings.TrimPrefix(path.Ext(f.Filename), ".")); ext != "" {
+			extensions[ext]++
+		}
+	}
+	params.Additions = pgtype.Int4{Int32: int32(detail.Stats.Additions), Valid: true}
+	params.Deletions = pgtype.Int4{Int32: int32(detail.Stats.Deletions), Valid: true}
+	params.ChangedFiles = pgtype.Int4{Int32: int32(len(detail.Files)), Valid: true}
+	params.Extensions, _ = json.Marshal(extensions)
+	return params
+}
+
+// applyStats copies cached stats onto a commit. Commits without stats are
+// left as they are.
+func applyStats(c *Commit, s dbgen.InsertCommitStatsParams) {
+	if !s.Additions.Valid {
+		return
+	}
+	additions, deletions, files := int(s.Additions.Int32), int(s.Deletions.Int32), int(s.ChangedFiles.Int32)
+	c.Additions, c.Deletions, c.ChangedFiles = &additions, &deletions, &files
+	c.Extensions = nil
+	if len(s.Extensions) > 0 {
+		_ = json.Unmarshal(s.Extensions, &c.Extensions)
+	}
+}
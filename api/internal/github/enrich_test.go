@@ -0,0 +1,84 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitStatsParams(t *testing.T) {
+	detail := &CommitDetail{SHA: "abc123"}
+	detail.Stats.Additions = 40
+	detail.Stats.Deletions = 7
+	detail.Files = []struct {
+		Filename string `json:"filename"`
+	}{{"main.go"}, {"internal/x/y.GO"}, {"README.md"}, {"Makefile"}}
+
+	params := commitStatsParams("user/repo", "abc123", detail)
+
+	assert.Equal(t, "user/repo", params.Repo)
+	assert.Equal(t, int32(40), params.Additions.Int32)
+	assert.Equal(t, int32(7), params.Deletions.Int32)
+	assert.Equal(t, int32(4), params.ChangedFiles.Int32)
+	assert.JSONEq(t, `{"go":2,"md":1}`, string(params.Extensions))
+}
+
+func TestCommitStatsParams_Unavailable(t *testing.T) {
+	params := commitStatsParams("user/repo", "gone", nil)
+
+	assert.Equal(t, "gone", params.Sha)
+	assert.False(t, params.Additions.Valid)
+	assert.Nil(t, params.Extensions)
+}
+
+func TestApplyStats(t *testing.T) {
+	detail := &CommitDetail{}
+	detail.Stats.Additions = 3
+	detail.Files = []struct {
+		Filename string `json:"filename"`
+	}{{"a.ts"}}
+
+	push := storedActivity{Repo: "user/repo", Payload: Payload{Head: "b", Commits: []Commit{
+		{SHA: "a", Message: "first"},
+		{SHA: "b", Message: "second"},
+	}}}
+	applyStats(&push.Payload.Commits[0], commitStatsParams("user/repo", "a", detail))
+	applyStats(&push.Payload.Commits[1], commitStatsParams("user/repo", "b", nil))
+	push.Payload.StatsFetched = true
+
+	raw, err := json.Marshal(push)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"repo": "user/repo",
+		"payload": {
+			"head": "b",
+			"stats_fetched": true,
+			"commits": [
+				{"sha": "a", "message": "first", "additions": 3, "deletions": 0, "changed_files": 1, "extensions": {"ts": 1}},
+				{"sha": "b", "message": "second"}
+			]
+		}
+	}`, string(raw))
+}
+
+func TestResourceUnavailable(t *testing.T) {
+	assert.True(t, resourceUnavailable(&APIError{StatusCode: http.StatusNotFound}))
+	assert.True(t, resourceUnavailable(&APIError{StatusCode: http.StatusUnprocessableEntity}))
+	assert.False(t, resourceUnavailable(&APIError{StatusCode: http.StatusBadGateway}))
+	assert.False(t, resourceUnavailable(&RateLimitError{}))
+	assert.False(t, resourceUnavailable(errors.New("connection reset")))
+}
+
+func TestEnrichCommitsArgsKind(t *testing.T) {
+	assert.Equal(t, "github_commit_enrich", EnrichCommitsArgs{}.Kind())
+	opts := EnrichCommitsArgs{}.InsertOpts()
+	assert.True(t, opts.UniqueOpts.ByArgs)
+	// Jobs run no sooner than the window they're unique in
+	assert.Equal(t, enrichDelay, opts.UniqueOpts.ByPeriod)
+	assert.WithinDuration(t, time.Now().Add(enrichDelay), opts.ScheduledAt, time.Second)
+}
@@ -0,0 +1,300 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// contributionWindow is the span of one contributionsCollection query. It
+// keeps each repository's per-day commit contributions under one page of 100.
+const contributionWindow = 90 * 24 * time.Hour
+
+// CommitContribution is the number of commits a user made to a repository
+// on one day.
+type CommitContribution struct {
+	Repo  string
+	Date  time.Time
+	Count int
+}
+
+// PullRequestContribution is a pull request the user opened.
+type PullRequestContribution struct {
+	Repo       string
+	Number     int
+	Title      string
+	State      string
+	OccurredAt time.Time
+}
+
+// ReviewContribution is a pull request review the user submitted.
+type ReviewContribution struct {
+	Repo       string
+	PRNumber   int
+	ReviewID   int64
+	State      string
+	OccurredAt time.Time
+}
+
+// Contributions is a user's contribution history over a time range.
+type Contributions struct {
+	Commits      []CommitContribution
+	PullRequests []PullRequestContribution
+	Reviews      []ReviewContribution
+}
+
+// FetchContributions reads the authenticated user's contributions between
+// from and to from the GraphQL contributionsCollection, which reaches back
+// further than the Events API.
+func (c *Client) FetchContributions(ctx context.Context, token string, from, to time.Time) (*Contributions, error) {
+	result := &Contributions{}
+	for start := from; start.Before(to); start = start.Add(contributionWindow) {
+		end := start.Add(contributionWindow)
+		if end.After(to) {
+			end = to
+		}
+
+		commits, err := c.fetchCommitContributions(ctx, token, start, end)
+		if err != nil {
+			return nil, err
+		}
+		result.Commits = append(result.Commits, commits...)
+
+		prs, err := c.fetchPullRequestContributions(ctx, token, start, end)
+		if err != nil {
+			return nil, err
+		}
+		result.PullRequests = append(result.PullRequests, prs...)
+
+		reviews, err := c.fetchReviewContributions(ctx, token, start, end)
+		if err != nil {
+			return nil, err
+		}
+		result.Reviews = append(result.Reviews, reviews...)
+	}
+	return result, nil
+}
+
+const commitContributionsQuery = `query($from: DateTime!, $to: DateTime!) {
+  viewer {
+    contributionsCollection(from: $from, to: $to) {
+      commitContributionsByRepository(maxRepositories: 100) {
+        repository { nameWithOwner }
+        contributions(first: 100) {
+          nodes { occurredAt commitCount }
+        }
+      }
+    }
+  }
+}`
+
+func (c *Client) fetchCommitContributions(ctx context.Context, token string, from, to time.Time) ([]CommitContribution, error) {
+	var data struct {
+		Viewer struct {
+			ContributionsCollection struct {
+				CommitContributionsByRepository []struct {
+					Repository    graphqlRepo `json:"repository"`
+					Contributions struct {
+						Nodes []struct {
+							OccurredAt  time.Time `json:"occurredAt"`
+							CommitCount int       `json:"commitCount"`
+						} `json:"nodes"`
+					} `json:"contributions"`
+				} `json:"commitContributionsByRepository"`
+			} `json:"contributionsCollection"`
+		} `json:"viewer"`
+	}
+	vars := map[string]any{"from": from, "to": to}
+	if err := c.graphql(ctx, token, commitContributionsQuery, vars, &data); err != nil {
+		return nil, err
+	}
+
+	var commits []CommitContribution
+	for _, repo := range data.Viewer.ContributionsCollection.CommitContributionsByRepository {
+		for _, n := range repo.Contributions.Nodes {
+			commits = append(commits, CommitContribution{
+				Repo:  repo.Repository.NameWithOwner,
+				Date:  n.OccurredAt,
+				Count: n.CommitCount,
+			})
+		}
+	}
+	return commits, nil
+}
+
+const pullRequestContributionsQuery = `query($from: DateTime!, $to: DateTime!, $after: String) {
+  viewer {
+    contributionsCollection(from: $from, to: $to) {
+      pullRequestContributions(first: 100, after: $after) {
+        nodes {
+          occurredAt
+          pullRequest { number title state repository { nameWithOwner } }
+        }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+func (c *Client) fetchPullRequestContributions(ctx context.Context, token string, from, to time.Time) ([]PullRequestContribution, error) {
+	var prs []PullRequestContribution
+	var after *string
+	for {
+		var data struct {
+			Viewer struct {
+				ContributionsCollection struct {
+					PullRequestContributions struct {
+						Nodes []struct {
+							OccurredAt  time.Time `json:"occurredAt"`
+							PullRequest struct {
+								Number     int         `json:"number"`
+								Title      string      `json:"title"`
+								State      string      `json:"state"`
+								Repository graphqlRepo `json:"repository"`
+							} `json:"pullRequest"`
+						} `json:"nodes"`
+						PageInfo graphqlPageInfo `json:"pageInfo"`
+					} `json:"pullRequestContributions"`
+				} `json:"contributionsCollection"`
+			} `json:"viewer"`
+		}
+		vars := map[string]any{"from": from, "to": to, "after": after}
+		if err := c.graphql(ctx, token, pullRequestContributionsQuery, vars, &data); err != nil {
+			return nil, err
+		}
+
+		conn := data.Viewer.ContributionsCollection.PullRequestContributions
+		for _, n := range conn.Nodes {
+			prs = append(prs, PullRequestContribution{
+				Repo:       n.PullRequest.Repository.NameWithOwner,
+				Number:     n.PullRequest.Number,
+				Title:      n.PullRequest.Title,
+				State:      n.PullRequest.State,
+				OccurredAt: n.OccurredAt,
+			})
+		}
+		if !conn.PageInfo.HasNextPage {
+			return prs, nil
+		}
+		after = &conn.PageInfo.EndCursor
+	}
+}
+
+const reviewContributionsQuery = `query($from: DateTime!, $to: DateTime!, $after: String) {
+  viewer {
+    contributionsCollection(from: $from, to: $to) {
+      pullRequestReviewContributions(first: 100, after: $after) {
+        nodes {
+          occurredAt
+          pullRequestReview { databaseId state }
+          pullRequest { number repository { nameWithOwner } }
+        }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+func (c *Client) fetchReviewContributions(ctx context.Context, token string, from, to time.Time) ([]ReviewContribution, error) {
+	var reviews []ReviewContribution
+	var after *string
+	for {
+		var data struct {
+			Viewer struct {
+				ContributionsCollection struct {
+					PullRequestReviewContributions struct {
+						Nodes []struct {
+							OccurredAt        time.Time `json:"occurredAt"`
+							PullRequestReview struct {
+								DatabaseID int64  `json:"databaseId"`
+								State      string `json:"state"`
+							} `json:"pullRequestReview"`
+							PullRequest struct {
+								Number     int         `json:"number"`
+								Repository graphqlRepo `json:"repository"`
+							} `json:"pullRequest"`
+						} `json:"nodes"`
+						PageInfo graphqlPageInfo `json:"pageInfo"`
+					} `json:"pullRequestReviewContributions"`
+				} `json:"contributionsCollection"`
+			} `json:"viewer"`
+		}
+		vars := map[string]any{"from": from, "to": to, "after": after}
+		if err := c.graphql(ctx, token, reviewContributionsQuery, vars, &data); err != nil {
+			return nil, err
+		}
+
+		conn := data.Viewer.ContributionsCollection.PullRequestReviewContributions
+		for _, n := range conn.Nodes {
+			reviews = append(reviews, ReviewContribution{
+				Repo:       n.PullRequest.Repository.NameWithOwner,
+				PRNumber:   n.PullRequest.Number,
+				ReviewID:   n.PullRequestReview.DatabaseID,
+				State:      n.PullRequestReview.State,
+				OccurredAt: n.OccurredAt,
+			})
+		}
+		if !conn.PageInfo.HasNextPage {
+			return reviews, nil
+		}
+		after = &conn.PageInfo.EndCursor
+	}
+}
+
+type graphqlRepo struct {
+	NameWithOwner string `json:"nameWithOwner"`
+}
+
+type graphqlPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// graphql posts a query and decodes its data into out. HTTP failures map
+// to *RateLimitError or *APIError like the REST calls; GraphQL errors in a
+// 200 response are returned as a plain error.
+func (c *Client) graphql(ctx context.Context, token, query string, vars map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("encode graphql request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.graphqlURL(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("graphql request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, time.Now())
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decode graphql response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		if envelope.Errors[0].Type == "RATE_LIMITED" {
+			return &RateLimitError{ResetAt: time.Now().Add(secondaryLimitWait)}
+		}
+		return errors.New("github graphql: " + envelope.Errors[0].Message)
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
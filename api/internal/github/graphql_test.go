@@ -0,0 +1,148 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphqlStub answers the three contribution queries with canned data,
+// paging pull requests across two responses.
+func graphqlStub(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(req.Query, "commitContributionsByRepository"):
+			w.Write([]byte(`{"data":{"viewer":{"contributionsCollection":{"commitContributionsByRepository":[
+				{"repository":{"nameWithOwner":"user/repo"},"contributions":{"nodes":[
+					{"occurredAt":"2025-01-10T08:00:00Z","commitCount":3}]}}]}}}}`))
+		case strings.Contains(req.Query, "pullRequestReviewContributions"):
+			w.Write([]byte(`{"data":{"viewer":{"contributionsCollection":{"pullRequestReviewContributions":{
+				"nodes":[{"occurredAt":"2025-01-12T09:00:00Z","pullRequestReview":{"databaseId":99,"state":"APPROVED"},
+					"pullRequest":{"number":5,"repository":{"nameWithOwner":"org/other"}}}],
+				"pageInfo":{"hasNextPage":false,"endCursor":""}}}}}}`))
+		case strings.Contains(req.Query, "pullRequestContributions"):
+			if req.Variables["after"] == nil {
+				w.Write([]byte(`{"data":{"viewer":{"contributionsCollection":{"pullRequestContributions":{
+					"nodes":[{"occurredAt":"2025-01-11T09:00:00Z","pullRequest":{"number":7,"title":"Add x","state":"MERGED","repository":{"nameWithOwner":"user/repo"}}}],
+					"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}}`))
+				return
+			}
+			assert.Equal(t, "c1", req.Variables["after"])
+			w.Write([]byte(`{"data":{"viewer":{"contributionsCollection":{"pullRequestContributions":{
+				"nodes":[{"occurredAt":"2025-01-13T09:00:00Z","pullRequest":{"number":8,"title":"Fix y","state":"OPEN","repository":{"nameWithOwner":"user/repo"}}}],
+				"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}}}`))
+		default:
+			t.Fatalf("unexpected query: %s", req.Query)
+		}
+	}))
+}
+
+func TestFetchContributions(t *testing.T) {
+	srv := graphqlStub(t)
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := client.FetchContributions(t.Context(), "test-token", from, from.AddDate(0, 0, 30))
+	require.NoError(t, err)
+
+	require.Len(t, c.Commits, 1)
+	assert.Equal(t, "user/repo", c.Commits[0].Repo)
+	assert.Equal(t, 3, c.Commits[0].Count)
+
+	require.Len(t, c.PullRequests, 2)
+	assert.Equal(t, 7, c.PullRequests[0].Number)
+	assert.Equal(t, "MERGED", c.PullRequests[0].State)
+	assert.Equal(t, 8, c.PullRequests[1].Number)
+
+	require.Len(t, c.Reviews, 1)
+	assert.Equal(t, int64(99), c.Reviews[0].ReviewID)
+	assert.Equal(t, 5, c.Reviews[0].PRNumber)
+}
+
+func TestFetchContributions_SplitsIntoWindows(t *testing.T) {
+	var windows int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if strings.Contains(req.Query, "commitContributionsByRepository") {
+			windows++
+		}
+		w.Write([]byte(`{"data":{"viewer":{"contributionsCollection":{}}}}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	to := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	_, err := client.FetchContributions(t.Context(), "test-token", to.AddDate(0, 0, -365), to)
+	require.NoError(t, err)
+	assert.Equal(t, 5, windows)
+}
+
+func TestFetchContributions_RateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`))
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	to := time.Now()
+	_, err := client.FetchContributions(t.Context(), "test-token", to.AddDate(0, 0, -1), to)
+
+	var rl *RateLimitError
+	require.ErrorAs(t, err, &rl)
+}
+
+func TestContributionEvents(t *testing.T) {
+	cutoff := time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC)
+	c := &Contributions{
+		Commits: []CommitContribution{
+			{Repo: "user/repo", Date: time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC), Count: 3},
+			// Same day as the first polled push: left to the polled events
+			{Repo: "user/repo", Date: time.Date(2025, 1, 11, 8, 0, 0, 0, time.UTC), Count: 2},
+		},
+		PullRequests: []PullRequestContribution{
+			{Repo: "user/repo", Number: 7, Title: "Add x", State: "MERGED", OccurredAt: cutoff},
+		},
+		Reviews: []ReviewContribution{
+			{Repo: "org/other", PRNumber: 5, ReviewID: 99, State: "APPROVED", OccurredAt: cutoff},
+		},
+	}
+
+	events := contributionEvents(c, cutoff)
+	require.Len(t, events, 3)
+
+	assert.Equal(t, "contrib:user/repo:2025-01-10", externalID(events[0]))
+	assert.Equal(t, 3, events[0].Payload.DistinctSize)
+
+	assert.Equal(t, "pull_request:user/repo:7:opened", externalID(events[1]))
+	assert.Equal(t, "closed", events[1].Payload.PullRequest.State)
+
+	assert.Equal(t, "review:org/other:99", externalID(events[2]))
+	assert.Equal(t, "approved", events[2].Payload.Review.State)
+}
+
+func TestHistoryImportArgs(t *testing.T) {
+	args := HistoryImportArgs{UserID: 42}
+	assert.Equal(t, "github_history_import", args.Kind())
+	assert.True(t, args.InsertOpts().UniqueOpts.ByArgs)
+}
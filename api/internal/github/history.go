@@ -0,0 +1,207 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/backfill"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
+)
+
+// historyDays is how far back the import reaches; the heatmap shows a year.
+const historyDays = 365
+
+// historyImportTimeout bounds one import attempt, which pages through a
+// year of contributions in several GraphQL calls.
+const historyImportTimeout = 10 * time.Minute
+
+// HistoryImportArgs are the arguments for importing a user's contribution
+// history after they first connect GitHub.
+type HistoryImportArgs struct {
+	UserID int64 `json:"user_id"`
+	// Provider names the GitHub deployment; empty means github.com.
+	Provider string `json:"provider,omitempty"`
+}
+
+func (HistoryImportArgs) Kind() string { return "github_history_import" }
+
+func (HistoryImportArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		MaxAttempts: 5,
+		UniqueOpts:  riverlib.UniqueOpts{ByArgs: true},
+	}
+}
+
+// HistoryImportWorker seeds a year of activities from the GraphQL
+// contributionsCollection and then backfills the daily summaries.
+type HistoryImportWorker struct {
+	riverlib.WorkerDefaults[HistoryImportArgs]
+	q       *dbgen.Queries
+	hosts   Hosts
+	keyring *tokencrypt.Keyring
+}
+
+func NewHistoryImportWorker(q *dbgen.Queries, hosts Hosts, keyring *tokencrypt.Keyring) *HistoryImportWorker {
+	return &HistoryImportWorker{q: q, hosts: hosts, keyring: keyring}
+}
+
+func (w *HistoryImportWorker) Timeout(job *riverlib.Job[HistoryImportArgs]) time.Duration {
+	return historyImportTimeout
+}
+
+func (w *HistoryImportWorker) Work(ctx context.Context, job *riverlib.Job[HistoryImportArgs]) error {
+	userID := job.Args.UserID
+	source, gh, err := w.hosts.lookup(job.Args.Provider)
+	if err != nil {
+		return err
+	}
+
+	ds, err := w.q.GetDataSourceByUserAndProvider(ctx, dbgen.GetDataSourceByUserAndProviderParams{
+		UserID:   userID,
+		Provider: source,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("%s data source for user %d not found", source, userID))
+	}
+	if err != nil {
+		return err
+	}
+	if ds.HistoryImportedAt.Valid {
+		return nil
+	}
+
+	token, err := w.keyring.Decrypt(ds.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -historyDays)
+	contribs, err := gh.FetchContributions(ctx, string(token), from, to)
+	var rateLimited *RateLimitError
+	if errors.As(err, &rateLimited) {
+		return riverlib.JobSnooze(rateLimited.Wait(time.Now()))
+	}
+	if err != nil {
+		return err
+	}
+
+	earliest, err := w.q.GetEarliestPushTime(ctx, dbgen.GetEarliestPushTimeParams{UserID: userID, Source: source})
+	if err != nil {
+		return err
+	}
+	cutoff := to
+	if earliest.Valid {
+		cutoff = earliest.Time
+	}
+
+	var inserted int
+	for _, evt := range contributionEvents(contribs, cutoff) {
+		n, err := w.q.InsertActivity(ctx, ActivityParams(userID, source, evt))
+		if err != nil {
+			return fmt.Errorf("insert contribution %s: %w", externalID(evt), err)
+		}
+		inserted += int(n)
+	}
+
+	if err := w.q.SetHistoryImported(ctx, dbgen.SetHistoryImportedParams{UserID: userID, Provider: source}); err != nil {
+		return err
+	}
+
+	tz, err := w.q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return err
+	}
+	loc := summary.LoadLocation(tz)
+	start, _ := time.Parse(time.DateOnly, from.In(loc).Format(time.DateOnly))
+	end, _ := time.Parse(time.DateOnly, to.In(loc).Format(time.DateOnly))
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	bf, err := backfill.Enqueue(ctx, w.q, client, userID, start, end)
+	if err != nil {
+		return err
+	}
+	// The imported pull requests need their review and merge times, and
+	// the imported reviews their authors and request times
+	if _, err := client.Insert(ctx, PullRequestDetailsArgs{UserID: userID, Provider: source}, nil); err != nil {
+		return err
+	}
+	if _, err := client.Insert(ctx, EnrichReviewsArgs{UserID: userID, Provider: source}, nil); err != nil {
+		return err
+	}
+
+	slog.Info("github history imported", "user_id", userID, "provider", source, "inserted", inserted, "backfill_id", bf.ID)
+	return nil
+}
+
+// contributionEvents converts contributions into events so they share
+// ActivityParams with the sync and webhook paths. Pull requests and reviews
+// get the same external IDs as their Events API counterparts and dedupe.
+// Commit contributions only carry per-day counts, so days that aren't
+// entirely before cutoff (the first polled push) are skipped rather than
+// double counted against pushes with exact SHAs.
+func contributionEvents(c *Contributions, cutoff time.Time) []Event {
+	var events []Event
+	for _, cc := range c.Commits {
+		if cc.Count <= 0 || cc.Date.Add(24*time.Hour).After(cutoff) {
+			continue
+		}
+		events = append(events, Event{
+			ID:        fmt.Sprintf("contrib:%s:%s", cc.Repo, cc.Date.Format(time.DateOnly)),
+			Type:      "PushEvent",
+			Repo:      Repo{Name: cc.Repo},
+			CreatedAt: cc.Date,
+			Payload:   Payload{Size: cc.Count, DistinctSize: cc.Count},
+		})
+	}
+	for _, pr := range c.PullRequests {
+		events = append(events, Event{
+			Type:      "PullRequestEvent",
+			Repo:      Repo{Name: pr.Repo},
+			CreatedAt: pr.OccurredAt,
+			Payload: Payload{
+				Action: "opened",
+				Number: pr.Number,
+				PullRequest: &PullRequest{
+					Number: pr.Number,
+					Title:  pr.Title,
+					State:  restPullRequestState(pr.State),
+				},
+			},
+		})
+	}
+	for _, r := range c.Reviews {
+		events = append(events, Event{
+			Type:      "PullRequestReviewEvent",
+			Repo:      Repo{Name: r.Repo},
+			CreatedAt: r.OccurredAt,
+			Payload: Payload{
+				Action: "created",
+				Number: r.PRNumber,
+				Review: &Review{
+					ID:          r.ReviewID,
+					State:       strings.ToLower(r.State),
+					SubmittedAt: r.OccurredAt,
+				},
+			},
+		})
+	}
+	return events
+}
+
+// restPullRequestState maps GraphQL's OPEN/CLOSED/MERGED to the REST
+// open/closed states stored by the other paths.
+func restPullRequestState(state string) string {
+	if state == "OPEN" {
+		return "open"
+	}
+	return "closed"
+}
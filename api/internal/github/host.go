@@ -0,0 +1,31 @@
+package github
+
+import (
+	"fmt"
+
+	riverlib "github.com/riverqueue/river"
+)
+
+// IsProvider reports whether name is the key of a GitHub deployment.
+func IsProvider(name string) bool {
+	return name == ProviderName || name == EnterpriseProviderName
+}
+
+// Hosts holds the API client of each enabled GitHub deployment, keyed by
+// its provider name.
+type Hosts map[string]*Client
+
+// lookup returns the provider name and client a job works against. Jobs
+// enqueued before Enterprise Server support carry no provider and belong
+// to github.com. Jobs for a deployment that is no longer enabled are
+// cancelled.
+func (h Hosts) lookup(name string) (string, *Client, error) {
+	if name == "" {
+		name = ProviderName
+	}
+	client, ok := h[name]
+	if !ok {
+		return name, nil, riverlib.JobCancel(fmt.Errorf("%s is not enabled", name))
+	}
+	return name, client, nil
+}
@@ -0,0 +1,22 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHosts_Lookup(t *testing.T) {
+	dotcom := NewClient(nil, "https://api.github.com")
+	hosts := Hosts{ProviderName: dotcom}
+
+	// Jobs without a provider predate Enterprise Server support
+	name, client, err := hosts.lookup("")
+	require.NoError(t, err)
+	assert.Equal(t, ProviderName, name)
+	assert.Same(t, dotcom, client)
+
+	_, _, err = hosts.lookup(EnterpriseProviderName)
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
+)
+
+// languageCacheTTL is how long a cached repo language breakdown is used
+// before it is fetched again. Language mixes change slowly.
+const languageCacheTTL = 7 * 24 * time.Hour
+
+// missingLanguageTTL is how long an empty breakdown, cached when a repo
+// wasn't found, is used. The cache is shared, and the repo may be visible
+// to other users.
+const missingLanguageTTL = time.Hour
+
+// LanguageResolver looks up repository languages through the GitHub API,
+// caching results in repo_languages.
+type LanguageResolver struct {
+	q       *dbgen.Queries
+	hosts   Hosts
+	keyring *tokencrypt.Keyring
+}
+
+func NewLanguageResolver(q *dbgen.Queries, hosts Hosts, keyring *tokencrypt.Keyring) *LanguageResolver {
+	return &LanguageResolver{q: q, hosts: hosts, keyring: keyring}
+}
+
+// RepoLanguages returns bytes per language for repo, using the user's token
+// for cache misses so private repositories resolve. A stale cache entry is
+// returned if the refresh fails. source is the activity source, which
+// names the GitHub deployment the repo lives on. Returns nil for repos of
+// other sources, and if the user hasn't connected that deployment and
+// nothing is cached.
+func (r *LanguageResolver) RepoLanguages(ctx context.Context, userID int64, source, repo string) (map[string]int64, error) {
+	gh, ok := r.hosts[source]
+	if !ok {
+		return nil, nil
+	}
+
+	cached, fresh, err := r.cached(ctx, source, repo)
+	if err != nil || fresh {
+		return cached, err
+	}
+
+	languages, err := r.fetch(ctx, gh, userID, source, repo)
+	if err != nil {
+		if cached != nil {
+			slog.Warn("refresh repo languages failed, using cache", "source", source, "repo", repo, "error", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	if languages == nil {
+		return cached, nil
+	}
+
+	raw, _ := json.Marshal(languages)
+	if err := r.q.UpsertRepoLanguages(ctx, dbgen.UpsertRepoLanguagesParams{Source: source, Repo: repo, Languages: raw}); err != nil {
+		slog.Error("cache repo languages failed", "source", source, "repo", repo, "error", err)
+	}
+	return languages, nil
+}
+
+// CachedRepoLanguages returns the cached breakdown of repo, however old,
+// without calling the API. Returns nil if nothing is cached.
+func (r *LanguageResolver) CachedRepoLanguages(ctx context.Context, source, repo string) (map[string]int64, error) {
+	if _, ok := r.hosts[source]; !ok {
+		return nil, nil
+	}
+	cached, _, err := r.cached(ctx, source, repo)
+	return cached, err
+}
+
+// cached reads the cached breakdown of repo and reports whether it is
+// still within its TTL.
+func (r *LanguageResolver) cached(ctx context.Context, source, repo string) (map[string]int64, bool, error) {
+	row, err := r.q.GetRepoLanguages(ctx, dbgen.GetRepoLanguagesParams{Source: source, Repo: repo})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var languages map[string]int64
+	if err := json.Unmarshal(row.Languages, &languages); err != nil {
+		return nil, false, err
+	}
+	ttl := languageCacheTTL
+	if len(languages) == 0 {
+		ttl = missingLanguageTTL
+	}
+	return languages, time.Since(row.FetchedAt.Time) < ttl, nil
+}
+
+// fetch calls the deployment's API with the user's token for it. Missing
+// or inaccessible repos yield an empty map so they are cached briefly
+// rather than retried on every run. Returns nil if the user hasn't
+// connected the deployment.
+func (r *LanguageResolver) fetch(ctx context.Context, gh *Client, userID int64, source, repo string) (map[string]int64, error) {
+	ds, err := r.q.GetDataSourceByUserAndProvider(ctx, dbgen.GetDataSourceByUserAndProviderParams{
+		UserID:   userID,
+		Provider: source,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := r.keyring.Decrypt(ds.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	languages, err := gh.FetchRepoLanguages(ctx, string(token), repo)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return map[string]int64{}, nil
+	}
+	return languages, err
+}
@@ -0,0 +1,18 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoLanguages_OtherSource(t *testing.T) {
+	// A GitLab repo named like a GitHub one must not get its languages
+	r := NewLanguageResolver(nil, Hosts{ProviderName: nil}, nil)
+
+	langs, err := r.RepoLanguages(context.Background(), 1, "gitlab", "acme/api")
+	require.NoError(t, err)
+	assert.Nil(t, langs)
+}
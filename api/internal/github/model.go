@@ -20,21 +20,144 @@ type Repo struct {
 // Payload contains event-type-specific data.
 type Payload struct {
 	// PushEvent
-	Commits []Commit `json:"commits,omitempty"`
-	Size    int      `json:"size,omitempty"`
-	// PullRequestEvent
-	Action      string       `json:"action,omitempty"`
+	Commits      []Commit `json:"commits,omitempty"`
+	Size         int      `json:"size,omitempty"`
+	DistinctSize int      `json:"distinct_size,omitempty"`
+	Head         string   `json:"head,omitempty"`
+	// StatsFetched is set once commit enrichment has looked up the
+	// commits' stats, whether or not the API returned them.
+	StatsFetched bool `json:"stats_fetched,omitempty"`
+	// PushEvent, CreateEvent, DeleteEvent
+	Ref string `json:"ref,omitempty"`
+	// CreateEvent, DeleteEvent
+	RefType string `json:"ref_type,omitempty"`
+	// PullRequestEvent, PullRequestReviewEvent, IssuesEvent, ReleaseEvent,
+	// WatchEvent and the comment events
+	Action string `json:"action,omitempty"`
+	// PullRequestEvent, PullRequestReviewEvent, PullRequestReviewCommentEvent
+	Number      int          `json:"number,omitempty"`
 	PullRequest *PullRequest `json:"pull_request,omitempty"`
+	// PullRequestReviewEvent
+	Review *Review `json:"review,omitempty"`
+	// RequestedAt is when the user's review was last requested before they
+	// submitted it, or when the pull request was opened if it never was.
+	// ReviewFetched is set once review enrichment has looked both up.
+	RequestedAt   *time.Time `json:"requested_at,omitempty"`
+	ReviewFetched bool       `json:"review_fetched,omitempty"`
+	// IssuesEvent, IssueCommentEvent
+	Issue *Issue `json:"issue,omitempty"`
+	// IssueCommentEvent, PullRequestReviewCommentEvent
+	Comment *Comment `json:"comment,omitempty"`
+	// ReleaseEvent
+	Release *Release `json:"release,omitempty"`
+	// ForkEvent
+	Forkee *Forkee `json:"forkee,omitempty"`
 }
 
-// Commit represents a commit within a PushEvent payload.
+// Commit represents a commit within a PushEvent payload. The stats are
+// filled in by commit enrichment and stay nil if the commit couldn't be
+// fetched. Extensions counts changed files by lowercase extension.
 type Commit struct {
-	SHA     string `json:"sha"`
-	Message string `json:"message"`
+	SHA          string         `json:"sha"`
+	Message      string         `json:"message"`
+	Additions    *int           `json:"additions,omitempty"`
+	Deletions    *int           `json:"deletions,omitempty"`
+	ChangedFiles *int           `json:"changed_files,omitempty"`
+	Extensions   map[string]int `json:"extensions,omitempty"`
+}
+
+// Actor identifies a GitHub account.
+type Actor struct {
+	Login string `json:"login"`
 }
 
 // PullRequest represents a pull request within a PullRequestEvent payload.
+// Merged is only meaningful on the closed action. User is the author.
 type PullRequest struct {
-	Title string `json:"title"`
-	State string `json:"state"`
+	Number int    `json:"number,omitempty"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Merged bool   `json:"merged,omitempty"`
+	User   *Actor `json:"user,omitempty"`
+}
+
+// Review represents a review within a PullRequestReviewEvent payload.
+// User is the reviewer.
+type Review struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	User        *Actor    `json:"user,omitempty"`
+}
+
+// Issue represents an issue within IssuesEvent and IssueCommentEvent payloads.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+}
+
+// Comment identifies a comment within IssueCommentEvent and
+// PullRequestReviewCommentEvent payloads. The body is not kept.
+type Comment struct {
+	ID int64 `json:"id"`
+}
+
+// Release represents a release within a ReleaseEvent payload.
+type Release struct {
+	ID      int64  `json:"id"`
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+}
+
+// Forkee identifies the repository created by a ForkEvent.
+type Forkee struct {
+	FullName string `json:"full_name"`
+}
+
+// CommitDetail is a single commit from the commits API.
+// https://docs.github.com/en/rest/commits/commits#get-a-commit
+type CommitDetail struct {
+	SHA   string `json:"sha"`
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+	// Files lists at most 300 files; Stats covers the whole commit.
+	Files []struct {
+		Filename string `json:"filename"`
+	} `json:"files"`
+}
+
+// PullRequestDetail is a single pull request from the pulls API.
+// https://docs.github.com/en/rest/pulls/pulls#get-a-pull-request
+type PullRequestDetail struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	User      Actor      `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+// PullRequestReview is a review from the pull request reviews API. Pending
+// reviews have no SubmittedAt.
+// https://docs.github.com/en/rest/pulls/reviews#list-reviews-for-a-pull-request
+type PullRequestReview struct {
+	User        Actor      `json:"user"`
+	State       string     `json:"state"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+}
+
+// IssueEvent is an entry of an issue or pull request's event list. Only
+// review requests are read.
+// https://docs.github.com/en/rest/issues/events#list-issue-events
+type IssueEvent struct {
+	Event             string    `json:"event"`
+	CreatedAt         time.Time `json:"created_at"`
+	RequestedReviewer *Actor    `json:"requested_reviewer"`
+	RequestedTeam     *struct {
+		Slug string `json:"slug"`
+	} `json:"requested_team"`
 }
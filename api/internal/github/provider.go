@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+// ProviderName is the data source and activity source key for github.com.
+const ProviderName = "github"
+
+// EnterpriseProviderName is the data source and activity source key for a
+// GitHub Enterprise Server, which users can connect alongside github.com.
+const EnterpriseProviderName = "github_enterprise"
+
+// Provider syncs a GitHub deployment through the user Events API.
+type Provider struct {
+	name   string
+	client *Client
+	oauth  provider.OAuthConfig
+}
+
+// NewProvider creates the provider for the GitHub deployment at webURL,
+// such as "https://github.com", using the given OAuth app registered on
+// it. name is the data source key: ProviderName for github.com and
+// EnterpriseProviderName for an Enterprise Server.
+func NewProvider(name, webURL string, client *Client, creds provider.Credentials) *Provider {
+	webURL = strings.TrimRight(webURL, "/")
+	return &Provider{
+		name:   name,
+		client: client,
+		oauth: provider.OAuthConfig{
+			Credentials:  creds,
+			AuthorizeURL: webURL + "/login/oauth/authorize",
+			TokenURL:     webURL + "/login/oauth/access_token",
+			Scopes:       []string{"read:user", "repo"},
+			PKCE:         true,
+		},
+	}
+}
+
+func (p *Provider) Name() string { return p.name }
+
+func (p *Provider) OAuthConfig() provider.OAuthConfig { return p.oauth }
+
+func (p *Provider) ExchangeCode(ctx context.Context, code, verifier string) (*provider.Token, error) {
+	return provider.ExchangeCode(ctx, p.oauth, code, verifier)
+}
+
+// Fetch reads the user's recent events, using cursor as the ETag. Rate
+// limit responses are returned as *RateLimitError.
+func (p *Provider) Fetch(ctx context.Context, token, cursor string) (*provider.FetchResult, error) {
+	events, err := p.client.FetchUserEvents(ctx, token, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &provider.FetchResult{
+		Cursor:       events.ETag,
+		PollInterval: events.PollInterval,
+		NotModified:  events.NotModified,
+	}
+	for _, evt := range events.Events {
+		if SupportedEventTypes[evt.Type] {
+			result.Activities = append(result.Activities, toActivity(evt))
+		}
+	}
+	return result, nil
+}
+
+func (p *Provider) SupportedTypes() []string {
+	types := make([]string, 0, len(SupportedEventTypes))
+	for ghType := range SupportedEventTypes {
+		types = append(types, mapEventType(ghType))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// AfterSync schedules enrichment of the pushes and reviews the sync stored,
+// the pull request details lookup, and the one-time contribution history
+// import for accounts that haven't had one. The import runs after a sync so it can
+// tell which days the polled pushes already cover.
+func (p *Provider) AfterSync(ctx context.Context, ds dbgen.DataSource) error {
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	if _, err := client.Insert(ctx, EnrichCommitsArgs{UserID: ds.UserID, Provider: p.name}, nil); err != nil {
+		return err
+	}
+	if _, err := client.Insert(ctx, EnrichReviewsArgs{UserID: ds.UserID, Provider: p.name}, nil); err != nil {
+		return err
+	}
+	if _, err := client.Insert(ctx, PullRequestDetailsArgs{UserID: ds.UserID, Provider: p.name}, nil); err != nil {
+		return err
+	}
+	if ds.HistoryImportedAt.Valid {
+		return nil
+	}
+	_, err := client.Insert(ctx, HistoryImportArgs{UserID: ds.UserID, Provider: p.name}, nil)
+	return err
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+func TestProvider_Fetch(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	events := []Event{
+		{ID: "1", Type: "PushEvent", Repo: Repo{Name: "user/repo"}, CreatedAt: now, Payload: Payload{Head: "abc123", Size: 1}},
+		{ID: "2", Type: "GollumEvent", Repo: Repo{Name: "user/repo"}, CreatedAt: now},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `"old"`, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"new"`)
+		w.Header().Set("X-Poll-Interval", "60")
+		json.NewEncoder(w).Encode(events)
+	}))
+	defer srv.Close()
+
+	p := NewProvider(ProviderName, "https://github.com", newTestClient(srv.URL), provider.Credentials{})
+	result, err := p.Fetch(context.Background(), "test-token", `"old"`)
+
+	require.NoError(t, err)
+	assert.Equal(t, `"new"`, result.Cursor)
+	assert.Equal(t, time.Minute, result.PollInterval)
+
+	// Unsupported event types are dropped
+	require.Len(t, result.Activities, 1)
+	act := result.Activities[0]
+	assert.Equal(t, "push", act.Type)
+	assert.Equal(t, "push:user/repo:abc123", act.ExternalID)
+	assert.Equal(t, "1", act.LegacyExternalID)
+	assert.Equal(t, now, act.OccurredAt)
+	assert.False(t, act.Mutable)
+}
+
+func TestProvider_FetchRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	p := NewProvider(ProviderName, "https://github.com", newTestClient(srv.URL), provider.Credentials{})
+	_, err := p.Fetch(context.Background(), "test-token", "")
+
+	var rl provider.RateLimited
+	require.ErrorAs(t, err, &rl)
+	assert.Equal(t, 30*time.Second, rl.Wait(time.Now()).Round(time.Second))
+}
+
+func TestProvider_SupportedTypes(t *testing.T) {
+	p := NewProvider(ProviderName, "https://github.com", nil, provider.Credentials{})
+	assert.Equal(t, "github", p.Name())
+	assert.Equal(t, []string{
+		"create", "delete", "fork", "issue", "issue_comment", "pull_request",
+		"push", "release", "review", "review_comment", "star",
+	}, p.SupportedTypes())
+	assert.True(t, p.OAuthConfig().PKCE)
+}
+
+func TestProvider_Enterprise(t *testing.T) {
+	p := NewProvider(EnterpriseProviderName, "https://ghe.example.com/", nil, provider.Credentials{})
+	assert.Equal(t, "github_enterprise", p.Name())
+	assert.Equal(t, "https://ghe.example.com/login/oauth/authorize", p.OAuthConfig().AuthorizeURL)
+	assert.Equal(t, "https://ghe.example.com/login/oauth/access_token", p.OAuthConfig().TokenURL)
+}
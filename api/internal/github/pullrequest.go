@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/pullrequest"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
+)
+
+// prDetailsBatch is how many pull requests are loaded per query.
+const prDetailsBatch = 50
+
+// prDetailsTimeout bounds one attempt. The first run after the history
+// import looks up a year of pull requests, two requests each.
+const prDetailsTimeout = 10 * time.Minute
+
+// PullRequestDetailsArgs are the arguments for looking up the review and
+// merge times of a user's GitHub pull requests.
+type PullRequestDetailsArgs struct {
+	UserID int64 `json:"user_id"`
+	// Provider names the GitHub deployment; empty means github.com.
+	Provider string `json:"provider,omitempty"`
+}
+
+func (PullRequestDetailsArgs) Kind() string { return "github_pull_request_details" }
+
+// InsertOpts dedupes against jobs that haven't finished yet, so every sync
+// can ask for details without piling up jobs.
+func (PullRequestDetailsArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		ScheduledAt: time.Now().Add(enrichDelay),
+		UniqueOpts:  riversetup.UniqueUnfinished(enrichDelay),
+	}
+}
+
+// PullRequestDetailsWorker fills in what the user's own events can't tell
+// about their pull requests: when others first reviewed and approved them,
+// and merges and closes the events missed. It projects the activities
+// first, then fetches pull requests never fetched before and open ones
+// again at most hourly.
+type PullRequestDetailsWorker struct {
+	riverlib.WorkerDefaults[PullRequestDetailsArgs]
+	q       *dbgen.Queries
+	hosts   Hosts
+	keyring *tokencrypt.Keyring
+}
+
+func NewPullRequestDetailsWorker(q *dbgen.Queries, hosts Hosts, keyring *tokencrypt.Keyring) *PullRequestDetailsWorker {
+	return &PullRequestDetailsWorker{q: q, hosts: hosts, keyring: keyring}
+}
+
+func (w *PullRequestDetailsWorker) Timeout(job *riverlib.Job[PullRequestDetailsArgs]) time.Duration {
+	return prDetailsTimeout
+}
+
+func (w *PullRequestDetailsWorker) Work(ctx context.Context, job *riverlib.Job[PullRequestDetailsArgs]) error {
+	userID := job.Args.UserID
+	source, gh, err := w.hosts.lookup(job.Args.Provider)
+	if err != nil {
+		return err
+	}
+
+	ds, err := w.q.GetDataSourceByUserAndProvider(ctx, dbgen.GetDataSourceByUserAndProviderParams{
+		UserID:   userID,
+		Provider: source,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("%s data source for user %d not found", source, userID))
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := w.keyring.Decrypt(ds.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	if err := pullrequest.Project(ctx, w.q, userID); err != nil {
+		return err
+	}
+
+	err = w.fetchAll(ctx, gh, userID, source, string(token))
+	var rateLimited *RateLimitError
+	if errors.As(err, &rateLimited) {
+		return riverlib.JobSnooze(rateLimited.Wait(time.Now()))
+	}
+	return err
+}
+
+// fetchAll looks up the pull requests due for a fetch batch by batch.
+func (w *PullRequestDetailsWorker) fetchAll(ctx context.Context, gh *Client, userID int64, source, token string) error {
+	for {
+		rows, err := w.q.ListPullRequestsToFetch(ctx, dbgen.ListPullRequestsToFetchParams{
+			UserID: userID,
+			Source: source,
+			Limit:  prDetailsBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := w.fetch(ctx, gh, userID, source, token, row); err != nil {
+				return fmt.Errorf("fetch pull request %s#%d: %w", row.Repo, row.Number, err)
+			}
+		}
+
+		if len(rows) < prDetailsBatch {
+			return nil
+		}
+	}
+}
+
+// fetch stores the details of one pull request. Pull requests the API
+// can't return are marked fetched as they are.
+func (w *PullRequestDetailsWorker) fetch(ctx context.Context, gh *Client, userID int64, source, token string, row dbgen.ListPullRequestsToFetchRow) error {
+	pr, err := gh.FetchPullRequest(ctx, token, row.Repo, int(row.Number))
+	if err != nil && !resourceUnavailable(err) {
+		return err
+	}
+	if pr != nil {
+		reviews, err := gh.FetchPullRequestReviews(ctx, token, row.Repo, int(row.Number))
+		if err != nil && !resourceUnavailable(err) {
+			return err
+		}
+		if err := w.q.UpsertPullRequest(ctx, pullRequestParams(userID, source, row.Repo, pr, reviews, time.Now())); err != nil {
+			return err
+		}
+	}
+	return w.q.MarkPullRequestFetched(ctx, row.ID)
+}
+
+// pullRequestParams builds the lifecycle of a fetched pull request as of
+// now. The first review is the earliest submitted review by someone other
+// than the author; replying to review comments also creates reviews.
+func pullRequestParams(userID int64, source, repo string, pr *PullRequestDetail, reviews []PullRequestReview, now time.Time) dbgen.UpsertPullRequestParams {
+	l := pullrequest.Lifecycle{
+		Source:   source,
+		Repo:     repo,
+		Number:   pr.Number,
+		Title:    pr.Title,
+		State:    pullrequest.StateOpen,
+		StateAt:  now,
+		OpenedAt: pr.CreatedAt,
+	}
+	if pr.ClosedAt != nil {
+		l.State, l.ClosedAt = pullrequest.StateClosed, *pr.ClosedAt
+	}
+	if pr.MergedAt != nil {
+		l.State, l.MergedAt = pullrequest.StateMerged, *pr.MergedAt
+	}
+	params := l.UpsertParams(userID)
+
+	var firstReview, approved time.Time
+	for _, r := range reviews {
+		if r.SubmittedAt == nil || r.State == "PENDING" || r.User.Login == pr.User.Login {
+			continue
+		}
+		if firstReview.IsZero() || r.SubmittedAt.Before(firstReview) {
+			firstReview = *r.SubmittedAt
+		}
+		if r.State == "APPROVED" && (approved.IsZero() || r.SubmittedAt.Before(approved)) {
+			approved = *r.SubmittedAt
+		}
+	}
+	params.FirstReviewAt = pullrequest.Timestamptz(firstReview)
+	params.ApprovedAt = pullrequest.Timestamptz(approved)
+	return params
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestParams(t *testing.T) {
+	var pr PullRequestDetail
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"number": 7, "title": "Add feature", "state": "closed",
+		"user": {"login": "me"},
+		"created_at": "2026-03-01T09:00:00Z",
+		"merged_at": "2026-03-03T09:00:00Z",
+		"closed_at": "2026-03-03T09:00:00Z"
+	}`), &pr))
+	var reviews []PullRequestReview
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"user": {"login": "me"}, "state": "COMMENTED", "submitted_at": "2026-03-01T10:00:00Z"},
+		{"user": {"login": "bob"}, "state": "PENDING"},
+		{"user": {"login": "alice"}, "state": "CHANGES_REQUESTED", "submitted_at": "2026-03-02T09:00:00Z"},
+		{"user": {"login": "alice"}, "state": "APPROVED", "submitted_at": "2026-03-02T15:00:00Z"}
+	]`), &reviews))
+	now := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	params := pullRequestParams(1, ProviderName, "user/repo", &pr, reviews, now)
+
+	assert.Equal(t, "github", params.Source)
+	assert.Equal(t, int32(7), params.Number)
+	assert.Equal(t, "merged", params.State)
+	assert.Equal(t, now, params.StateAt.Time)
+	assert.Equal(t, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), params.OpenedAt.Time)
+	// The author's own comment doesn't count as a review
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), params.FirstReviewAt.Time)
+	assert.Equal(t, time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC), params.ApprovedAt.Time)
+	assert.Equal(t, time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), params.MergedAt.Time)
+	assert.True(t, params.ClosedAt.Valid)
+}
+
+func TestPullRequestParams_OpenWithoutReviews(t *testing.T) {
+	pr := &PullRequestDetail{Number: 8, State: "open", CreatedAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+
+	params := pullRequestParams(1, ProviderName, "user/repo", pr, nil, time.Now())
+
+	assert.Equal(t, "open", params.State)
+	assert.False(t, params.FirstReviewAt.Valid)
+	assert.False(t, params.ApprovedAt.Valid)
+	assert.False(t, params.MergedAt.Valid)
+	assert.False(t, params.ClosedAt.Valid)
+}
+
+func TestPullRequestDetailsArgsKind(t *testing.T) {
+	assert.Equal(t, "github_pull_request_details", PullRequestDetailsArgs{}.Kind())
+	opts := PullRequestDetailsArgs{}.InsertOpts()
+	assert.True(t, opts.UniqueOpts.ByArgs)
+	assert.Equal(t, enrichDelay, opts.UniqueOpts.ByPeriod)
+	assert.False(t, opts.ScheduledAt.IsZero())
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// secondaryLimitWait is how long to back off from a secondary rate limit
+// that doesn't say when to retry. GitHub asks for at least a minute.
+const secondaryLimitWait = time.Minute
+
+// RateLimitError is returned when GitHub rejects a request for exceeding
+// the primary or a secondary rate limit.
+type RateLimitError struct {
+	ResetAt   time.Time
+	Secondary bool
+}
+
+func (e *RateLimitError) Error() string {
+	kind := "rate limit"
+	if e.Secondary {
+		kind = "secondary rate limit"
+	}
+	return fmt.Sprintf("github %s exceeded, retry at %s", kind, e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// Wait returns how long to wait from now before retrying, at least one second.
+func (e *RateLimitError) Wait(now time.Time) time.Duration {
+	return max(e.ResetAt.Sub(now), time.Second)
+}
+
+// APIError is returned for any other non-success response.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("github api returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("github api returned %d: %s", e.StatusCode, e.Message)
+}
+
+// responseError converts a non-success response into a *RateLimitError or
+// *APIError.
+func responseError(resp *http.Response, now time.Time) error {
+	var body struct {
+		Message string `json:"message"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(raw, &body)
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return &RateLimitError{ResetAt: now.Add(time.Duration(secs) * time.Second), Secondary: true}
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			resetAt := now.Add(secondaryLimitWait)
+			if unix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				resetAt = time.Unix(unix, 0)
+			}
+			return &RateLimitError{ResetAt: resetAt}
+		}
+		if strings.Contains(strings.ToLower(body.Message), "secondary rate limit") {
+			return &RateLimitError{ResetAt: now.Add(secondaryLimitWait), Secondary: true}
+		}
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: body.Message}
+}
+
+// pollInterval reads X-Poll-Interval, the minimum time GitHub wants
+// between polls of the events endpoint.
+func pollInterval(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("X-Poll-Interval"))
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
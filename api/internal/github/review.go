@@ -0,0 +1,216 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
+)
+
+// EnrichReviewsArgs are the arguments for looking up who wrote the pull
+// requests a user reviewed and when their review was requested.
+type EnrichReviewsArgs struct {
+	UserID int64 `json:"user_id"`
+	// Provider names the GitHub deployment; empty means github.com.
+	Provider string `json:"provider,omitempty"`
+}
+
+func (EnrichReviewsArgs) Kind() string { return "github_review_enrich" }
+
+// InsertOpts dedupes against jobs that haven't finished yet, so every sync
+// and review webhook can ask for enrichment without piling up jobs.
+func (EnrichReviewsArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		ScheduledAt: time.Now().Add(enrichDelay),
+		UniqueOpts:  riversetup.UniqueUnfinished(enrichDelay),
+	}
+}
+
+// EnrichReviewsWorker fills in the pull request author, the reviewer and
+// the review request time of review activities, then re-aggregates the
+// affected days, since reviews of the user's own pull requests don't count
+// as reviews given.
+type EnrichReviewsWorker struct {
+	riverlib.WorkerDefaults[EnrichReviewsArgs]
+	q       *dbgen.Queries
+	hosts   Hosts
+	keyring *tokencrypt.Keyring
+}
+
+func NewEnrichReviewsWorker(q *dbgen.Queries, hosts Hosts, keyring *tokencrypt.Keyring) *EnrichReviewsWorker {
+	return &EnrichReviewsWorker{q: q, hosts: hosts, keyring: keyring}
+}
+
+func (w *EnrichReviewsWorker) Timeout(job *riverlib.Job[EnrichReviewsArgs]) time.Duration {
+	return enrichTimeout
+}
+
+func (w *EnrichReviewsWorker) Work(ctx context.Context, job *riverlib.Job[EnrichReviewsArgs]) error {
+	userID := job.Args.UserID
+	source, gh, err := w.hosts.lookup(job.Args.Provider)
+	if err != nil {
+		return err
+	}
+
+	ds, err := w.q.GetDataSourceByUserAndProvider(ctx, dbgen.GetDataSourceByUserAndProviderParams{
+		UserID:   userID,
+		Provider: source,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("%s data source for user %d not found", source, userID))
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := w.keyring.Decrypt(ds.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	// Days already enriched are re-aggregated even if a later review fails,
+	// since the retry won't list those reviews again.
+	touched, err := w.enrichAll(ctx, gh, userID, source, string(token))
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	if aggErr := summary.Reaggregate(ctx, w.q, client, userID, touched); aggErr != nil {
+		return errors.Join(err, aggErr)
+	}
+
+	var rateLimited *RateLimitError
+	if errors.As(err, &rateLimited) {
+		return riverlib.JobSnooze(rateLimited.Wait(time.Now()))
+	}
+	return err
+}
+
+// reviewedPullRequest is what enrichment needs to know about a pull request.
+// A nil detail means the API couldn't return it.
+type reviewedPullRequest struct {
+	detail *PullRequestDetail
+	events []IssueEvent
+}
+
+// enrichAll enriches the user's reviews batch by batch and returns the
+// times of the reviews it updated. Pull requests are looked up once per
+// run however many reviews they got.
+func (w *EnrichReviewsWorker) enrichAll(ctx context.Context, gh *Client, userID int64, source, token string) ([]time.Time, error) {
+	viewer, err := gh.FetchAuthenticatedUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make(map[string]reviewedPullRequest)
+	var touched []time.Time
+	for {
+		rows, err := w.q.ListUnenrichedReviews(ctx, dbgen.ListUnenrichedReviewsParams{
+			UserID: userID,
+			Source: source,
+			Limit:  enrichBatch,
+		})
+		if err != nil {
+			return touched, err
+		}
+
+		for _, row := range rows {
+			var review storedActivity
+			if err := json.Unmarshal(row.Payload, &review); err != nil {
+				return touched, fmt.Errorf("decode activity %d: %w", row.ID, err)
+			}
+
+			var pr reviewedPullRequest
+			if number := prNumber(review.Payload); number != 0 {
+				key := fmt.Sprintf("%s#%d", review.Repo, number)
+				cached, ok := prs[key]
+				if !ok {
+					cached, err = w.fetchPullRequest(ctx, gh, token, review.Repo, number)
+					if err != nil {
+						return touched, fmt.Errorf("enrich activity %d: %w", row.ID, err)
+					}
+					prs[key] = cached
+				}
+				pr = cached
+			}
+
+			enrichReview(&review, pr, viewer.Login, row.OccurredAt.Time)
+			payload, err := json.Marshal(review)
+			if err != nil {
+				return touched, err
+			}
+			if err := w.q.UpdateActivityPayload(ctx, dbgen.UpdateActivityPayloadParams{
+				ID:      row.ID,
+				Payload: payload,
+			}); err != nil {
+				return touched, err
+			}
+			touched = append(touched, row.OccurredAt.Time)
+		}
+
+		if len(rows) < enrichBatch {
+			return touched, nil
+		}
+	}
+}
+
+func (w *EnrichReviewsWorker) fetchPullRequest(ctx context.Context, gh *Client, token, repo string, number int) (reviewedPullRequest, error) {
+	detail, err := gh.FetchPullRequest(ctx, token, repo, number)
+	if err != nil {
+		if resourceUnavailable(err) {
+			return reviewedPullRequest{}, nil
+		}
+		return reviewedPullRequest{}, err
+	}
+	events, err := gh.FetchIssueEvents(ctx, token, repo, number)
+	if err != nil && !resourceUnavailable(err) {
+		return reviewedPullRequest{}, err
+	}
+	return reviewedPullRequest{detail: detail, events: events}, nil
+}
+
+// enrichReview records the pull request author, the reviewer and when the
+// review was requested on a stored review, and marks it as fetched.
+// submitted stands in for the review's submission time if the payload
+// lacks one.
+func enrichReview(review *storedActivity, pr reviewedPullRequest, login string, submitted time.Time) {
+	p := &review.Payload
+	p.ReviewFetched = true
+	if p.Review != nil {
+		if p.Review.User == nil {
+			p.Review.User = &Actor{Login: login}
+		}
+		if !p.Review.SubmittedAt.IsZero() {
+			submitted = p.Review.SubmittedAt
+		}
+	}
+	if pr.detail == nil {
+		return
+	}
+
+	if p.PullRequest == nil {
+		p.PullRequest = &PullRequest{Number: pr.detail.Number, Title: pr.detail.Title, State: pr.detail.State}
+	}
+	if p.PullRequest.User == nil {
+		author := pr.detail.User
+		p.PullRequest.User = &author
+	}
+
+	requested := pr.detail.CreatedAt
+	for _, e := range pr.events {
+		if e.Event != "review_requested" || e.CreatedAt.After(submitted) {
+			continue
+		}
+		forUser := e.RequestedReviewer != nil && e.RequestedReviewer.Login == login
+		if (forUser || e.RequestedTeam != nil) && e.CreatedAt.After(requested) {
+			requested = e.CreatedAt
+		}
+	}
+	p.RequestedAt = &requested
+}
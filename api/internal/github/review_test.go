@@ -0,0 +1,76 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichReview(t *testing.T) {
+	opened := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	submitted := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	pr := reviewedPullRequest{
+		detail: &PullRequestDetail{Number: 7, Title: "Add feature", State: "open", User: Actor{Login: "alice"}, CreatedAt: opened},
+		events: []IssueEvent{
+			{Event: "review_requested", CreatedAt: opened.Add(time.Hour), RequestedReviewer: &Actor{Login: "me"}},
+			{Event: "review_requested", CreatedAt: opened.Add(2 * time.Hour), RequestedReviewer: &Actor{Login: "bob"}},
+			{Event: "labeled", CreatedAt: opened.Add(3 * time.Hour)},
+			// Re-requested after this review
+			{Event: "review_requested", CreatedAt: submitted.Add(time.Hour), RequestedReviewer: &Actor{Login: "me"}},
+		},
+	}
+	review := storedActivity{Repo: "user/repo", Payload: Payload{
+		Action: "created",
+		Number: 7,
+		Review: &Review{ID: 1, State: "approved", SubmittedAt: submitted},
+	}}
+
+	enrichReview(&review, pr, "me", time.Time{})
+
+	assert.True(t, review.Payload.ReviewFetched)
+	require.NotNil(t, review.Payload.RequestedAt)
+	assert.Equal(t, opened.Add(time.Hour), *review.Payload.RequestedAt)
+	require.NotNil(t, review.Payload.PullRequest)
+	assert.Equal(t, "alice", review.Payload.PullRequest.User.Login)
+	assert.Equal(t, "me", review.Payload.Review.User.Login)
+}
+
+func TestEnrichReview_NeverRequested(t *testing.T) {
+	opened := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	pr := reviewedPullRequest{detail: &PullRequestDetail{Number: 7, User: Actor{Login: "alice"}, CreatedAt: opened}}
+	review := storedActivity{Repo: "user/repo", Payload: Payload{
+		Number:      7,
+		PullRequest: &PullRequest{Number: 7, Title: "Add feature", User: &Actor{Login: "alice"}},
+		Review:      &Review{ID: 1, State: "commented", User: &Actor{Login: "me"}},
+	}}
+
+	enrichReview(&review, pr, "me", opened.Add(time.Hour))
+
+	require.NotNil(t, review.Payload.RequestedAt)
+	assert.Equal(t, opened, *review.Payload.RequestedAt)
+	assert.Equal(t, "Add feature", review.Payload.PullRequest.Title)
+}
+
+func TestEnrichReview_PullRequestUnavailable(t *testing.T) {
+	review := storedActivity{Repo: "user/repo", Payload: Payload{
+		Number: 7,
+		Review: &Review{ID: 1, State: "approved"},
+	}}
+
+	enrichReview(&review, reviewedPullRequest{}, "me", time.Now())
+
+	assert.True(t, review.Payload.ReviewFetched)
+	assert.Nil(t, review.Payload.RequestedAt)
+	assert.Nil(t, review.Payload.PullRequest)
+	assert.Equal(t, "me", review.Payload.Review.User.Login)
+}
+
+func TestEnrichReviewsArgsKind(t *testing.T) {
+	assert.Equal(t, "github_review_enrich", EnrichReviewsArgs{}.Kind())
+	opts := EnrichReviewsArgs{}.InsertOpts()
+	assert.True(t, opts.UniqueOpts.ByArgs)
+	assert.Equal(t, enrichDelay, opts.UniqueOpts.ByPeriod)
+	assert.False(t, opts.ScheduledAt.IsZero())
+}
@@ -0,0 +1,206 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// webhookEventTypes maps X-GitHub-Event header values to Events API types.
+var webhookEventTypes = map[string]string{
+	"push":                        "PushEvent",
+	"pull_request":                "PullRequestEvent",
+	"pull_request_review":         "PullRequestReviewEvent",
+	"pull_request_review_comment": "PullRequestReviewCommentEvent",
+	"create":                      "CreateEvent",
+	"delete":                      "DeleteEvent",
+	"issues":                      "IssuesEvent",
+	"issue_comment":               "IssueCommentEvent",
+	"release":                     "ReleaseEvent",
+	"fork":                        "ForkEvent",
+	"watch":                       "WatchEvent",
+}
+
+// VerifySignature checks an X-Hub-Signature-256 header ("sha256=<hex>")
+// against the HMAC-SHA256 of body keyed with secret.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	hexSig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok || secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// webhookPayload is the subset of webhook delivery bodies we read.
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads
+type webhookPayload struct {
+	Action     string `json:"action"`
+	Number     int    `json:"number"`
+	Ref        string `json:"ref"`
+	RefType    string `json:"ref_type"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Commits []struct {
+		ID       string `json:"id"`
+		Message  string `json:"message"`
+		Distinct bool   `json:"distinct"`
+	} `json:"commits"`
+	HeadCommit *struct {
+		Time time.Time `json:"timestamp"`
+	} `json:"head_commit"`
+	PullRequest *struct {
+		Number    int       `json:"number"`
+		Title     string    `json:"title"`
+		State     string    `json:"state"`
+		Merged    bool      `json:"merged"`
+		User      *Actor    `json:"user"`
+		UpdatedAt time.Time `json:"updated_at"`
+	} `json:"pull_request"`
+	Review *Review `json:"review"`
+	Issue  *struct {
+		Number    int       `json:"number"`
+		Title     string    `json:"title"`
+		State     string    `json:"state"`
+		UpdatedAt time.Time `json:"updated_at"`
+	} `json:"issue"`
+	Comment *struct {
+		ID        int64     `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"comment"`
+	Release *struct {
+		Release
+		PublishedAt time.Time `json:"published_at"`
+	} `json:"release"`
+	Forkee *Forkee `json:"forkee"`
+}
+
+// ParseWebhook converts a webhook delivery into the equivalent Events API
+// event. It returns nil for event types we don't process (including "ping"),
+// for pushes that delete a branch, which carry no new work, and for edits
+// and deletions of comments and releases, which the Events API doesn't
+// report either.
+func ParseWebhook(eventName, deliveryID string, body []byte) (*Event, error) {
+	evtType, ok := webhookEventTypes[eventName]
+	if !ok {
+		return nil, nil
+	}
+
+	var wp webhookPayload
+	if err := json.Unmarshal(body, &wp); err != nil {
+		return nil, fmt.Errorf("decode webhook payload: %w", err)
+	}
+
+	evt := &Event{
+		ID:        deliveryID,
+		Type:      evtType,
+		Repo:      Repo{Name: wp.Repository.FullName},
+		CreatedAt: time.Now().UTC(),
+	}
+
+	switch evtType {
+	case "PushEvent":
+		if wp.Deleted {
+			return nil, nil
+		}
+		evt.Payload = Payload{
+			Ref:  wp.Ref,
+			Head: wp.After,
+			Size: len(wp.Commits),
+		}
+		for _, c := range wp.Commits {
+			evt.Payload.Commits = append(evt.Payload.Commits, Commit{SHA: c.ID, Message: c.Message})
+			if c.Distinct {
+				evt.Payload.DistinctSize++
+			}
+		}
+		if wp.HeadCommit != nil && !wp.HeadCommit.Time.IsZero() {
+			evt.CreatedAt = wp.HeadCommit.Time
+		}
+	case "PullRequestEvent", "PullRequestReviewEvent":
+		evt.Payload = Payload{Action: wp.Action, Number: wp.Number}
+		if wp.PullRequest != nil {
+			evt.Payload.PullRequest = &PullRequest{
+				Number: wp.PullRequest.Number,
+				Title:  wp.PullRequest.Title,
+				State:  wp.PullRequest.State,
+				Merged: wp.PullRequest.Merged,
+				User:   wp.PullRequest.User,
+			}
+			if evt.Payload.Number == 0 {
+				evt.Payload.Number = wp.PullRequest.Number
+			}
+			if evtType == "PullRequestEvent" && !wp.PullRequest.UpdatedAt.IsZero() {
+				evt.CreatedAt = wp.PullRequest.UpdatedAt
+			}
+		}
+		if wp.Review != nil {
+			evt.Payload.Review = wp.Review
+			if !wp.Review.SubmittedAt.IsZero() {
+				evt.CreatedAt = wp.Review.SubmittedAt
+			}
+		}
+	case "CreateEvent", "DeleteEvent":
+		evt.Payload = Payload{Ref: wp.Ref, RefType: wp.RefType}
+	case "IssuesEvent":
+		evt.Payload = Payload{Action: wp.Action}
+		if wp.Issue != nil {
+			evt.Payload.Issue = &Issue{Number: wp.Issue.Number, Title: wp.Issue.Title, State: wp.Issue.State}
+			if !wp.Issue.UpdatedAt.IsZero() {
+				evt.CreatedAt = wp.Issue.UpdatedAt
+			}
+		}
+	case "IssueCommentEvent", "PullRequestReviewCommentEvent":
+		if wp.Action != "created" {
+			return nil, nil
+		}
+		evt.Payload = Payload{Action: wp.Action}
+		if wp.Issue != nil {
+			evt.Payload.Issue = &Issue{Number: wp.Issue.Number, Title: wp.Issue.Title, State: wp.Issue.State}
+		}
+		if wp.PullRequest != nil {
+			evt.Payload.Number = wp.PullRequest.Number
+			evt.Payload.PullRequest = &PullRequest{
+				Number: wp.PullRequest.Number,
+				Title:  wp.PullRequest.Title,
+				State:  wp.PullRequest.State,
+			}
+		}
+		if wp.Comment != nil {
+			evt.Payload.Comment = &Comment{ID: wp.Comment.ID}
+			if !wp.Comment.CreatedAt.IsZero() {
+				evt.CreatedAt = wp.Comment.CreatedAt
+			}
+		}
+	case "ReleaseEvent":
+		if wp.Action != "published" {
+			return nil, nil
+		}
+		evt.Payload = Payload{Action: wp.Action}
+		if wp.Release != nil {
+			rel := wp.Release.Release
+			evt.Payload.Release = &rel
+			if !wp.Release.PublishedAt.IsZero() {
+				evt.CreatedAt = wp.Release.PublishedAt
+			}
+		}
+	case "ForkEvent":
+		evt.Payload = Payload{Forkee: wp.Forkee}
+	case "WatchEvent":
+		evt.Payload = Payload{Action: wp.Action}
+	}
+
+	return evt, nil
+}
@@ -0,0 +1,215 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+
+	assert.True(t, VerifySignature("s3cret", body, sign("s3cret", body)))
+	assert.False(t, VerifySignature("s3cret", body, sign("other", body)))
+	assert.False(t, VerifySignature("s3cret", []byte(`{}`), sign("s3cret", body)))
+	assert.False(t, VerifySignature("s3cret", body, "sha1=abc"))
+	assert.False(t, VerifySignature("s3cret", body, "sha256=not-hex"))
+	assert.False(t, VerifySignature("", body, sign("", body)))
+}
+
+func TestParseWebhook_Push(t *testing.T) {
+	body := []byte(`{
+		"ref": "refs/heads/main",
+		"after": "def456",
+		"repository": {"full_name": "user/repo"},
+		"commits": [
+			{"id": "abc123", "message": "first", "distinct": true},
+			{"id": "def456", "message": "second", "distinct": false}
+		],
+		"head_commit": {"timestamp": "2026-03-01T10:00:00Z"}
+	}`)
+
+	evt, err := ParseWebhook("push", "delivery-1", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "PushEvent", evt.Type)
+	assert.Equal(t, "user/repo", evt.Repo.Name)
+	assert.Equal(t, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), evt.CreatedAt.UTC())
+	assert.Equal(t, "def456", evt.Payload.Head)
+	assert.Equal(t, 2, evt.Payload.Size)
+	assert.Equal(t, 1, evt.Payload.DistinctSize)
+	require.Len(t, evt.Payload.Commits, 2)
+	assert.Equal(t, "abc123", evt.Payload.Commits[0].SHA)
+	assert.Equal(t, "push:user/repo:def456", externalID(*evt))
+}
+
+func TestParseWebhook_PushDeletedBranch(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/old","deleted":true,"repository":{"full_name":"user/repo"}}`)
+
+	evt, err := ParseWebhook("push", "delivery-2", body)
+
+	require.NoError(t, err)
+	assert.Nil(t, evt)
+}
+
+func TestParseWebhook_PullRequest(t *testing.T) {
+	body := []byte(`{
+		"action": "opened",
+		"number": 7,
+		"repository": {"full_name": "user/repo"},
+		"pull_request": {"number": 7, "title": "Add feature", "state": "open", "updated_at": "2026-03-01T11:00:00Z"}
+	}`)
+
+	evt, err := ParseWebhook("pull_request", "delivery-3", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "PullRequestEvent", evt.Type)
+	assert.Equal(t, "opened", evt.Payload.Action)
+	require.NotNil(t, evt.Payload.PullRequest)
+	assert.Equal(t, "Add feature", evt.Payload.PullRequest.Title)
+	assert.Equal(t, "pull_request:user/repo:7:opened", externalID(*evt))
+}
+
+func TestParseWebhook_PullRequestMerged(t *testing.T) {
+	body := []byte(`{
+		"action": "closed",
+		"number": 7,
+		"repository": {"full_name": "user/repo"},
+		"pull_request": {"number": 7, "title": "Add feature", "state": "closed", "merged": true, "user": {"login": "me"}, "updated_at": "2026-03-02T11:00:00Z"}
+	}`)
+
+	evt, err := ParseWebhook("pull_request", "delivery-5", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	require.NotNil(t, evt.Payload.PullRequest)
+	assert.True(t, evt.Payload.PullRequest.Merged)
+	assert.Equal(t, "me", evt.Payload.PullRequest.User.Login)
+	assert.Equal(t, "pull_request:user/repo:7:closed", externalID(*evt))
+}
+
+func TestParseWebhook_Review(t *testing.T) {
+	body := []byte(`{
+		"action": "submitted",
+		"repository": {"full_name": "user/repo"},
+		"review": {"id": 99, "state": "approved", "submitted_at": "2026-03-01T12:00:00Z"},
+		"pull_request": {"number": 7, "title": "Add feature", "state": "open"}
+	}`)
+
+	evt, err := ParseWebhook("pull_request_review", "delivery-4", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "PullRequestReviewEvent", evt.Type)
+	assert.Equal(t, 7, evt.Payload.Number)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), evt.CreatedAt.UTC())
+	assert.Equal(t, "review:user/repo:99", externalID(*evt))
+}
+
+func TestParseWebhook_Create(t *testing.T) {
+	body := []byte(`{"ref":"v1.0.0","ref_type":"tag","repository":{"full_name":"user/repo"}}`)
+
+	evt, err := ParseWebhook("create", "delivery-5", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "CreateEvent", evt.Type)
+	assert.Equal(t, "create:user/repo:tag:v1.0.0", externalID(*evt))
+}
+
+func TestParseWebhook_Issue(t *testing.T) {
+	body := []byte(`{
+		"action": "closed",
+		"repository": {"full_name": "user/repo"},
+		"issue": {"number": 3, "title": "Crash on start", "state": "closed", "updated_at": "2026-03-01T13:00:00Z"}
+	}`)
+
+	evt, err := ParseWebhook("issues", "delivery-8", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "IssuesEvent", evt.Type)
+	require.NotNil(t, evt.Payload.Issue)
+	assert.Equal(t, "Crash on start", evt.Payload.Issue.Title)
+	assert.Equal(t, time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC), evt.CreatedAt.UTC())
+	assert.Equal(t, "issue:user/repo:3:closed", externalID(*evt))
+}
+
+func TestParseWebhook_Comment(t *testing.T) {
+	body := []byte(`{
+		"action": "created",
+		"repository": {"full_name": "user/repo"},
+		"issue": {"number": 3, "title": "Crash on start", "state": "open"},
+		"comment": {"id": 41, "body": "Fixed in #7", "created_at": "2026-03-01T14:00:00Z"}
+	}`)
+
+	evt, err := ParseWebhook("issue_comment", "delivery-9", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "IssueCommentEvent", evt.Type)
+	assert.Equal(t, time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC), evt.CreatedAt.UTC())
+	assert.Equal(t, "issue_comment:user/repo:41", externalID(*evt))
+
+	edited, err := ParseWebhook("issue_comment", "delivery-10", []byte(`{"action":"edited","comment":{"id":41}}`))
+	require.NoError(t, err)
+	assert.Nil(t, edited)
+}
+
+func TestParseWebhook_Release(t *testing.T) {
+	body := []byte(`{
+		"action": "published",
+		"repository": {"full_name": "user/repo"},
+		"release": {"id": 5, "tag_name": "v1.0.0", "name": "First", "published_at": "2026-03-01T15:00:00Z"}
+	}`)
+
+	evt, err := ParseWebhook("release", "delivery-11", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "ReleaseEvent", evt.Type)
+	require.NotNil(t, evt.Payload.Release)
+	assert.Equal(t, "v1.0.0", evt.Payload.Release.TagName)
+	assert.Equal(t, time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC), evt.CreatedAt.UTC())
+	assert.Equal(t, "release:user/repo:5", externalID(*evt))
+
+	created, err := ParseWebhook("release", "delivery-12", []byte(`{"action":"created","release":{"id":5}}`))
+	require.NoError(t, err)
+	assert.Nil(t, created)
+}
+
+func TestParseWebhook_Fork(t *testing.T) {
+	body := []byte(`{"repository":{"full_name":"user/repo"},"forkee":{"full_name":"me/repo"}}`)
+
+	evt, err := ParseWebhook("fork", "delivery-13", body)
+
+	require.NoError(t, err)
+	require.NotNil(t, evt)
+	assert.Equal(t, "ForkEvent", evt.Type)
+	assert.Equal(t, "fork:user/repo:me/repo", externalID(*evt))
+}
+
+func TestParseWebhook_UnsupportedEvent(t *testing.T) {
+	evt, err := ParseWebhook("ping", "delivery-6", []byte(`{"zen":"hi"}`))
+
+	require.NoError(t, err)
+	assert.Nil(t, evt)
+}
+
+func TestParseWebhook_InvalidJSON(t *testing.T) {
+	_, err := ParseWebhook("push", "delivery-7", []byte(`{bad`))
+	assert.Error(t, err)
+}
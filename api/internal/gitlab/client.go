@@ -0,0 +1,143 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPages bounds how far back a single sync pages through events.
+const maxPages = 10
+
+// perPage is GitLab's maximum page size.
+const perPage = 100
+
+// rateLimitWait is how long to back off from a 429 that doesn't say when
+// to retry.
+const rateLimitWait = time.Minute
+
+// Client calls the GitLab REST API of one instance.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string // instance URL, e.g. "https://gitlab.com"
+}
+
+// NewClient creates a GitLab API client for the instance at baseURL.
+// If httpClient is nil, a default http.Client is used.
+func NewClient(httpClient *http.Client, baseURL string) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{httpClient: httpClient, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// RateLimitError is returned when GitLab rejects a request with 429.
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("gitlab rate limit exceeded, retry at %s", e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// Wait returns how long to wait from now before retrying, at least one second.
+func (e *RateLimitError) Wait(now time.Time) time.Duration {
+	return max(e.ResetAt.Sub(now), time.Second)
+}
+
+// APIError is returned for any other non-success response.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gitlab api returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("gitlab api returned %d: %s", e.StatusCode, e.Message)
+}
+
+// FetchUserEvents returns the authenticated user's events newer than
+// sinceID, newest first. With sinceID 0 it returns up to maxPages pages.
+func (c *Client) FetchUserEvents(ctx context.Context, token string, sinceID int64) ([]Event, error) {
+	var events []Event
+	for page := 1; page <= maxPages; page++ {
+		url := fmt.Sprintf("%s/api/v4/events?sort=desc&per_page=%d&page=%d", c.baseURL, perPage, page)
+		var batch []Event
+		if err := c.get(ctx, token, url, &batch); err != nil {
+			return nil, fmt.Errorf("fetch events page %d: %w", page, err)
+		}
+
+		for _, evt := range batch {
+			if evt.ID <= sinceID {
+				return events, nil
+			}
+			events = append(events, evt)
+		}
+		if len(batch) < perPage {
+			break // No more pages
+		}
+	}
+	return events, nil
+}
+
+// FetchProject returns the project with the given ID.
+func (c *Client) FetchProject(ctx context.Context, token string, id int64) (*Project, error) {
+	var project Project
+	if err := c.get(ctx, token, fmt.Sprintf("%s/api/v4/projects/%d", c.baseURL, id), &project); err != nil {
+		return nil, fmt.Errorf("fetch project %d: %w", id, err)
+	}
+	return &project, nil
+}
+
+func (c *Client) get(ctx context.Context, token, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, time.Now())
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// responseError converts a non-success response into a *RateLimitError or
+// *APIError.
+func responseError(resp *http.Response, now time.Time) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resetAt := now.Add(rateLimitWait)
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			resetAt = now.Add(time.Duration(secs) * time.Second)
+		} else if unix, err := strconv.ParseInt(resp.Header.Get("RateLimit-Reset"), 10, 64); err == nil {
+			resetAt = time.Unix(unix, 0)
+		}
+		return &RateLimitError{ResetAt: resetAt}
+	}
+
+	var body struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(raw, &body)
+	msg := body.Error
+	if body.Message != nil {
+		msg = fmt.Sprint(body.Message)
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: msg}
+}
@@ -0,0 +1,86 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchUserEvents_StopsAtSinceID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/events", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode([]Event{{ID: 12}, {ID: 11}, {ID: 10}, {ID: 9}})
+	}))
+	defer srv.Close()
+
+	client := NewClient(nil, srv.URL+"/")
+	events, err := client.FetchUserEvents(context.Background(), "test-token", 10)
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(12), events[0].ID)
+	assert.Equal(t, int64(11), events[1].ID)
+}
+
+func TestFetchUserEvents_Pagination(t *testing.T) {
+	var pages int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		n := perPage
+		if r.URL.Query().Get("page") == "2" {
+			n = 5
+		}
+		batch := make([]Event, n)
+		for i := range batch {
+			batch[i] = Event{ID: int64(1000 - pages*perPage - i)}
+		}
+		json.NewEncoder(w).Encode(batch)
+	}))
+	defer srv.Close()
+
+	client := NewClient(nil, srv.URL)
+	events, err := client.FetchUserEvents(context.Background(), "test-token", 0)
+
+	require.NoError(t, err)
+	assert.Len(t, events, perPage+5)
+	assert.Equal(t, 2, pages)
+}
+
+func TestFetchUserEvents_RateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient(nil, srv.URL)
+	_, err := client.FetchUserEvents(context.Background(), "test-token", 0)
+
+	var rl *RateLimitError
+	require.ErrorAs(t, err, &rl)
+	assert.Equal(t, 30*time.Second, rl.Wait(time.Now()).Round(time.Second))
+}
+
+func TestFetchProject_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"404 Project Not Found"}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(nil, srv.URL)
+	_, err := client.FetchProject(context.Background(), "test-token", 7)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "404 Project Not Found", apiErr.Message)
+}
@@ -0,0 +1,43 @@
+package gitlab
+
+import "time"
+
+// Event represents a GitLab user contribution event.
+// https://docs.gitlab.com/api/events/
+type Event struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"project_id"`
+	ActionName  string    `json:"action_name"`
+	TargetType  string    `json:"target_type"`
+	TargetID    int64     `json:"target_id"`
+	TargetIID   int       `json:"target_iid"`
+	TargetTitle string    `json:"target_title"`
+	CreatedAt   time.Time `json:"created_at"`
+	PushData    *PushData `json:"push_data,omitempty"`
+	Note        *Note     `json:"note,omitempty"`
+}
+
+// PushData describes a push. GitLab only reports the count and the head
+// commit, not the full list of commits.
+type PushData struct {
+	CommitCount int    `json:"commit_count"`
+	Action      string `json:"action"`
+	RefType     string `json:"ref_type"`
+	CommitFrom  string `json:"commit_from"`
+	CommitTo    string `json:"commit_to"`
+	Ref         string `json:"ref"`
+	CommitTitle string `json:"commit_title"`
+}
+
+// Note is the comment attached to a "commented on" event.
+type Note struct {
+	ID           int64  `json:"id"`
+	NoteableType string `json:"noteable_type"`
+	NoteableIID  int    `json:"noteable_iid"`
+}
+
+// Project is the subset of a project we need to name its activities.
+type Project struct {
+	ID                int64  `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+}
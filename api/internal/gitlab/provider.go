@@ -0,0 +1,194 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+// ProviderName is the data source and activity source key for GitLab.
+const ProviderName = "gitlab"
+
+// Provider syncs GitLab through the user events API.
+type Provider struct {
+	client *Client
+	oauth  provider.OAuthConfig
+}
+
+// NewProvider creates the GitLab provider for the given OAuth app. The
+// OAuth endpoints live on the same instance as the client's API.
+func NewProvider(client *Client, creds provider.Credentials) *Provider {
+	return &Provider{
+		client: client,
+		oauth: provider.OAuthConfig{
+			Credentials:  creds,
+			AuthorizeURL: client.baseURL + "/oauth/authorize",
+			TokenURL:     client.baseURL + "/oauth/token",
+			Scopes:       []string{"read_api"},
+			PKCE:         true,
+		},
+	}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+func (p *Provider) OAuthConfig() provider.OAuthConfig { return p.oauth }
+
+func (p *Provider) ExchangeCode(ctx context.Context, code, verifier string) (*provider.Token, error) {
+	return provider.ExchangeCode(ctx, p.oauth, code, verifier)
+}
+
+// RefreshToken renews an access token. GitLab access tokens expire after
+// two hours.
+func (p *Provider) RefreshToken(ctx context.Context, refreshToken string) (*provider.Token, error) {
+	return provider.RefreshToken(ctx, p.oauth, refreshToken)
+}
+
+// Fetch reads the events newer than cursor, the ID of the newest event
+// seen by the previous fetch. Rate limit responses are returned as
+// *RateLimitError.
+func (p *Provider) Fetch(ctx context.Context, token, cursor string) (*provider.FetchResult, error) {
+	sinceID, _ := strconv.ParseInt(cursor, 10, 64)
+	events, err := p.client.FetchUserEvents(ctx, token, sinceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &provider.FetchResult{Cursor: cursor}
+	if len(events) == 0 {
+		result.NotModified = true
+		return result, nil
+	}
+	// Events are newest first.
+	result.Cursor = strconv.FormatInt(events[0].ID, 10)
+
+	repos := make(map[int64]string)
+	for _, evt := range events {
+		repo, ok := repos[evt.ProjectID]
+		if !ok && evt.ProjectID != 0 {
+			repo, err = p.projectPath(ctx, token, evt.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			repos[evt.ProjectID] = repo
+		}
+		if act, ok := toActivity(evt, repo); ok {
+			result.Activities = append(result.Activities, act)
+		}
+	}
+	return result, nil
+}
+
+// projectPath returns the project's full path. Projects the user can no
+// longer see resolve to an empty path.
+func (p *Provider) projectPath(ctx context.Context, token string, id int64) (string, error) {
+	project, err := p.client.FetchProject(ctx, token, id)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusForbidden) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return project.PathWithNamespace, nil
+}
+
+func (p *Provider) SupportedTypes() []string {
+	return []string{"create", "pull_request", "push", "review"}
+}
+
+// toActivity maps a GitLab event to an activity using GitHub's payload
+// shape, so the daily aggregation treats both forges alike. Merge requests
+// become pull_request rows, approvals and merge request comments become
+// review rows. Other events are dropped.
+func toActivity(evt Event, repo string) (provider.Activity, bool) {
+	act := provider.Activity{OccurredAt: evt.CreatedAt, ExternalID: strconv.FormatInt(evt.ID, 10)}
+	var payload map[string]any
+
+	switch {
+	case evt.PushData != nil && (evt.ActionName == "pushed to" || evt.ActionName == "pushed new"):
+		pd := evt.PushData
+		if pd.CommitCount == 0 {
+			if evt.ActionName != "pushed new" {
+				return act, false
+			}
+			// A new branch or tag without new commits.
+			act.Type = "create"
+			payload = map[string]any{"ref": pd.Ref, "ref_type": pd.RefType}
+			break
+		}
+		act.Type = "push"
+		payload = map[string]any{
+			"ref":           pd.Ref,
+			"head":          pd.CommitTo,
+			"size":          pd.CommitCount,
+			"distinct_size": pd.CommitCount,
+		}
+		// GitLab only names the head commit. Listing it alone would make a
+		// multi-commit push count as one, so commits are only given when
+		// the head is the whole push.
+		if pd.CommitCount == 1 {
+			payload["commits"] = []map[string]string{{"sha": pd.CommitTo, "message": pd.CommitTitle}}
+		}
+		if pd.CommitTo != "" {
+			act.ExternalID = fmt.Sprintf("push:%s:%s", repo, pd.CommitTo)
+		}
+
+	case evt.TargetType == "MergeRequest" && (evt.ActionName == "opened" || evt.ActionName == "accepted" || evt.ActionName == "closed"):
+		action, state, merged := "opened", "open", false
+		if evt.ActionName != "opened" {
+			action, state, merged = "closed", "closed", evt.ActionName == "accepted"
+		}
+		act.Type = "pull_request"
+		payload = map[string]any{
+			"action": action,
+			"number": evt.TargetIID,
+			"pull_request": map[string]any{
+				"number": evt.TargetIID,
+				"title":  evt.TargetTitle,
+				"state":  state,
+				"merged": merged,
+			},
+		}
+		act.ExternalID = fmt.Sprintf("pull_request:%s:%d:%s", repo, evt.TargetIID, evt.ActionName)
+
+	case evt.TargetType == "MergeRequest" && evt.ActionName == "approved":
+		act.Type = "review"
+		payload = reviewPayload(evt.ID, evt.TargetIID, "approved", evt)
+
+	case evt.ActionName == "commented on" && evt.Note != nil && evt.Note.NoteableType == "MergeRequest":
+		act.Type = "review"
+		payload = reviewPayload(evt.Note.ID, evt.Note.NoteableIID, "commented", evt)
+
+	case evt.ActionName == "created" && evt.TargetType == "":
+		act.Type = "create"
+		payload = map[string]any{"ref_type": "repository"}
+
+	default:
+		return act, false
+	}
+
+	body := map[string]any{"payload": payload}
+	if repo != "" {
+		body["repo"] = repo
+	}
+	act.Payload, _ = json.Marshal(body)
+	return act, true
+}
+
+func reviewPayload(id int64, iid int, state string, evt Event) map[string]any {
+	return map[string]any{
+		"action": "created",
+		"number": iid,
+		"review": map[string]any{
+			"id":           id,
+			"state":        state,
+			"submitted_at": evt.CreatedAt,
+		},
+	}
+}
@@ -0,0 +1,160 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+func decodePayload(t *testing.T, act provider.Activity) map[string]any {
+	t.Helper()
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(act.Payload, &body))
+	return body
+}
+
+func TestToActivity_Push(t *testing.T) {
+	evt := Event{
+		ID:         1,
+		ActionName: "pushed to",
+		CreatedAt:  time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		PushData:   &PushData{CommitCount: 3, Ref: "main", RefType: "branch", CommitTo: "abc123", CommitTitle: "Fix"},
+	}
+
+	act, ok := toActivity(evt, "group/project")
+
+	require.True(t, ok)
+	assert.Equal(t, "push", act.Type)
+	assert.Equal(t, "push:group/project:abc123", act.ExternalID)
+	body := decodePayload(t, act)
+	assert.Equal(t, "group/project", body["repo"])
+	payload := body["payload"].(map[string]any)
+	assert.Equal(t, 3.0, payload["distinct_size"])
+	// Only the head is known, so a multi-commit push carries no commit list
+	assert.NotContains(t, payload, "commits")
+}
+
+func TestToActivity_SingleCommitPushListsCommit(t *testing.T) {
+	evt := Event{ID: 1, ActionName: "pushed to", PushData: &PushData{CommitCount: 1, CommitTo: "abc123", CommitTitle: "Merge branch 'x'"}}
+
+	act, ok := toActivity(evt, "group/project")
+
+	require.True(t, ok)
+	commits := decodePayload(t, act)["payload"].(map[string]any)["commits"].([]any)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "abc123", commits[0].(map[string]any)["sha"])
+	assert.Equal(t, "Merge branch 'x'", commits[0].(map[string]any)["message"])
+}
+
+func TestToActivity_NewBranchWithoutCommits(t *testing.T) {
+	evt := Event{ID: 2, ActionName: "pushed new", PushData: &PushData{Ref: "feature", RefType: "branch"}}
+
+	act, ok := toActivity(evt, "group/project")
+
+	require.True(t, ok)
+	assert.Equal(t, "create", act.Type)
+}
+
+func TestToActivity_MergeRequest(t *testing.T) {
+	tests := []struct {
+		action string
+		want   string
+		merged bool
+	}{
+		{"opened", "opened", false},
+		{"accepted", "closed", true},
+		{"closed", "closed", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			evt := Event{ID: 3, ActionName: tt.action, TargetType: "MergeRequest", TargetIID: 7, TargetTitle: "Add x"}
+
+			act, ok := toActivity(evt, "group/project")
+
+			require.True(t, ok)
+			assert.Equal(t, "pull_request", act.Type)
+			assert.Equal(t, "pull_request:group/project:7:"+tt.action, act.ExternalID)
+			payload := decodePayload(t, act)["payload"].(map[string]any)
+			assert.Equal(t, tt.want, payload["action"])
+			assert.Equal(t, tt.merged, payload["pull_request"].(map[string]any)["merged"])
+		})
+	}
+}
+
+func TestToActivity_Reviews(t *testing.T) {
+	approved := Event{ID: 4, ActionName: "approved", TargetType: "MergeRequest", TargetIID: 7}
+	act, ok := toActivity(approved, "group/project")
+	require.True(t, ok)
+	assert.Equal(t, "review", act.Type)
+	review := decodePayload(t, act)["payload"].(map[string]any)["review"].(map[string]any)
+	assert.Equal(t, "approved", review["state"])
+
+	comment := Event{ID: 5, ActionName: "commented on", TargetType: "DiffNote", Note: &Note{ID: 50, NoteableType: "MergeRequest", NoteableIID: 7}}
+	act, ok = toActivity(comment, "group/project")
+	require.True(t, ok)
+	assert.Equal(t, "review", act.Type)
+
+	issueComment := Event{ID: 6, ActionName: "commented on", TargetType: "Note", Note: &Note{ID: 60, NoteableType: "Issue"}}
+	_, ok = toActivity(issueComment, "group/project")
+	assert.False(t, ok)
+}
+
+func TestProvider_Fetch(t *testing.T) {
+	var projectCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/events":
+			json.NewEncoder(w).Encode([]Event{
+				{ID: 21, ProjectID: 5, ActionName: "opened", TargetType: "MergeRequest", TargetIID: 2},
+				{ID: 20, ProjectID: 5, ActionName: "pushed to", PushData: &PushData{CommitCount: 2, CommitTo: "def"}},
+				{ID: 19, ProjectID: 5, ActionName: "joined"},
+			})
+		case "/api/v4/projects/5":
+			projectCalls++
+			json.NewEncoder(w).Encode(Project{ID: 5, PathWithNamespace: "group/project"})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewProvider(NewClient(nil, srv.URL), provider.Credentials{})
+	result, err := p.Fetch(context.Background(), "test-token", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "21", result.Cursor)
+	assert.Len(t, result.Activities, 2)
+	assert.Equal(t, 1, projectCalls)
+}
+
+func TestProvider_FetchNothingNew(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]Event{{ID: 21}})
+	}))
+	defer srv.Close()
+
+	p := NewProvider(NewClient(nil, srv.URL), provider.Credentials{})
+	result, err := p.Fetch(context.Background(), "test-token", "21")
+
+	require.NoError(t, err)
+	assert.True(t, result.NotModified)
+	assert.Equal(t, "21", result.Cursor)
+}
+
+func TestProvider_SelfHostedOAuth(t *testing.T) {
+	p := NewProvider(NewClient(nil, "https://gitlab.example.com/"), provider.Credentials{ClientID: "id"})
+
+	cfg := p.OAuthConfig()
+	assert.Equal(t, "https://gitlab.example.com/oauth/authorize", cfg.AuthorizeURL)
+	assert.Equal(t, "https://gitlab.example.com/oauth/token", cfg.TokenURL)
+	assert.True(t, cfg.PKCE)
+	assert.Equal(t, "gitlab", p.Name())
+}
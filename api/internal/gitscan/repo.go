@@ -0,0 +1,208 @@
+// Package gitscan imports commits from git repositories on the server's
+// disk, for repositories that only live on an internal file server.
+package gitscan
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/ethanwang/devpulse/api/internal/gitagent"
+)
+
+// Repo is a bare or working repository read with go-git, without running
+// git or touching the network.
+type Repo struct {
+	path string
+	repo *git.Repository
+}
+
+// OpenRepo opens the repository at path.
+func OpenRepo(path string) (*Repo, error) {
+	r, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	return &Repo{path: path, repo: r}, nil
+}
+
+// Name is the repository's "owner/name" from its origin remote, or its
+// directory name.
+func (r *Repo) Name() string {
+	remote := ""
+	if cfg, err := r.repo.Config(); err == nil {
+		if origin, ok := cfg.Remotes["origin"]; ok && len(origin.URLs) > 0 {
+			remote = origin.URLs[0]
+		}
+	}
+	return gitagent.RepoName(remote, r.path)
+}
+
+// Tips returns the commit hashes of the repository's branches, local ones
+// first, then remote-tracking ones. Tags are left out; the commits they
+// point to are normally on a branch.
+func (r *Repo) Tips() ([]Tip, error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+
+	var heads, remotes []Tip
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		switch name := ref.Name(); {
+		case name.IsBranch():
+			heads = append(heads, Tip{Hash: ref.Hash().String(), Branch: name.Short()})
+		case name.IsRemote():
+			remotes = append(remotes, Tip{Hash: ref.Hash().String(), Branch: name.Short()})
+		}
+		return nil
+	})
+	return append(heads, remotes...), err
+}
+
+// Tip is the commit a branch points to.
+type Tip struct {
+	Hash   string
+	Branch string
+}
+
+// Commits returns the commits reachable from tips but not from seen, the
+// tips of the previous scan, leaving out commits older than floor. Like
+// `git rev-list tips --not seen`, the walk goes newest first and stops once
+// only commits reachable from seen remain. Clock skew between commits can
+// make it return a few already-seen commits, which callers dedupe.
+func (r *Repo) Commits(ctx context.Context, tips []Tip, seen []string, floor time.Time) ([]gitagent.Commit, error) {
+	w := &walker{repo: r.repo, marks: make(map[plumbing.Hash]bool)}
+	for _, h := range seen {
+		w.push(plumbing.NewHash(h), "", true)
+	}
+	for _, t := range tips {
+		w.push(plumbing.NewHash(t.Hash), t.Branch, false)
+	}
+
+	name := r.Name()
+	var commits []gitagent.Commit
+	for w.pending() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		e := heap.Pop(&w.queue).(walkEntry)
+		uninteresting := w.marks[e.commit.Hash]
+		if !uninteresting && e.commit.Committer.When.Before(floor) {
+			continue
+		}
+		for _, p := range e.commit.ParentHashes {
+			w.push(p, e.branch, uninteresting)
+		}
+		if uninteresting {
+			continue
+		}
+
+		c, err := toCommit(ctx, e.commit, name, e.branch)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+func toCommit(ctx context.Context, c *object.Commit, repo, branch string) (gitagent.Commit, error) {
+	commit := gitagent.Commit{
+		SHA:         c.Hash.String(),
+		Repo:        repo,
+		Branch:      branch,
+		Message:     firstLine(c.Message),
+		AuthorEmail: c.Author.Email,
+		CommittedAt: c.Committer.When,
+	}
+	// Merge commits don't change lines of their own, matching what
+	// `git diff-tree` reports for them in the hook agent.
+	if c.NumParents() > 1 {
+		return commit, nil
+	}
+	stats, err := c.StatsContext(ctx)
+	if err != nil {
+		return commit, fmt.Errorf("diff %s: %w", c.Hash, err)
+	}
+	for _, s := range stats {
+		commit.Additions += s.Addition
+		commit.Deletions += s.Deletion
+	}
+	return commit, nil
+}
+
+func firstLine(msg string) string {
+	for i, r := range msg {
+		if r == '\n' {
+			return msg[:i]
+		}
+	}
+	return msg
+}
+
+// walker walks commits newest first. marks records each queued commit and
+// whether it is reachable from the previous scan's tips.
+type walker struct {
+	repo  *git.Repository
+	queue commitQueue
+	marks map[plumbing.Hash]bool
+}
+
+// push queues a commit. A commit first reached from a new tip is queued
+// again when it turns out to be reachable from an old one, so that mark
+// spreads to its ancestors.
+func (w *walker) push(h plumbing.Hash, branch string, uninteresting bool) {
+	if prev, ok := w.marks[h]; ok && (prev || !uninteresting) {
+		return
+	}
+	c, err := w.repo.CommitObject(h)
+	if err != nil {
+		// Old tips can be garbage collected after a force push, and
+		// shallow repositories lack their oldest parents.
+		return
+	}
+	w.marks[h] = uninteresting
+	heap.Push(&w.queue, walkEntry{commit: c, branch: branch})
+}
+
+// pending reports whether any queued commit is still new.
+func (w *walker) pending() bool {
+	for _, e := range w.queue {
+		if !w.marks[e.commit.Hash] {
+			return true
+		}
+	}
+	return false
+}
+
+type walkEntry struct {
+	commit *object.Commit
+	branch string
+}
+
+// commitQueue is a max-heap of commits by committer time.
+type commitQueue []walkEntry
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].commit.Committer.When.After(q[j].commit.Committer.When)
+}
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)   { *q = append(*q, x.(walkEntry)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,178 @@
+package gitscan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/gitagent"
+)
+
+var base = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	t    *testing.T
+	dir  string
+	repo *git.Repository
+	wt   *git.Worktree
+}
+
+func newFixture(t *testing.T) *fixture {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	return &fixture{t: t, dir: dir, repo: repo, wt: wt}
+}
+
+// commit writes content to file and commits it as email at base+offset.
+func (f *fixture) commit(file, content, email string, offset time.Duration, parents ...plumbing.Hash) plumbing.Hash {
+	f.t.Helper()
+	require.NoError(f.t, os.WriteFile(filepath.Join(f.dir, file), []byte(content), 0o644))
+	_, err := f.wt.Add(file)
+	require.NoError(f.t, err)
+
+	sig := &object.Signature{Name: "Dev", Email: email, When: base.Add(offset)}
+	h, err := f.wt.Commit("change "+file+"\n\nbody", &git.CommitOptions{Author: sig, Committer: sig, Parents: parents})
+	require.NoError(f.t, err)
+	return h
+}
+
+func (f *fixture) checkout(branch string, create bool) {
+	f.t.Helper()
+	require.NoError(f.t, f.wt.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: create,
+	}))
+}
+
+func (f *fixture) open() *Repo {
+	f.t.Helper()
+	r, err := OpenRepo(f.dir)
+	require.NoError(f.t, err)
+	return r
+}
+
+func shas(commits []gitagent.Commit) map[string]string {
+	out := make(map[string]string, len(commits))
+	for _, c := range commits {
+		out[c.SHA] = c.Branch
+	}
+	return out
+}
+
+func TestCommits(t *testing.T) {
+	f := newFixture(t)
+	a := f.commit("main.go", "package main\n", "dev@example.com", 0)
+	b := f.commit("main.go", "package main\n\nfunc main() {}\n", "dev@example.com", time.Hour)
+	f.checkout("feature", true)
+	c := f.commit("util.go", "package main\n", "other@example.com", 2*time.Hour)
+
+	r := f.open()
+	tips, err := r.Tips()
+	require.NoError(t, err)
+	require.Len(t, tips, 2)
+
+	commits, err := r.Commits(context.Background(), tips, nil, base.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		a.String(): "master",
+		b.String(): "master",
+		c.String(): "feature",
+	}, shas(commits))
+
+	// Newest first, with the first line of the message and line counts.
+	assert.Equal(t, c.String(), commits[0].SHA)
+	assert.Equal(t, "change util.go", commits[0].Message)
+	assert.Equal(t, "other@example.com", commits[0].AuthorEmail)
+	assert.Equal(t, 1, commits[0].Additions)
+	assert.Equal(t, b.String(), commits[1].SHA)
+	assert.Equal(t, 2, commits[1].Additions)
+	assert.Equal(t, 0, commits[1].Deletions)
+}
+
+func TestCommits_OnlyNewSincePreviousTips(t *testing.T) {
+	f := newFixture(t)
+	f.commit("a.txt", "1\n", "dev@example.com", 0)
+	f.commit("a.txt", "2\n", "dev@example.com", time.Hour)
+
+	r := f.open()
+	oldTips, err := r.Tips()
+	require.NoError(t, err)
+	seen := []string{oldTips[0].Hash}
+
+	// A commit made days ago but only now landing on the server is still
+	// new, even though it's older than the previous tips.
+	late := f.commit("b.txt", "late\n", "dev@example.com", -72*time.Hour)
+	f.checkout("topic", true)
+	topic := f.commit("c.txt", "topic\n", "dev@example.com", 3*time.Hour)
+
+	tips, err := r.Tips()
+	require.NoError(t, err)
+	commits, err := r.Commits(context.Background(), tips, seen, base.Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{late.String(): "master", topic.String(): "topic"}, shas(commits))
+
+	// Nothing is new when the tips haven't moved.
+	var now []string
+	for _, tip := range tips {
+		now = append(now, tip.Hash)
+	}
+	commits, err = r.Commits(context.Background(), tips, now, base.Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+}
+
+func TestCommits_Floor(t *testing.T) {
+	f := newFixture(t)
+	f.commit("a.txt", "old\n", "dev@example.com", -48*time.Hour)
+	recent := f.commit("a.txt", "new\n", "dev@example.com", 0)
+
+	r := f.open()
+	tips, err := r.Tips()
+	require.NoError(t, err)
+	commits, err := r.Commits(context.Background(), tips, nil, base.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{recent.String(): "master"}, shas(commits))
+}
+
+func TestCommits_MergeHasNoLineCounts(t *testing.T) {
+	f := newFixture(t)
+	a := f.commit("a.txt", "1\n", "dev@example.com", 0)
+	f.checkout("side", true)
+	side := f.commit("b.txt", "side\n", "dev@example.com", time.Hour)
+	f.checkout("master", false)
+	main := f.commit("a.txt", "2\n", "dev@example.com", 2*time.Hour)
+	merge := f.commit("c.txt", "merged\n", "dev@example.com", 3*time.Hour, main, side)
+
+	r := f.open()
+	tips, err := r.Tips()
+	require.NoError(t, err)
+	commits, err := r.Commits(context.Background(), tips, nil, base.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, commits, 4)
+	assert.Contains(t, shas(commits), a.String())
+
+	assert.Equal(t, merge.String(), commits[0].SHA)
+	assert.Zero(t, commits[0].Additions)
+}
+
+func TestName(t *testing.T) {
+	f := newFixture(t)
+	f.commit("a.txt", "1\n", "dev@example.com", 0)
+	assert.Equal(t, filepath.Base(f.dir), f.open().Name())
+
+	_, err := f.repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"git@git.internal:team/tool.git"}})
+	require.NoError(t, err)
+	assert.Equal(t, "team/tool", f.open().Name())
+}
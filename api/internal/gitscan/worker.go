@@ -0,0 +1,209 @@
+package gitscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/gitagent"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+)
+
+// historyDays is how far back a scan reaches. The first scan of a
+// repository imports this much history; later scans only read new commits
+// but still skip anything older. When users are added or change emails,
+// the next scan reads the whole window again so their earlier commits are
+// picked up.
+const historyDays = 365
+
+// scanTimeout bounds one run. A first scan diffs a year of commits per
+// repository; state is saved per repository, so a timed-out run resumes
+// where it stopped.
+const scanTimeout = 30 * time.Minute
+
+// ScanArgs are the arguments for the periodic local repository scan.
+type ScanArgs struct{}
+
+func (ScanArgs) Kind() string { return "git_scan" }
+
+// ScanWorker imports commits from the configured repositories and
+// attributes them to users by author email. Commits are stored like the
+// hook agent reports them, under source "git" keyed by SHA, so a commit
+// seen both ways is stored once.
+type ScanWorker struct {
+	riverlib.WorkerDefaults[ScanArgs]
+	q     *dbgen.Queries
+	repos []string
+}
+
+// NewScanWorker creates the worker for the given repository paths, which
+// may be glob patterns like /srv/git/*.git.
+func NewScanWorker(q *dbgen.Queries, repos []string) *ScanWorker {
+	return &ScanWorker{q: q, repos: repos}
+}
+
+func (w *ScanWorker) Timeout(job *riverlib.Job[ScanArgs]) time.Duration {
+	return scanTimeout
+}
+
+// Work scans each repository in turn. A repository that fails is logged
+// and retried on the next run rather than failing the others.
+func (w *ScanWorker) Work(ctx context.Context, job *riverlib.Job[ScanArgs]) error {
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	floor := time.Now().AddDate(0, 0, -historyDays)
+	digest, err := w.q.GetUserEmailsDigest(ctx)
+	if err != nil {
+		return fmt.Errorf("digest user emails: %w", err)
+	}
+	for _, path := range expandRepos(w.repos) {
+		if err := w.scan(ctx, client, path, floor, digest); err != nil {
+			slog.Error("git scan failed", "repo", path, "error", err)
+		}
+	}
+	return ctx.Err()
+}
+
+func (w *ScanWorker) scan(ctx context.Context, client *riverlib.Client[pgx.Tx], path string, floor time.Time, digest string) error {
+	repo, err := OpenRepo(path)
+	if err != nil {
+		return err
+	}
+	tips, err := repo.Tips()
+	if err != nil {
+		return fmt.Errorf("list refs: %w", err)
+	}
+
+	state, err := w.q.GetGitScanState(ctx, path)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("get scan state: %w", err)
+	}
+
+	commits, err := repo.Commits(ctx, tips, seenTips(state, digest), floor)
+	if err != nil {
+		return err
+	}
+	users, err := w.authors(ctx, commits)
+	if err != nil {
+		return err
+	}
+
+	touched := make(map[int64][]time.Time)
+	inserted := 0
+	for _, c := range commits {
+		userID, ok := users[strings.ToLower(c.AuthorEmail)]
+		if !ok {
+			continue
+		}
+		n, err := w.q.InsertActivity(ctx, activity(c).InsertParams(userID, gitagent.SourceName))
+		if err != nil {
+			return fmt.Errorf("insert commit %s: %w", c.SHA, err)
+		}
+		if n > 0 {
+			inserted++
+			touched[userID] = append(touched[userID], c.CommittedAt)
+		}
+	}
+	for userID, times := range touched {
+		if err := summary.Reaggregate(ctx, w.q, client, userID, times); err != nil {
+			return err
+		}
+	}
+
+	hashes := make([]string, 0, len(tips))
+	for _, t := range tips {
+		hashes = append(hashes, t.Hash)
+	}
+	if err := w.q.UpsertGitScanState(ctx, dbgen.UpsertGitScanStateParams{
+		RepoPath:      path,
+		Tips:          hashes,
+		AuthorsDigest: digest,
+	}); err != nil {
+		return fmt.Errorf("save scan state: %w", err)
+	}
+
+	slog.Info("git repository scanned", "repo", path, "commits", len(commits), "inserted", inserted)
+	return nil
+}
+
+// seenTips returns the tips whose history a scan can skip. Commits by
+// unknown emails were skipped when the state was saved, so if the users'
+// emails have changed since, nothing is skipped; inserts are keyed by SHA,
+// so commits stored before aren't duplicated.
+func seenTips(state dbgen.GitScanState, digest string) []string {
+	if state.AuthorsDigest != digest {
+		return nil
+	}
+	return state.Tips
+}
+
+// authors maps the lowercased author emails of commits to user IDs.
+// Commits by people without a DevPulse account are skipped.
+func (w *ScanWorker) authors(ctx context.Context, commits []gitagent.Commit) (map[string]int64, error) {
+	emails := distinctEmails(commits)
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	rows, err := w.q.ListUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, fmt.Errorf("match authors: %w", err)
+	}
+	users := make(map[string]int64, len(rows))
+	for _, r := range rows {
+		users[r.Email] = r.ID
+	}
+	return users, nil
+}
+
+// activity converts a commit to the activity the hook agent would upload
+// for it.
+func activity(c gitagent.Commit) provider.Activity {
+	a := gitagent.CommitActivity(c)
+	return provider.Activity{
+		Type:       a.Type,
+		OccurredAt: a.OccurredAt,
+		ExternalID: a.ExternalID,
+		Payload:    a.Payload,
+	}
+}
+
+func distinctEmails(commits []gitagent.Commit) []string {
+	seen := make(map[string]bool)
+	var emails []string
+	for _, c := range commits {
+		email := strings.ToLower(c.AuthorEmail)
+		if email != "" && !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// expandRepos resolves glob patterns in the configured paths. Plain paths
+// are kept as they are so a missing repository is reported by the scan.
+func expandRepos(patterns []string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, p := range patterns {
+		matches := []string{p}
+		if strings.ContainsAny(p, "*?[") {
+			matches, _ = filepath.Glob(p)
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				paths = append(paths, m)
+			}
+		}
+	}
+	return paths
+}
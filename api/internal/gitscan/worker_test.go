@@ -0,0 +1,73 @@
+package gitscan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/gitagent"
+)
+
+func TestExpandRepos(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.git", "b.git", "notes"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, name), 0o755))
+	}
+
+	got := expandRepos([]string{
+		filepath.Join(dir, "*.git"),
+		filepath.Join(dir, "a.git"),
+		"/srv/missing",
+	})
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a.git"),
+		filepath.Join(dir, "b.git"),
+		"/srv/missing",
+	}, got)
+}
+
+func TestSeenTips(t *testing.T) {
+	state := dbgen.GitScanState{Tips: []string{"abc123"}, AuthorsDigest: "d1"}
+
+	assert.Equal(t, []string{"abc123"}, seenTips(state, "d1"))
+	assert.Nil(t, seenTips(state, "d2"), "new users rescan from the history floor")
+	assert.Nil(t, seenTips(dbgen.GitScanState{}, "d1"))
+}
+
+func TestDistinctEmails(t *testing.T) {
+	commits := []gitagent.Commit{
+		{AuthorEmail: "Dev@Example.com"},
+		{AuthorEmail: "dev@example.com"},
+		{AuthorEmail: ""},
+		{AuthorEmail: "ops@example.com"},
+	}
+	assert.Equal(t, []string{"dev@example.com", "ops@example.com"}, distinctEmails(commits))
+}
+
+func TestActivity(t *testing.T) {
+	at := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	act := activity(gitagent.Commit{SHA: "abc123", Repo: "team/tool", Branch: "main", CommittedAt: at})
+
+	assert.Equal(t, "push", act.Type)
+	assert.Equal(t, "abc123", act.ExternalID)
+	assert.Equal(t, at, act.OccurredAt)
+
+	var payload struct {
+		Repo    string `json:"repo"`
+		Payload struct {
+			Commits []struct {
+				SHA string `json:"sha"`
+			} `json:"commits"`
+		} `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(act.Payload, &payload))
+	assert.Equal(t, "team/tool", payload.Repo)
+	require.Len(t, payload.Payload.Commits, 1)
+	assert.Equal(t, "abc123", payload.Payload.Commits[0].SHA)
+}
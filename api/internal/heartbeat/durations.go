@@ -0,0 +1,140 @@
+package heartbeat
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+// durationTimeout is the longest gap between two heartbeats that still
+// counts as continuous work, WakaTime's default keystroke timeout.
+const durationTimeout = 15 * time.Minute
+
+// unknown labels heartbeats without a project, language or editor.
+const unknown = "Unknown"
+
+// codingCategories are the WakaTime heartbeat categories that count as
+// coding time. Browsing, meetings, research and the like don't.
+var codingCategories = map[string]bool{
+	"ai coding":      true,
+	"building":       true,
+	"code reviewing": true,
+	"coding":         true,
+	"debugging":      true,
+	"indexing":       true,
+	"manual testing": true,
+	"running tests":  true,
+	"writing docs":   true,
+	"writing tests":  true,
+}
+
+// ProjectDuration is the time spent on one project over a day, broken down
+// by language and editor.
+type ProjectDuration struct {
+	Project   string
+	Seconds   float64
+	Languages map[string]float64
+	Editors   map[string]float64
+}
+
+// computeDurations credits the gap after each heartbeat before end to that
+// heartbeat's project, language and editor. Gaps longer than
+// durationTimeout are idle time, so a lone heartbeat counts for nothing.
+// Gaps after heartbeats outside codingCategories aren't coding either,
+// but those heartbeats still end the coding gap before them.
+// Heartbeats must be sorted by time; the ones at or after end only close
+// the last gap of the day.
+func computeDurations(hbs []dbgen.ListHeartbeatsInRangeRow, end time.Time) []ProjectDuration {
+	byProject := make(map[string]*ProjectDuration)
+	for i := 0; i+1 < len(hbs); i++ {
+		cur := hbs[i]
+		if !cur.Time.Time.Before(end) {
+			break
+		}
+		gap := hbs[i+1].Time.Time.Sub(cur.Time.Time)
+		if gap <= 0 || gap > durationTimeout || !codingCategories[cur.Category] {
+			continue
+		}
+
+		project := orUnknown(cur.Project)
+		d, ok := byProject[project]
+		if !ok {
+			d = &ProjectDuration{
+				Project:   project,
+				Languages: make(map[string]float64),
+				Editors:   make(map[string]float64),
+			}
+			byProject[project] = d
+		}
+		secs := gap.Seconds()
+		d.Seconds += secs
+		d.Languages[orUnknown(cur.Language)] += secs
+		d.Editors[orUnknown(cur.Editor)] += secs
+	}
+
+	durations := make([]ProjectDuration, 0, len(byProject))
+	for _, d := range byProject {
+		durations = append(durations, *d)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i].Project < durations[j].Project })
+	return durations
+}
+
+// durationActivities turns a day's durations into one "coding" activity per
+// project, in the same shape the WakaTime provider stores, so the daily
+// summary counts them as coding minutes. The external ID is stable per
+// (date, project) so later heartbeats update the same row.
+func durationActivities(date string, dayStart time.Time, durations []ProjectDuration) []provider.Activity {
+	acts := make([]provider.Activity, 0, len(durations))
+	for _, d := range durations {
+		payload, _ := json.Marshal(map[string]any{
+			"project":   d.Project,
+			"seconds":   math.Round(d.Seconds),
+			"date":      date,
+			"languages": roundSeconds(d.Languages),
+			"editors":   roundSeconds(d.Editors),
+		})
+		acts = append(acts, provider.Activity{
+			Type:       "coding",
+			OccurredAt: dayStart,
+			ExternalID: date + ":" + d.Project,
+			Payload:    payload,
+			Mutable:    true,
+		})
+	}
+	return acts
+}
+
+// editorFromUserAgent extracts the editor from a wakatime-cli user agent
+// such as "wakatime/v1.90.0 (linux-x86_64) go1.21.5 vscode/1.85.1
+// vscode-wakatime/24.3.0", where the plugin is named "<editor>-wakatime".
+func editorFromUserAgent(ua string) string {
+	fields := strings.Fields(ua)
+	for i := len(fields) - 1; i >= 0; i-- {
+		name, _, _ := strings.Cut(fields[i], "/")
+		if editor, ok := strings.CutSuffix(name, "-wakatime"); ok && editor != "" {
+			return editor
+		}
+	}
+	return ""
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return unknown
+	}
+	return s
+}
+
+func roundSeconds(m map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		out[k] = math.Round(v)
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package heartbeat
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+func beat(at time.Time, project, language, editor string) dbgen.ListHeartbeatsInRangeRow {
+	return dbgen.ListHeartbeatsInRangeRow{
+		Time:     pgtype.Timestamptz{Time: at, Valid: true},
+		Project:  project,
+		Language: language,
+		Editor:   editor,
+		Category: "coding",
+	}
+}
+
+func TestComputeDurations(t *testing.T) {
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	hbs := []dbgen.ListHeartbeatsInRangeRow{
+		beat(at(9*time.Hour), "devpulse", "Go", "vscode"),
+		beat(at(9*time.Hour+2*time.Minute), "devpulse", "Go", "vscode"),
+		beat(at(9*time.Hour+5*time.Minute), "devpulse", "SQL", "vscode"),
+		// 30 minute break: the gap after the SQL heartbeat is idle.
+		beat(at(9*time.Hour+35*time.Minute), "", "", ""),
+		beat(at(9*time.Hour+36*time.Minute), "dotfiles", "Shell", "vim"),
+		// Closes the last gap of the day; its own time belongs to the next day.
+		beat(at(23*time.Hour+58*time.Minute), "devpulse", "Go", "vscode"),
+		beat(end.Add(time.Minute), "devpulse", "Go", "vscode"),
+		beat(end.Add(3*time.Minute), "devpulse", "Go", "vscode"),
+	}
+
+	got := computeDurations(hbs, end)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "Unknown", got[0].Project)
+	assert.Equal(t, 60.0, got[0].Seconds)
+	assert.Equal(t, map[string]float64{"Unknown": 60}, got[0].Languages)
+
+	assert.Equal(t, "devpulse", got[1].Project)
+	assert.Equal(t, 480.0, got[1].Seconds)
+	assert.Equal(t, map[string]float64{"Go": 480}, got[1].Languages)
+	assert.Equal(t, map[string]float64{"vscode": 480}, got[1].Editors)
+}
+
+func TestComputeDurations_SkipsNonCodingCategories(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	browsing := beat(start.Add(2*time.Minute), "devpulse", "", "chrome")
+	browsing.Category = "browsing"
+	tests := beat(start.Add(5*time.Minute), "devpulse", "Go", "vscode")
+	tests.Category = "running tests"
+
+	hbs := []dbgen.ListHeartbeatsInRangeRow{
+		beat(start, "devpulse", "Go", "vscode"),
+		// Ends the coding gap, but the three minutes after it are browsing
+		browsing,
+		tests,
+		beat(start.Add(6*time.Minute), "devpulse", "Go", "vscode"),
+	}
+
+	got := computeDurations(hbs, start.Add(time.Hour))
+	require.Len(t, got, 1)
+	assert.Equal(t, 180.0, got[0].Seconds)
+	assert.Equal(t, map[string]float64{"Go": 180}, got[0].Languages)
+}
+
+func TestComputeDurations_Empty(t *testing.T) {
+	end := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+
+	assert.Empty(t, computeDurations(nil, end))
+	assert.Empty(t, computeDurations([]dbgen.ListHeartbeatsInRangeRow{beat(end.Add(-time.Hour), "p", "Go", "vim")}, end))
+}
+
+func TestDurationActivities(t *testing.T) {
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	acts := durationActivities("2026-03-10", start, []ProjectDuration{{
+		Project:   "devpulse",
+		Seconds:   299.6,
+		Languages: map[string]float64{"Go": 200.2, "SQL": 99.4},
+		Editors:   map[string]float64{"vscode": 299.6},
+	}})
+	require.Len(t, acts, 1)
+
+	act := acts[0]
+	assert.Equal(t, "coding", act.Type)
+	assert.Equal(t, "2026-03-10:devpulse", act.ExternalID)
+	assert.Equal(t, start, act.OccurredAt)
+	assert.True(t, act.Mutable)
+
+	var payload struct {
+		Project   string             `json:"project"`
+		Seconds   float64            `json:"seconds"`
+		Date      string             `json:"date"`
+		Languages map[string]float64 `json:"languages"`
+		Editors   map[string]float64 `json:"editors"`
+	}
+	require.NoError(t, json.Unmarshal(act.Payload, &payload))
+	assert.Equal(t, "devpulse", payload.Project)
+	assert.Equal(t, 300.0, payload.Seconds)
+	assert.Equal(t, "2026-03-10", payload.Date)
+	assert.Equal(t, map[string]float64{"Go": 200, "SQL": 99}, payload.Languages)
+	assert.Equal(t, map[string]float64{"vscode": 300}, payload.Editors)
+}
+
+func TestEditorFromUserAgent(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{ua: "wakatime/v1.90.0 (linux-6.5.0-x86_64) go1.21.5 vscode/1.85.1 vscode-wakatime/24.3.0", want: "vscode"},
+		{ua: "wakatime/v1.90.0 (darwin-23.1.0-arm64) go1.21.5 vim/9.0 vim-wakatime/11.1.1", want: "vim"},
+		{ua: "wakatime/v1.90.0 (linux-6.5.0-x86_64) go1.21.5", want: ""},
+		{ua: "curl/8.4.0", want: ""},
+		{ua: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ua, func(t *testing.T) {
+			assert.Equal(t, tt.want, editorFromUserAgent(tt.ua))
+		})
+	}
+}
+
+func TestRequestOccurredAt(t *testing.T) {
+	r := Request{Time: 1773144000.25}
+	assert.Equal(t, time.Date(2026, 3, 10, 12, 0, 0, 250_000_000, time.UTC), r.occurredAt())
+}
@@ -0,0 +1,94 @@
+package heartbeat
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+	"github.com/ethanwang/devpulse/api/internal/validate"
+)
+
+// maxBulk caps one bulk upload. wakatime-cli sends at most 25 heartbeats
+// per request.
+const maxBulk = 100
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterRoutes mounts the WakaTime-compatible heartbeat routes. Plugins
+// append these paths to their api_url, so the group should be mounted at
+// /api/v1 behind API key middleware.
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.POST("/users/current/heartbeats", h.Create)
+	g.POST("/users/current/heartbeats.bulk", h.CreateBulk)
+}
+
+func (h *Handler) Create(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req Request
+	if err := c.Bind(&req); err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := h.svc.Record(c.Request().Context(), userID, c.Request().UserAgent(), []Request{req}); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, Response{Data: toData(req)})
+}
+
+// CreateBulk stores a batch of heartbeats. Invalid entries are reported in
+// their slot of the response without failing the rest.
+func (h *Handler) CreateBulk(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var reqs []Request
+	if err := c.Bind(&reqs); err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+	if len(reqs) > maxBulk {
+		return apperror.BadRequest("too many heartbeats")
+	}
+
+	resp := BulkResponse{Responses: make([][2]any, len(reqs))}
+	valid := make([]Request, 0, len(reqs))
+	for i, req := range reqs {
+		if err := validate.Struct(req); err != nil {
+			resp.Responses[i] = [2]any{map[string]string{"error": err.Error()}, http.StatusBadRequest}
+			continue
+		}
+		valid = append(valid, req)
+		resp.Responses[i] = [2]any{Response{Data: toData(req)}, http.StatusCreated}
+	}
+
+	if err := h.svc.Record(c.Request().Context(), userID, c.Request().UserAgent(), valid); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func toData(r Request) Data {
+	category := r.Category
+	if category == "" {
+		category = defaultCategory
+	}
+	return Data{Entity: r.Entity, Type: r.Type, Category: category, Time: r.Time}
+}
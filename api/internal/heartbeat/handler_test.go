@@ -0,0 +1,71 @@
+package heartbeat
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+)
+
+func newContext(path, body string) (*echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(mw.ContextKeyUserID, int64(1))
+	return c, rec
+}
+
+func TestCreate_MissingEntity(t *testing.T) {
+	c, _ := newContext("/api/v1/users/current/heartbeats", `{"type":"file","time":1773144000}`)
+
+	h := NewHandler(nil)
+	err := h.Create(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+func TestCreateBulk_TooMany(t *testing.T) {
+	items := make([]string, maxBulk+1)
+	for i := range items {
+		items[i] = `{"entity":"main.go","type":"file","time":1773144000}`
+	}
+	c, _ := newContext("/api/v1/users/current/heartbeats.bulk", "["+strings.Join(items, ",")+"]")
+
+	h := NewHandler(nil)
+	err := h.CreateBulk(c)
+
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+func TestCreateBulk_ReportsInvalidEntries(t *testing.T) {
+	body := `[{"type":"file","time":1773144000},{"entity":"main.go","type":"file"}]`
+	c, rec := newContext("/api/v1/users/current/heartbeats.bulk", body)
+
+	h := NewHandler(NewService(nil, nil))
+	require.NoError(t, h.CreateBulk(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var resp struct {
+		Responses [][2]json.RawMessage `json:"responses"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Responses, 2)
+	for _, r := range resp.Responses {
+		assert.JSONEq(t, "400", string(r[1]))
+		assert.Contains(t, string(r[0]), `"error"`)
+	}
+}
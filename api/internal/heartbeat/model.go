@@ -0,0 +1,46 @@
+package heartbeat
+
+import (
+	"math"
+	"time"
+)
+
+// Request is a heartbeat as WakaTime editor plugins send it. Fields the
+// plugins send that DevPulse doesn't use, like lineno or dependencies, are
+// ignored.
+type Request struct {
+	Entity    string  `json:"entity" validate:"required,max=2048"`
+	Type      string  `json:"type" validate:"required"`
+	Category  string  `json:"category"`
+	Time      float64 `json:"time" validate:"required,gt=0"`
+	Project   string  `json:"project"`
+	Branch    string  `json:"branch"`
+	Language  string  `json:"language"`
+	IsWrite   bool    `json:"is_write"`
+	UserAgent string  `json:"user_agent"`
+}
+
+// occurredAt converts the fractional Unix timestamp plugins send.
+func (r Request) occurredAt() time.Time {
+	sec, frac := math.Modf(r.Time)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
+
+// Data is the heartbeat echoed back in WakaTime's response envelope.
+type Data struct {
+	Entity   string  `json:"entity"`
+	Type     string  `json:"type"`
+	Category string  `json:"category"`
+	Time     float64 `json:"time"`
+}
+
+// Response is the body of a single heartbeat upload.
+type Response struct {
+	Data Data `json:"data"`
+}
+
+// BulkResponse is the body of a bulk upload. Each entry is a
+// [body, status] pair in request order, as WakaTime returns them.
+type BulkResponse struct {
+	Responses [][2]any `json:"responses"`
+}
@@ -0,0 +1,76 @@
+package heartbeat
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+)
+
+// SourceName is the activity source for coding time computed from
+// heartbeats.
+const SourceName = "heartbeat"
+
+// defaultCategory is WakaTime's category for heartbeats that don't set one.
+const defaultCategory = "coding"
+
+type Service struct {
+	q     *dbgen.Queries
+	river *riverlib.Client[pgx.Tx]
+}
+
+// NewService creates the heartbeat service. The River client is used to
+// recompute durations for the days heartbeats land on.
+func NewService(q *dbgen.Queries, river *riverlib.Client[pgx.Tx]) *Service {
+	return &Service{q: q, river: river}
+}
+
+// Record stores heartbeats and schedules their days' durations to be
+// recomputed. userAgent names the editor for heartbeats that don't carry
+// their own user agent. Heartbeats already stored are skipped.
+func (s *Service) Record(ctx context.Context, userID int64, userAgent string, reqs []Request) error {
+	var inserted []time.Time
+	for _, r := range reqs {
+		ua := r.UserAgent
+		if ua == "" {
+			ua = userAgent
+		}
+		category := r.Category
+		if category == "" {
+			category = defaultCategory
+		}
+		at := r.occurredAt()
+
+		n, err := s.q.InsertHeartbeat(ctx, dbgen.InsertHeartbeatParams{
+			UserID:   userID,
+			Entity:   r.Entity,
+			Type:     r.Type,
+			Category: category,
+			Project:  r.Project,
+			Branch:   r.Branch,
+			Language: r.Language,
+			Editor:   editorFromUserAgent(ua),
+			IsWrite:  r.IsWrite,
+			Time:     pgtype.Timestamptz{Time: at, Valid: true},
+		})
+		if err != nil {
+			return apperror.Internalf("insert heartbeat: %w", err)
+		}
+		if n > 0 {
+			inserted = append(inserted, at)
+		}
+	}
+
+	if err := scheduleDurations(ctx, s.q, s.river, userID, inserted); err != nil {
+		// The heartbeats are stored; a plugin retry would be deduplicated
+		// and not reschedule either, so don't fail the upload.
+		slog.Error("enqueue heartbeat durations failed", "user_id", userID, "error", err)
+	}
+	return nil
+}
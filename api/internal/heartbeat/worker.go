@@ -0,0 +1,123 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+)
+
+// durationsDelay batches a plugin's stream of uploads: heartbeats that land
+// on the same day shortly after each other share one recomputation.
+const durationsDelay = time.Minute
+
+// DurationsArgs are the arguments for recomputing one user's coding
+// durations for a local day.
+type DurationsArgs struct {
+	UserID int64  `json:"user_id"`
+	Date   string `json:"date"`
+}
+
+func (DurationsArgs) Kind() string { return "heartbeat_durations" }
+
+// InsertOpts dedupes against jobs that haven't finished yet, so a day is
+// recomputed again once new heartbeats arrive after the last run.
+func (DurationsArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		UniqueOpts: riversetup.UniqueUnfinished(durationsDelay),
+	}
+}
+
+// DurationsWorker turns a day's heartbeats into per-project "coding"
+// activities and re-aggregates the day's summary when they change.
+type DurationsWorker struct {
+	riverlib.WorkerDefaults[DurationsArgs]
+	q *dbgen.Queries
+}
+
+func NewDurationsWorker(q *dbgen.Queries) *DurationsWorker {
+	return &DurationsWorker{q: q}
+}
+
+func (w *DurationsWorker) Work(ctx context.Context, job *riverlib.Job[DurationsArgs]) error {
+	userID := job.Args.UserID
+	tz, err := w.q.GetUserTimezone(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("user %d not found", userID))
+	}
+	if err != nil {
+		return err
+	}
+
+	start, err := time.ParseInLocation(time.DateOnly, job.Args.Date, summary.LoadLocation(tz))
+	if err != nil {
+		return riverlib.JobCancel(fmt.Errorf("parse date %q: %w", job.Args.Date, err))
+	}
+	end := start.AddDate(0, 0, 1)
+
+	// Read past midnight so the day's last gap can be closed.
+	hbs, err := w.q.ListHeartbeatsInRange(ctx, dbgen.ListHeartbeatsInRangeParams{
+		UserID:  userID,
+		Column2: pgtype.Timestamptz{Time: start, Valid: true},
+		Column3: pgtype.Timestamptz{Time: end.Add(durationTimeout), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("list heartbeats: %w", err)
+	}
+
+	changed := false
+	for _, act := range durationActivities(job.Args.Date, start, computeDurations(hbs, end)) {
+		n, err := w.q.UpsertActivity(ctx, dbgen.UpsertActivityParams(act.InsertParams(userID, SourceName)))
+		if err != nil {
+			return fmt.Errorf("upsert coding activity: %w", err)
+		}
+		changed = changed || n > 0
+	}
+	if !changed {
+		return nil
+	}
+
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	return summary.Reaggregate(ctx, w.q, client, userID, []time.Time{start})
+}
+
+// scheduleDurations enqueues a DurationsArgs job for each of the user's
+// local days that contains one of the given heartbeat times.
+func scheduleDurations(ctx context.Context, q *dbgen.Queries, client *riverlib.Client[pgx.Tx], userID int64, times []time.Time) error {
+	if len(times) == 0 {
+		return nil
+	}
+
+	tz, err := q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get timezone: %w", err)
+	}
+	loc := summary.LoadLocation(tz)
+
+	seen := make(map[string]bool)
+	params := make([]riverlib.InsertManyParams, 0)
+	for _, t := range times {
+		date := t.In(loc).Format(time.DateOnly)
+		if seen[date] {
+			continue
+		}
+		seen[date] = true
+		params = append(params, riverlib.InsertManyParams{
+			Args:       DurationsArgs{UserID: userID, Date: date},
+			InsertOpts: &riverlib.InsertOpts{ScheduledAt: time.Now().Add(durationsDelay)},
+		})
+	}
+
+	if _, err := client.InsertMany(ctx, params); err != nil {
+		return fmt.Errorf("enqueue heartbeat durations: %w", err)
+	}
+	return nil
+}
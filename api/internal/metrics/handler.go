@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(g *echo.Group) {
+	g.GET("/metrics/pull-requests", h.PullRequests)
+	g.GET("/metrics/reviews", h.Reviews)
+}
+
+func (h *Handler) PullRequests(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	weeks, _ := strconv.Atoi(c.QueryParam("weeks"))
+
+	resp, err := h.svc.PullRequests(c.Request().Context(), userID, weeks)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) Reviews(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	days, _ := strconv.Atoi(c.QueryParam("days"))
+
+	resp, err := h.svc.Reviews(c.Request().Context(), userID, days)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,278 @@
+// Package metrics serves engineering metrics: pull request cycle times and
+// the reviews a user gives.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+)
+
+type Service struct {
+	q *dbgen.Queries
+}
+
+func NewService(q *dbgen.Queries) *Service {
+	return &Service{q: q}
+}
+
+// location returns the user's timezone.
+func (s *Service) location(ctx context.Context, userID int64) (*time.Location, error) {
+	tz, err := s.q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("user not found")
+		}
+		return nil, apperror.Internalf("get timezone: %w", err)
+	}
+	return summary.LoadLocation(tz), nil
+}
+
+// --- Pull requests ---
+
+// PullRequestWeek describes the pull requests opened in one ISO week.
+// Closed counts those closed without merging. MergeRate is merged out of
+// merged and closed, and the medians cover the pull requests that got
+// that far; each is null when there are none yet.
+type PullRequestWeek struct {
+	Week                     string   `json:"week"`
+	Opened                   int32    `json:"opened"`
+	Merged                   int32    `json:"merged"`
+	Closed                   int32    `json:"closed"`
+	MergeRate                *float64 `json:"mergeRate"`
+	MedianHoursToFirstReview *float64 `json:"medianHoursToFirstReview"`
+	MedianHoursToMerge       *float64 `json:"medianHoursToMerge"`
+}
+
+type PullRequestMetricsResponse struct {
+	Weeks []PullRequestWeek `json:"weeks"`
+}
+
+// PullRequests returns cycle-time metrics for the last weeks weeks in the
+// user's timezone, oldest first, including the current week and weeks
+// without pull requests.
+func (s *Service) PullRequests(ctx context.Context, userID int64, weeks int) (*PullRequestMetricsResponse, error) {
+	if weeks < 1 || weeks > 52 {
+		weeks = 12
+	}
+
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	start := weekStart(time.Now().In(loc)).AddDate(0, 0, -7*(weeks-1))
+
+	rows, err := s.q.ListPullRequestWeeklyMetrics(ctx, dbgen.ListPullRequestWeeklyMetricsParams{
+		UserID:  userID,
+		Column2: pgtype.Timestamptz{Time: start, Valid: true},
+		Column3: loc.String(),
+	})
+	if err != nil {
+		return nil, apperror.Internalf("list pull request metrics: %w", err)
+	}
+
+	return &PullRequestMetricsResponse{Weeks: pullRequestWeeks(rows, start, weeks)}, nil
+}
+
+// weekStart returns midnight of the Monday starting t's ISO week.
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// pullRequestWeeks lays the rows out over weeks consecutive weeks from
+// start, filling weeks without a row with zeros.
+func pullRequestWeeks(rows []dbgen.ListPullRequestWeeklyMetricsRow, start time.Time, weeks int) []PullRequestWeek {
+	byDate := make(map[string]dbgen.ListPullRequestWeeklyMetricsRow, len(rows))
+	for _, r := range rows {
+		byDate[r.Week.Time.Format(time.DateOnly)] = r
+	}
+
+	result := make([]PullRequestWeek, 0, weeks)
+	for i := range weeks {
+		monday := start.AddDate(0, 0, 7*i)
+		year, week := monday.ISOWeek()
+		w := PullRequestWeek{Week: fmt.Sprintf("%d-W%02d", year, week)}
+		if r, ok := byDate[monday.Format(time.DateOnly)]; ok {
+			w.Opened, w.Merged, w.Closed = r.Opened, r.Merged, r.Closed
+			if resolved := r.Merged + r.Closed; resolved > 0 {
+				w.MergeRate = ptr(round(float64(r.Merged)/float64(resolved), 2))
+			}
+			if r.Reviewed > 0 {
+				w.MedianHoursToFirstReview = ptr(round(r.MedianFirstReviewSeconds/3600, 1))
+			}
+			if r.Merged > 0 {
+				w.MedianHoursToMerge = ptr(round(r.MedianMergeSeconds/3600, 1))
+			}
+		}
+		result = append(result, w)
+	}
+	return result
+}
+
+// --- Reviews ---
+
+// ReviewStates counts reviews by outcome. Dismissed reviews aren't counted.
+type ReviewStates struct {
+	Approved         int32 `json:"approved"`
+	ChangesRequested int32 `json:"changesRequested"`
+	Commented        int32 `json:"commented"`
+}
+
+type DayCount struct {
+	Date  string `json:"date"`
+	Count int32  `json:"count"`
+}
+
+type WeekCount struct {
+	Week  string `json:"week"`
+	Count int32  `json:"count"`
+}
+
+type NameCount struct {
+	Name  string `json:"name"`
+	Count int32  `json:"count"`
+}
+
+// ReviewMetricsResponse describes the reviews a user gave between From and
+// To (inclusive, in their timezone). Daily and Weekly include days and
+// weeks without reviews; the first and last week may be partial.
+// MedianTurnaroundHours is null until a review's request time is known.
+type ReviewMetricsResponse struct {
+	From                  string       `json:"from"`
+	To                    string       `json:"to"`
+	Total                 int32        `json:"total"`
+	States                ReviewStates `json:"states"`
+	MedianTurnaroundHours *float64     `json:"medianTurnaroundHours"`
+	Daily                 []DayCount   `json:"daily"`
+	Weekly                []WeekCount  `json:"weekly"`
+	TopRepos              []NameCount  `json:"topRepos"`
+	TopAuthors            []NameCount  `json:"topAuthors"`
+}
+
+// Reviews returns review metrics for the last days days, including today.
+func (s *Service) Reviews(ctx context.Context, userID int64, days int) (*ReviewMetricsResponse, error) {
+	if days < 1 || days > 365 {
+		days = 30
+	}
+
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day()-(days-1), 0, 0, 0, 0, loc)
+	since := pgtype.Timestamptz{Time: start, Valid: true}
+
+	dayRows, err := s.q.ListDailyReviewCounts(ctx, dbgen.ListDailyReviewCountsParams{
+		UserID:  userID,
+		Column2: since,
+		Column3: loc.String(),
+	})
+	if err != nil {
+		return nil, apperror.Internalf("list daily reviews: %w", err)
+	}
+	stateRows, err := s.q.ListReviewStateCounts(ctx, dbgen.ListReviewStateCountsParams{UserID: userID, Column2: since})
+	if err != nil {
+		return nil, apperror.Internalf("list review states: %w", err)
+	}
+	turnaround, err := s.q.GetReviewTurnaround(ctx, dbgen.GetReviewTurnaroundParams{UserID: userID, Column2: since})
+	if err != nil {
+		return nil, apperror.Internalf("get review turnaround: %w", err)
+	}
+	repoRows, err := s.q.ListTopReviewedRepos(ctx, dbgen.ListTopReviewedReposParams{UserID: userID, Column2: since})
+	if err != nil {
+		return nil, apperror.Internalf("list reviewed repos: %w", err)
+	}
+	authorRows, err := s.q.ListTopReviewedAuthors(ctx, dbgen.ListTopReviewedAuthorsParams{UserID: userID, Column2: since})
+	if err != nil {
+		return nil, apperror.Internalf("list reviewed authors: %w", err)
+	}
+
+	daily := reviewDays(dayRows, start, days)
+	resp := &ReviewMetricsResponse{
+		From:       daily[0].Date,
+		To:         daily[len(daily)-1].Date,
+		States:     reviewStates(stateRows),
+		Daily:      daily,
+		Weekly:     reviewWeeks(daily),
+		TopRepos:   make([]NameCount, 0, len(repoRows)),
+		TopAuthors: make([]NameCount, 0, len(authorRows)),
+	}
+	for _, d := range daily {
+		resp.Total += d.Count
+	}
+	if turnaround.Reviews > 0 {
+		resp.MedianTurnaroundHours = ptr(round(turnaround.MedianSeconds/3600, 1))
+	}
+	for _, r := range repoRows {
+		resp.TopRepos = append(resp.TopRepos, NameCount{Name: r.Name, Count: r.Count})
+	}
+	for _, r := range authorRows {
+		resp.TopAuthors = append(resp.TopAuthors, NameCount{Name: r.Name, Count: r.Count})
+	}
+	return resp, nil
+}
+
+// reviewDays lays the rows out over days consecutive days from start,
+// filling days without reviews with zeros.
+func reviewDays(rows []dbgen.ListDailyReviewCountsRow, start time.Time, days int) []DayCount {
+	counts := make(map[string]int32, len(rows))
+	for _, r := range rows {
+		counts[r.Date.Time.Format(time.DateOnly)] = r.Count
+	}
+
+	result := make([]DayCount, 0, days)
+	for i := range days {
+		date := start.AddDate(0, 0, i).Format(time.DateOnly)
+		result = append(result, DayCount{Date: date, Count: counts[date]})
+	}
+	return result
+}
+
+// reviewWeeks sums consecutive days by ISO week.
+func reviewWeeks(daily []DayCount) []WeekCount {
+	var weeks []WeekCount
+	for _, d := range daily {
+		t, _ := time.Parse(time.DateOnly, d.Date)
+		year, week := t.ISOWeek()
+		label := fmt.Sprintf("%d-W%02d", year, week)
+		if len(weeks) == 0 || weeks[len(weeks)-1].Week != label {
+			weeks = append(weeks, WeekCount{Week: label})
+		}
+		weeks[len(weeks)-1].Count += d.Count
+	}
+	return weeks
+}
+
+// reviewStates maps the lowercased review states providers store.
+func reviewStates(rows []dbgen.ListReviewStateCountsRow) ReviewStates {
+	var states ReviewStates
+	for _, r := range rows {
+		switch r.State {
+		case "approved":
+			states.Approved += r.Count
+		case "changes_requested":
+			states.ChangesRequested += r.Count
+		case "commented":
+			states.Commented += r.Count
+		}
+	}
+	return states
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
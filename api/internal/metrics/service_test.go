@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+func TestWeekStart(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	// Sunday evening belongs to the week that started the previous Monday
+	sunday := time.Date(2026, 3, 8, 20, 0, 0, 0, tokyo)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, tokyo), weekStart(sunday))
+
+	monday := time.Date(2026, 3, 9, 0, 30, 0, 0, tokyo)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, tokyo), weekStart(monday))
+}
+
+func TestPullRequestWeeks(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rows := []dbgen.ListPullRequestWeeklyMetricsRow{{
+		Week:                     pgtype.Date{Time: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), Valid: true},
+		Opened:                   4,
+		Merged:                   2,
+		Closed:                   1,
+		Reviewed:                 3,
+		MedianFirstReviewSeconds: 5400,
+		MedianMergeSeconds:       90000,
+	}}
+
+	weeks := pullRequestWeeks(rows, start, 3)
+
+	require.Len(t, weeks, 3)
+	assert.Equal(t, PullRequestWeek{Week: "2026-W10"}, weeks[0])
+	assert.Equal(t, "2026-W11", weeks[1].Week)
+	assert.Equal(t, int32(4), weeks[1].Opened)
+	assert.InDelta(t, 0.67, *weeks[1].MergeRate, 1e-9)
+	assert.InDelta(t, 1.5, *weeks[1].MedianHoursToFirstReview, 1e-9)
+	assert.InDelta(t, 25.0, *weeks[1].MedianHoursToMerge, 1e-9)
+	assert.Equal(t, "2026-W12", weeks[2].Week)
+}
+
+func TestPullRequestWeeks_Unresolved(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rows := []dbgen.ListPullRequestWeeklyMetricsRow{{
+		Week:   pgtype.Date{Time: start, Valid: true},
+		Opened: 2,
+	}}
+
+	weeks := pullRequestWeeks(rows, start, 1)
+
+	require.Len(t, weeks, 1)
+	assert.Equal(t, int32(2), weeks[0].Opened)
+	assert.Nil(t, weeks[0].MergeRate)
+	assert.Nil(t, weeks[0].MedianHoursToFirstReview)
+	assert.Nil(t, weeks[0].MedianHoursToMerge)
+}
+
+func TestReviewDaysAndWeeks(t *testing.T) {
+	// Saturday, so the first week is partial
+	start := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	rows := []dbgen.ListDailyReviewCountsRow{
+		{Date: pgtype.Date{Time: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), Valid: true}, Count: 2},
+		{Date: pgtype.Date{Time: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Valid: true}, Count: 3},
+	}
+
+	daily := reviewDays(rows, start, 4)
+
+	assert.Equal(t, []DayCount{
+		{Date: "2026-03-07", Count: 0},
+		{Date: "2026-03-08", Count: 2},
+		{Date: "2026-03-09", Count: 0},
+		{Date: "2026-03-10", Count: 3},
+	}, daily)
+	assert.Equal(t, []WeekCount{
+		{Week: "2026-W10", Count: 2},
+		{Week: "2026-W11", Count: 3},
+	}, reviewWeeks(daily))
+}
+
+func TestReviewStates(t *testing.T) {
+	rows := []dbgen.ListReviewStateCountsRow{
+		{State: "approved", Count: 4},
+		{State: "changes_requested", Count: 1},
+		{State: "commented", Count: 6},
+		{State: "dismissed", Count: 2},
+		{State: "", Count: 1},
+	}
+
+	assert.Equal(t, ReviewStates{Approved: 4, ChangesRequested: 1, Commented: 6}, reviewStates(rows))
+}
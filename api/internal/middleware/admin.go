@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+)
+
+// RequireAdmin returns middleware that only lets the given user IDs through.
+// It must run after JWTAuth.
+func RequireAdmin(adminIDs []int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			userID, err := GetUserID(c)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(adminIDs, userID) {
+				return apperror.Forbidden("admin access required")
+			}
+			return next(c)
+		}
+	}
+}
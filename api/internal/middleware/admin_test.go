@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   any
+		wantCode int
+	}{
+		{name: "admin", userID: int64(1), wantCode: http.StatusOK},
+		{name: "not admin", userID: int64(2), wantCode: http.StatusForbidden},
+		{name: "not authenticated", userID: nil, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/admin", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.userID != nil {
+				c.Set(mw.ContextKeyUserID, tt.userID)
+			}
+
+			handler := mw.RequireAdmin([]int64{1})(func(c *echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})
+
+			err := handler(c)
+			if tt.wantCode == http.StatusOK {
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+				return
+			}
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.wantCode, appErr.Code)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/jwtutil"
+)
+
+// KeyAuthenticator resolves an API key to the ID of the user who owns it.
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (int64, error)
+}
+
+// APIKeyAuth returns middleware that authenticates requests with an API key
+// and sets userID in context.
+func APIKeyAuth(keys KeyAuthenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			key := apiKeyFromRequest(c.Request())
+			if key == "" {
+				return apperror.Unauthorized("missing api key")
+			}
+
+			userID, err := keys.Authenticate(c.Request().Context(), key)
+			if err != nil {
+				return err
+			}
+
+			c.Set(ContextKeyUserID, userID)
+			return next(c)
+		}
+	}
+}
+
+// apiKeyFromRequest reads a key the ways WakaTime clients send it: HTTP
+// Basic with the base64 key as credentials, a Bearer token, or the api_key
+// query parameter.
+func apiKeyFromRequest(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(header, "Basic "):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
+		if err != nil {
+			return ""
+		}
+		// Some clients send the key as a username with an empty password.
+		key, _, _ := strings.Cut(string(decoded), ":")
+		return key
+	case strings.HasPrefix(header, "Bearer "):
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	return r.URL.Query().Get("api_key")
+}
+
+// JWTOrAPIKeyAuth returns middleware that accepts either a session JWT or
+// an API key, for endpoints used by both the web app and scripts.
+func JWTOrAPIKeyAuth(secret string, keys KeyAuthenticator) echo.MiddlewareFunc {
+	apiKey := APIKeyAuth(keys)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withKey := apiKey(next)
+		return func(c *echo.Context) error {
+			header := c.Request().Header.Get("Authorization")
+			if tokenStr, ok := strings.CutPrefix(header, "Bearer "); ok {
+				if userID, err := jwtutil.Parse(tokenStr, secret); err == nil {
+					c.Set(ContextKeyUserID, userID)
+					return next(c)
+				}
+			}
+			return withKey(c)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/jwtutil"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+)
+
+const testAPIKey = "9b2f4c1e-3d5a-4b6c-8d7e-0f1a2b3c4d5e"
+
+type fakeKeys struct{}
+
+func (fakeKeys) Authenticate(_ context.Context, key string) (int64, error) {
+	if key != testAPIKey {
+		return 0, apperror.Unauthorized("invalid api key")
+	}
+	return 7, nil
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	basic := func(s string) string { return "Basic " + base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name     string
+		header   string
+		query    string
+		wantCode int
+	}{
+		{name: "basic", header: basic(testAPIKey), wantCode: http.StatusOK},
+		{name: "basic with empty password", header: basic(testAPIKey + ":"), wantCode: http.StatusOK},
+		{name: "bearer", header: "Bearer " + testAPIKey, wantCode: http.StatusOK},
+		{name: "query", query: "?api_key=" + testAPIKey, wantCode: http.StatusOK},
+		{name: "wrong key", header: basic("nope"), wantCode: http.StatusUnauthorized},
+		{name: "bad base64", header: "Basic !!!", wantCode: http.StatusUnauthorized},
+		{name: "missing", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users/current/heartbeats"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := mw.APIKeyAuth(fakeKeys{})(func(c *echo.Context) error {
+				userID, err := mw.GetUserID(c)
+				require.NoError(t, err)
+				assert.Equal(t, int64(7), userID)
+				return c.String(http.StatusOK, "ok")
+			})
+
+			err := handler(c)
+			if tt.wantCode == http.StatusOK {
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+				return
+			}
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.wantCode, appErr.Code)
+		})
+	}
+}
+
+func TestJWTOrAPIKeyAuth(t *testing.T) {
+	token, err := jwtutil.Generate(42, testSecret)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		header     string
+		wantUserID int64
+		wantCode   int
+	}{
+		{name: "jwt", header: "Bearer " + token, wantUserID: 42, wantCode: http.StatusOK},
+		{name: "api key", header: "Bearer " + testAPIKey, wantUserID: 7, wantCode: http.StatusOK},
+		{name: "invalid", header: "Bearer nope", wantCode: http.StatusUnauthorized},
+		{name: "missing", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/activities/batch", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handler := mw.JWTOrAPIKeyAuth(testSecret, fakeKeys{})(func(c *echo.Context) error {
+				userID, err := mw.GetUserID(c)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantUserID, userID)
+				return c.String(http.StatusOK, "ok")
+			})
+
+			err := handler(c)
+			if tt.wantCode == http.StatusOK {
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+				return
+			}
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.wantCode, appErr.Code)
+		})
+	}
+}
@@ -18,30 +18,44 @@ func NewHandler(svc *Service) *Handler {
 
 // RegisterRoutes mounts OAuth routes. All routes require authentication.
 func (h *Handler) RegisterRoutes(api *echo.Group) {
-	api.GET("/oauth/github", h.GitHubRedirect)
-	api.GET("/oauth/github/callback", h.GitHubCallback)
+	api.GET("/oauth/:provider", h.Redirect)
+	api.GET("/oauth/:provider/callback", h.Callback)
 }
 
-// GitHubRedirect returns the GitHub OAuth authorization URL.
-func (h *Handler) GitHubRedirect(c *echo.Context) error {
+// Redirect returns the provider's OAuth authorization URL.
+func (h *Handler) Redirect(c *echo.Context) error {
+	userID, ok := c.Get("userID").(int64)
+	if !ok {
+		return apperror.Unauthorized("not authenticated")
+	}
+
+	authURL, err := h.svc.AuthURL(c.Param("provider"), userID)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(http.StatusOK, map[string]string{
-		"url": h.svc.GitHubAuthURL(),
+		"url": authURL,
 	})
 }
 
-// GitHubCallback exchanges the authorization code for a token.
-func (h *Handler) GitHubCallback(c *echo.Context) error {
+// Callback validates the state and exchanges the authorization code for a token.
+func (h *Handler) Callback(c *echo.Context) error {
 	code := c.QueryParam("code")
 	if code == "" {
 		return apperror.BadRequest("missing code parameter")
 	}
+	state := c.QueryParam("state")
+	if state == "" {
+		return apperror.BadRequest("missing state parameter")
+	}
 
 	userID, ok := c.Get("userID").(int64)
 	if !ok {
 		return apperror.Unauthorized("not authenticated")
 	}
 
-	if err := h.svc.ExchangeGitHubCode(c.Request().Context(), userID, code); err != nil {
+	if err := h.svc.ExchangeCode(c.Request().Context(), c.Param("provider"), userID, code, state); err != nil {
 		return err
 	}
 
@@ -1,44 +1,181 @@
 package oauth_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/labstack/echo/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/ethanwang/devpulse/api/internal/github"
 	"github.com/ethanwang/devpulse/api/internal/oauth"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+	"github.com/ethanwang/devpulse/api/internal/wakatime"
 )
 
+const testStateSecret = "test-state-secret"
+
+func newTestService() *oauth.Service {
+	registry := provider.NewRegistry(
+		github.NewProvider(github.ProviderName, "https://github.com", nil, provider.Credentials{
+			ClientID:    "test-client-id",
+			CallbackURL: "http://localhost/callback",
+		}),
+		wakatime.NewProvider(nil, provider.Credentials{
+			ClientID:    "waka-client-id",
+			CallbackURL: "http://localhost/callback",
+		}),
+	)
+	return oauth.NewService(nil, nil, testStateSecret, registry)
+}
+
+// newContext returns a context for the route with the provider path param set.
+func newContext(e *echo.Echo, req *http.Request, rec *httptest.ResponseRecorder, name string) *echo.Context {
+	c := e.NewContext(req, rec)
+	c.SetPathValues(echo.PathValues{{Name: "provider", Value: name}})
+	return c
+}
+
 func TestGitHubRedirect_ReturnsURL(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/oauth/github", nil)
 	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	c := newContext(e, req, rec, "github")
+	c.Set("userID", int64(42))
 
-	svc := oauth.NewService(nil, oauth.GitHubConfig{
-		ClientID:    "test-client-id",
-		CallbackURL: "http://localhost/callback",
-	})
-	h := oauth.NewHandler(svc)
-	err := h.GitHubRedirect(c)
+	h := oauth.NewHandler(newTestService())
+	err := h.Redirect(c)
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Contains(t, rec.Body.String(), "github.com/login/oauth/authorize")
 	assert.Contains(t, rec.Body.String(), "test-client-id")
+	assert.Contains(t, rec.Body.String(), "state=")
+	assert.Contains(t, rec.Body.String(), "code_challenge_method=S256")
+}
+
+func TestGitHubRedirect_MissingAuth(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/oauth/github", nil)
+	rec := httptest.NewRecorder()
+	c := newContext(e, req, rec, "github")
+
+	h := oauth.NewHandler(newTestService())
+	err := h.Redirect(c)
+
+	assert.Error(t, err)
 }
 
 func TestGitHubCallback_MissingCode(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/oauth/github/callback", nil)
 	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	c := newContext(e, req, rec, "github")
+
+	h := oauth.NewHandler(nil)
+	err := h.Callback(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing code")
+}
+
+func TestGitHubCallback_MissingState(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/oauth/github/callback?code=abc", nil)
+	rec := httptest.NewRecorder()
+	c := newContext(e, req, rec, "github")
+
+	h := oauth.NewHandler(nil)
+	err := h.Callback(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing state")
+}
+
+func TestGitHubCallback_ForgedState(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/oauth/github/callback?code=abc&state=forged", nil)
+	rec := httptest.NewRecorder()
+	c := newContext(e, req, rec, "github")
+	c.Set("userID", int64(42))
+
+	h := oauth.NewHandler(newTestService())
+	err := h.Callback(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth state")
+}
+
+func TestWakaTimeRedirect_ReturnsURL(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/oauth/wakatime", nil)
+	rec := httptest.NewRecorder()
+	c := newContext(e, req, rec, "wakatime")
+	c.Set("userID", int64(42))
+
+	h := oauth.NewHandler(newTestService())
+	err := h.Redirect(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "wakatime.com/oauth/authorize")
+	assert.Contains(t, rec.Body.String(), "waka-client-id")
+	assert.Contains(t, rec.Body.String(), "read_summaries")
+	assert.Contains(t, rec.Body.String(), "state=")
+}
+
+func TestWakaTimeCallback_MissingCode(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/oauth/wakatime/callback", nil)
+	rec := httptest.NewRecorder()
+	c := newContext(e, req, rec, "wakatime")
 
 	h := oauth.NewHandler(nil)
-	err := h.GitHubCallback(c)
+	err := h.Callback(c)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "missing code")
 }
+
+func TestRedirect_UnknownProvider(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/oauth/unknown", nil)
+	rec := httptest.NewRecorder()
+	c := newContext(e, req, rec, "unknown")
+	c.Set("userID", int64(42))
+
+	h := oauth.NewHandler(newTestService())
+	err := h.Redirect(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown provider")
+}
+
+func TestCallback_StateForOtherProvider(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/oauth/wakatime", nil)
+	rec := httptest.NewRecorder()
+	c := newContext(e, req, rec, "wakatime")
+	c.Set("userID", int64(42))
+
+	h := oauth.NewHandler(newTestService())
+	require.NoError(t, h.Redirect(c))
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	authURL, err := url.Parse(body["url"])
+	require.NoError(t, err)
+
+	// A WakaTime state must not complete the GitHub flow
+	req = httptest.NewRequest(http.MethodGet, "/api/oauth/github/callback?code=abc&state="+url.QueryEscape(authURL.Query().Get("state")), nil)
+	c = newContext(e, req, httptest.NewRecorder(), "github")
+	c.Set("userID", int64(42))
+
+	err = h.Callback(c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth state")
+}
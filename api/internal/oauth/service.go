@@ -2,94 +2,98 @@ package oauth
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 
 	dbgen "github.com/ethanwang/devpulse/api/db/generated"
 	"github.com/ethanwang/devpulse/api/internal/apperror"
-	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/ethanwang/devpulse/api/internal/provider"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
 )
 
-type GitHubConfig struct {
-	ClientID     string
-	ClientSecret string
-	CallbackURL  string
-}
-
 type Service struct {
-	q      *dbgen.Queries
-	github GitHubConfig
-}
-
-func NewService(q *dbgen.Queries, github GitHubConfig) *Service {
-	return &Service{q: q, github: github}
+	q           *dbgen.Queries
+	keyring     *tokencrypt.Keyring
+	stateSecret string
+	providers   *provider.Registry
 }
 
-// GitHubAuthURL returns the URL to redirect users to for GitHub authorization.
-func (s *Service) GitHubAuthURL() string {
-	return fmt.Sprintf(
-		"https://github.com/login/oauth/authorize?client_id=%s&redirect_uri=%s&scope=read:user,repo",
-		url.QueryEscape(s.github.ClientID),
-		url.QueryEscape(s.github.CallbackURL),
-	)
+// NewService creates the OAuth service. stateSecret signs the OAuth state
+// parameter and derives PKCE verifiers.
+func NewService(q *dbgen.Queries, keyring *tokencrypt.Keyring, stateSecret string, providers *provider.Registry) *Service {
+	return &Service{q: q, keyring: keyring, stateSecret: stateSecret, providers: providers}
 }
 
-// ExchangeGitHubCode exchanges an authorization code for an access token
-// and stores it in the database.
-func (s *Service) ExchangeGitHubCode(ctx context.Context, userID int64, code string) error {
-	tokenResp, err := exchangeCodeForToken(s.github, code)
+// AuthURL returns the URL to redirect users to for authorizing the named
+// provider. The URL carries a state bound to userID and, for providers that
+// support it, a PKCE S256 challenge.
+func (s *Service) AuthURL(name string, userID int64) (string, error) {
+	p, err := s.provider(name)
 	if err != nil {
-		return apperror.Internalf("github token exchange: %w", err)
-	}
-	if tokenResp.AccessToken == "" {
-		return apperror.BadRequest("github authorization failed")
+		return "", err
 	}
 
-	// TODO: AES encrypt access_token before storing
-	_, err = s.q.UpsertDataSource(ctx, dbgen.UpsertDataSourceParams{
-		UserID:       userID,
-		Provider:     "github",
-		AccessToken:  []byte(tokenResp.AccessToken),
-		RefreshToken: nil,
-		ExpiresAt:    pgtype.Timestamptz{},
-	})
+	state, err := signState(s.stateSecret, userID, name, time.Now())
 	if err != nil {
-		return apperror.Internalf("save github token: %w", err)
+		return "", apperror.Internalf("sign oauth state: %w", err)
 	}
-
-	return nil
+	return p.OAuthConfig().AuthURL(state, pkceChallenge(pkceVerifier(s.stateSecret, state))), nil
 }
 
-type githubTokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	Scope       string `json:"scope"`
-}
+// ExchangeCode validates the state, exchanges an authorization code for
+// a token and stores it as the user's data source for the provider.
+func (s *Service) ExchangeCode(ctx context.Context, name string, userID int64, code, state string) error {
+	p, err := s.provider(name)
+	if err != nil {
+		return err
+	}
+	if err := verifyState(s.stateSecret, state, userID, name); err != nil {
+		return apperror.BadRequest("invalid or expired oauth state")
+	}
 
-func exchangeCodeForToken(cfg GitHubConfig, code string) (*githubTokenResponse, error) {
-	body := url.Values{
-		"client_id":     {cfg.ClientID},
-		"client_secret": {cfg.ClientSecret},
-		"code":          {code},
+	var verifier string
+	if p.OAuthConfig().PKCE {
+		verifier = pkceVerifier(s.stateSecret, state)
 	}
-	req, err := http.NewRequest(http.MethodPost, "https://github.com/login/oauth/access_token", nil)
+	token, err := p.ExchangeCode(ctx, code, verifier)
+	if err != nil {
+		return apperror.Internalf("%s token exchange: %w", name, err)
+	}
+	if token.AccessToken == "" {
+		return apperror.BadRequest(name + " authorization failed")
+	}
+
+	accessToken, err := s.keyring.Encrypt([]byte(token.AccessToken))
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return apperror.Internalf("encrypt %s token: %w", name, err)
+	}
+	var refreshToken []byte
+	if token.RefreshToken != "" {
+		refreshToken, err = s.keyring.Encrypt([]byte(token.RefreshToken))
+		if err != nil {
+			return apperror.Internalf("encrypt %s token: %w", name, err)
+		}
 	}
-	req.URL.RawQuery = body.Encode()
-	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	_, err = s.q.UpsertDataSource(ctx, dbgen.UpsertDataSourceParams{
+		UserID:       userID,
+		Provider:     name,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    pgtype.Timestamptz{Time: token.ExpiresAt, Valid: !token.ExpiresAt.IsZero()},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("post to github: %w", err)
+		return apperror.Internalf("save %s token: %w", name, err)
 	}
-	defer resp.Body.Close()
 
-	var tokenResp githubTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	return nil
+}
+
+func (s *Service) provider(name string) (provider.Provider, error) {
+	p, ok := s.providers.Get(name)
+	if !ok {
+		return nil, apperror.NotFound("unknown provider: " + name)
 	}
-	return &tokenResp, nil
+	return p, nil
 }
@@ -0,0 +1,84 @@
+package oauth
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/jwtutil"
+)
+
+const testSecret = "test-state-secret"
+
+func TestState_RoundTrip(t *testing.T) {
+	state, err := signState(testSecret, 42, "github", time.Now())
+	require.NoError(t, err)
+
+	assert.NoError(t, verifyState(testSecret, state, 42, "github"))
+}
+
+func TestState_RejectsOtherUser(t *testing.T) {
+	state, err := signState(testSecret, 42, "github", time.Now())
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, verifyState(testSecret, state, 43, "github"), errInvalidState)
+}
+
+func TestState_RejectsOtherProvider(t *testing.T) {
+	state, err := signState(testSecret, 42, "wakatime", time.Now())
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, verifyState(testSecret, state, 42, "github"), errInvalidState)
+}
+
+func TestState_RejectsExpired(t *testing.T) {
+	state, err := signState(testSecret, 42, "github", time.Now().Add(-stateTTL-time.Minute))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, verifyState(testSecret, state, 42, "github"), errInvalidState)
+}
+
+func TestState_RejectsWrongSecret(t *testing.T) {
+	state, err := signState("other-secret", 42, "github", time.Now())
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, verifyState(testSecret, state, 42, "github"), errInvalidState)
+}
+
+func TestState_NotASessionToken(t *testing.T) {
+	// A session token signed with the same secret isn't a valid state
+	session, err := jwtutil.Generate(42, testSecret)
+	require.NoError(t, err)
+	assert.ErrorIs(t, verifyState(testSecret, session, 42, ""), errInvalidState)
+
+	// and a state doesn't authenticate as a session
+	state, err := signState(testSecret, 42, "github", time.Now())
+	require.NoError(t, err)
+	_, err = jwtutil.Parse(state, testSecret)
+	assert.Error(t, err)
+}
+
+func TestState_IsUnique(t *testing.T) {
+	now := time.Now()
+	a, err := signState(testSecret, 42, "github", now)
+	require.NoError(t, err)
+	b, err := signState(testSecret, 42, "github", now)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+}
+
+func TestPKCE(t *testing.T) {
+	verifier := pkceVerifier(testSecret, "some-state")
+
+	// RFC 7636: 43-128 chars from the unreserved set
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`), verifier)
+	assert.Equal(t, verifier, pkceVerifier(testSecret, "some-state"))
+	assert.NotEqual(t, verifier, pkceVerifier(testSecret, "other-state"))
+
+	// RFC 7636 Appendix B test vector
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", pkceChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Credentials identify this deployment's OAuth app with a provider.
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+	CallbackURL  string
+}
+
+// OAuthConfig describes a provider's OAuth 2.0 authorization code flow.
+type OAuthConfig struct {
+	Credentials
+	AuthorizeURL string
+	TokenURL     string
+	Scopes       []string
+	// PKCE adds an S256 code challenge to the flow. Only set it for
+	// providers that support PKCE.
+	PKCE bool
+	// BasicAuth sends the client credentials to the token endpoint as HTTP
+	// Basic auth instead of in the form body.
+	BasicAuth bool
+}
+
+// AuthURL returns the URL to redirect the user to. challenge is ignored
+// unless PKCE is set.
+func (c OAuthConfig) AuthURL(state, challenge string) string {
+	query := url.Values{
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {c.CallbackURL},
+		"response_type": {"code"},
+		"scope":         {strings.Join(c.Scopes, " ")},
+		"state":         {state},
+	}
+	if c.PKCE {
+		query.Set("code_challenge", challenge)
+		query.Set("code_challenge_method", "S256")
+	}
+	return c.AuthorizeURL + "?" + query.Encode()
+}
+
+type tokenResponse struct {
+	AccessToken  string  `json:"access_token"`
+	RefreshToken string  `json:"refresh_token"`
+	ExpiresIn    float64 `json:"expires_in"`
+}
+
+// ExchangeCode performs the standard token request for cfg. Providers whose
+// token endpoint follows RFC 6749 can implement Provider.ExchangeCode with
+// it. A rejected code yields a token with an empty AccessToken.
+func ExchangeCode(ctx context.Context, cfg OAuthConfig, code, verifier string) (*Token, error) {
+	body := url.Values{
+		"redirect_uri": {cfg.CallbackURL},
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+	}
+	if verifier != "" {
+		body.Set("code_verifier", verifier)
+	}
+	return requestToken(ctx, cfg, body)
+}
+
+// RefreshToken performs the standard refresh_token grant for cfg. A
+// rejected refresh token yields a token with an empty AccessToken.
+func RefreshToken(ctx context.Context, cfg OAuthConfig, refreshToken string) (*Token, error) {
+	body := url.Values{
+		"redirect_uri":  {cfg.CallbackURL},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	return requestToken(ctx, cfg, body)
+}
+
+func requestToken(ctx context.Context, cfg OAuthConfig, body url.Values) (*Token, error) {
+	if !cfg.BasicAuth {
+		body.Set("client_id", cfg.ClientID)
+		body.Set("client_secret", cfg.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(body.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if cfg.BasicAuth {
+		req.SetBasicAuth(cfg.ClientID, cfg.ClientSecret)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("post token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	token := &Token{AccessToken: tokenResp.AccessToken, RefreshToken: tokenResp.RefreshToken}
+	if tokenResp.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+// Provider is an external data source that users connect with OAuth and
+// that is synced by polling.
+type Provider interface {
+	// Name is the provider key stored in data_sources.provider and
+	// activities.source, and used in the /oauth/:provider routes.
+	Name() string
+
+	// OAuthConfig describes the provider's authorization code flow.
+	OAuthConfig() OAuthConfig
+
+	// ExchangeCode trades an authorization code for a token. verifier is
+	// empty unless OAuthConfig().PKCE is set.
+	ExchangeCode(ctx context.Context, code, verifier string) (*Token, error)
+
+	// Fetch returns the user's recent activity, already mapped to activity
+	// types. cursor is the value returned by the previous fetch, if any.
+	Fetch(ctx context.Context, token, cursor string) (*FetchResult, error)
+
+	// SupportedTypes lists the activity types Fetch can produce.
+	SupportedTypes() []string
+}
+
+// AfterSyncer is implemented by providers that schedule follow-up work once
+// a user's sync has stored its activities.
+type AfterSyncer interface {
+	AfterSync(ctx context.Context, ds dbgen.DataSource) error
+}
+
+// Refresher is implemented by providers whose access tokens expire and can
+// be renewed with the stored refresh token.
+type Refresher interface {
+	RefreshToken(ctx context.Context, refreshToken string) (*Token, error)
+}
+
+// ErrTokenRejected is wrapped by VerifyToken errors when the provider
+// refused the token itself, as opposed to failing to check it.
+var ErrTokenRejected = errors.New("token rejected")
+
+// TokenVerifier is implemented by providers that also accept a personal
+// access token in place of the OAuth flow.
+type TokenVerifier interface {
+	// VerifyToken checks that the token is usable before it's stored. An
+	// invalid token yields an error wrapping ErrTokenRejected.
+	VerifyToken(ctx context.Context, token string) error
+}
+
+// RateLimited is implemented by errors a provider returns when the user's
+// quota is exhausted. The sync is postponed instead of retried.
+type RateLimited interface {
+	error
+	Wait(now time.Time) time.Duration
+}
+
+// Token is an OAuth token returned by a code exchange.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresAt is zero if the token doesn't expire.
+	ExpiresAt time.Time
+}
+
+// Activity is a provider event mapped to an activities row.
+type Activity struct {
+	Type       string
+	OccurredAt time.Time
+	ExternalID string
+	Payload    json.RawMessage
+	// LegacyExternalID is the key the provider stored this activity under
+	// before it switched dedup keys. A row still stored under it is moved
+	// to ExternalID rather than duplicated.
+	LegacyExternalID string
+	// Mutable marks rows whose payload keeps changing after they're first
+	// seen, like a day's coding total, so later syncs overwrite them.
+	Mutable bool
+}
+
+// InsertParams returns the row to insert for the activity.
+func (a Activity) InsertParams(userID int64, source string) dbgen.InsertActivityParams {
+	return dbgen.InsertActivityParams{
+		UserID:     userID,
+		Source:     source,
+		Type:       a.Type,
+		Payload:    a.Payload,
+		OccurredAt: pgtype.Timestamptz{Time: a.OccurredAt, Valid: true},
+		ExternalID: pgtype.Text{String: a.ExternalID, Valid: a.ExternalID != ""},
+	}
+}
+
+// FetchResult is the outcome of a Fetch call.
+type FetchResult struct {
+	Activities []Activity
+	// Cursor is stored and passed to the next Fetch, e.g. an ETag.
+	Cursor string
+	// PollInterval is the minimum wait before the next fetch.
+	PollInterval time.Duration
+	// NotModified is set when nothing changed since cursor.
+	NotModified bool
+}
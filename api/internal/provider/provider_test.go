@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+type stubProvider struct {
+	name  string
+	types []string
+}
+
+func (p stubProvider) Name() string             { return p.name }
+func (p stubProvider) OAuthConfig() OAuthConfig { return OAuthConfig{} }
+func (p stubProvider) SupportedTypes() []string { return p.types }
+
+func (p stubProvider) ExchangeCode(ctx context.Context, code, verifier string) (*Token, error) {
+	return nil, nil
+}
+
+func (p stubProvider) Fetch(ctx context.Context, token, cursor string) (*FetchResult, error) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(
+		stubProvider{name: "wakatime", types: []string{"coding"}},
+		stubProvider{name: "github", types: []string{"push", "create"}},
+		stubProvider{name: "bitbucket", types: []string{"push"}},
+	)
+
+	assert.Equal(t, []string{"bitbucket", "github", "wakatime"}, r.Names())
+	assert.Equal(t, []string{"coding", "create", "push"}, r.ActivityTypes())
+
+	p, ok := r.Get("github")
+	require.True(t, ok)
+	assert.Equal(t, "github", p.Name())
+
+	_, ok = r.Get("gitlab")
+	assert.False(t, ok)
+}
+
+func TestAuthURL(t *testing.T) {
+	cfg := OAuthConfig{
+		Credentials:  Credentials{ClientID: "client-id", CallbackURL: "http://localhost/callback"},
+		AuthorizeURL: "https://example.com/oauth/authorize",
+		Scopes:       []string{"read:user", "repo"},
+	}
+
+	u, err := url.Parse(cfg.AuthURL("the-state", "the-challenge"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", u.Host)
+	q := u.Query()
+	assert.Equal(t, "client-id", q.Get("client_id"))
+	assert.Equal(t, "http://localhost/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "read:user repo", q.Get("scope"))
+	assert.Equal(t, "the-state", q.Get("state"))
+	assert.Empty(t, q.Get("code_challenge"))
+
+	cfg.PKCE = true
+	u, err = url.Parse(cfg.AuthURL("the-state", "the-challenge"))
+	require.NoError(t, err)
+	assert.Equal(t, "the-challenge", u.Query().Get("code_challenge"))
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+}
+
+func TestExchangeCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "the-code", r.PostForm.Get("code"))
+		assert.Equal(t, "the-verifier", r.PostForm.Get("code_verifier"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"at","refresh_token":"rt","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	cfg := OAuthConfig{Credentials: Credentials{ClientID: "id", ClientSecret: "secret"}, TokenURL: srv.URL}
+	token, err := ExchangeCode(context.Background(), cfg, "the-code", "the-verifier")
+
+	require.NoError(t, err)
+	assert.Equal(t, "at", token.AccessToken)
+	assert.Equal(t, "rt", token.RefreshToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+}
+
+func TestExchangeCode_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":"bad_verification_code"}`))
+	}))
+	defer srv.Close()
+
+	token, err := ExchangeCode(context.Background(), OAuthConfig{TokenURL: srv.URL}, "the-code", "")
+
+	require.NoError(t, err)
+	assert.Empty(t, token.AccessToken)
+	assert.True(t, token.ExpiresAt.IsZero())
+}
+
+func TestActivity_InsertParams(t *testing.T) {
+	now := time.Now()
+	act := Activity{Type: "push", OccurredAt: now, ExternalID: "push:user/repo:abc", Payload: []byte(`{}`)}
+
+	params := act.InsertParams(42, "github")
+	assert.Equal(t, int64(42), params.UserID)
+	assert.Equal(t, "github", params.Source)
+	assert.Equal(t, "push", params.Type)
+	assert.Equal(t, now, params.OccurredAt.Time)
+	assert.Equal(t, "push:user/repo:abc", params.ExternalID.String)
+	assert.True(t, params.ExternalID.Valid)
+}
+
+// failingDB fails inserts of the activity with the given external ID.
+type failingDB struct {
+	failID string
+}
+
+func (db failingDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if id, ok := args[5].(pgtype.Text); ok && id.String == db.failID {
+		return pgconn.CommandTag{}, errors.New("connection reset")
+	}
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (db failingDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (db failingDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return nil
+}
+
+func TestStoreAll_ReportsFailures(t *testing.T) {
+	now := time.Now()
+	acts := []Activity{
+		{Type: "push", OccurredAt: now, ExternalID: "a", Payload: []byte(`{}`)},
+		{Type: "push", OccurredAt: now.Add(time.Hour), ExternalID: "b", Payload: []byte(`{}`)},
+		{Type: "push", OccurredAt: now.Add(2 * time.Hour), ExternalID: "c", Payload: []byte(`{}`)},
+	}
+	w := NewSyncUserWorker(dbgen.New(failingDB{failID: "b"}), nil, nil)
+
+	touched, err := w.storeAll(context.Background(), 1, "github", acts)
+
+	// The sync must fail so the cursor isn't advanced past "b",
+	// but the others are still stored.
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "store activity b")
+	assert.Equal(t, []time.Time{now, now.Add(2 * time.Hour)}, touched)
+}
+
+func TestSyncArgsKind(t *testing.T) {
+	args := SyncArgs{}
+	assert.Equal(t, "provider_sync", args.Kind())
+}
+
+func TestSyncUserArgs(t *testing.T) {
+	args := SyncUserArgs{Provider: "github", UserID: 42}
+	assert.Equal(t, "provider_sync_user", args.Kind())
+
+	opts := args.InsertOpts()
+	assert.True(t, opts.UniqueOpts.ByArgs)
+	assert.Contains(t, opts.UniqueOpts.ByState, rivertype.JobStateRunning)
+	// A completed sync must not block the next period's job
+	assert.NotContains(t, opts.UniqueOpts.ByState, rivertype.JobStateCompleted)
+}
+
+func TestRefreshToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "old-refresh", r.PostForm.Get("refresh_token"))
+		w.Write([]byte(`{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`))
+	}))
+	defer srv.Close()
+
+	token, err := RefreshToken(context.Background(), OAuthConfig{TokenURL: srv.URL}, "old-refresh")
+
+	require.NoError(t, err)
+	assert.Equal(t, "new-access", token.AccessToken)
+	assert.Equal(t, "new-refresh", token.RefreshToken)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), token.ExpiresAt, time.Minute)
+}
+
+func TestExchangeCode_BasicAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "id", id)
+		assert.Equal(t, "secret", secret)
+		require.NoError(t, r.ParseForm())
+		assert.Empty(t, r.PostForm.Get("client_secret"))
+		w.Write([]byte(`{"access_token":"at"}`))
+	}))
+	defer srv.Close()
+
+	cfg := OAuthConfig{Credentials: Credentials{ClientID: "id", ClientSecret: "secret"}, TokenURL: srv.URL, BasicAuth: true}
+	token, err := ExchangeCode(context.Background(), cfg, "the-code", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "at", token.AccessToken)
+}
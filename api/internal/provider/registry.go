@@ -0,0 +1,51 @@
+package provider
+
+import "sort"
+
+// Registry holds the providers enabled in this deployment, keyed by name.
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry creates a registry of the given providers. A later provider
+// replaces an earlier one with the same name.
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// Get returns the provider with the given name.
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names returns the registered provider names in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActivityTypes returns the activity types the registered providers can
+// produce, deduplicated and sorted.
+func (r *Registry) ActivityTypes() []string {
+	seen := make(map[string]bool)
+	var types []string
+	for _, p := range r.providers {
+		for _, t := range p.SupportedTypes() {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
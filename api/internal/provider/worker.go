@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/pullrequest"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+	"github.com/ethanwang/devpulse/api/internal/tokencrypt"
+)
+
+// refreshMargin renews tokens this long before they expire, so a token
+// doesn't lapse in the middle of a fetch.
+const refreshMargin = 5 * time.Minute
+
+// syncUserTimeout bounds a single user's sync so a hung request can't hold
+// a worker slot indefinitely.
+const syncUserTimeout = 2 * time.Minute
+
+// SyncArgs are the arguments for the periodic sync job. It only fans out
+// one SyncUserArgs job per connected data source.
+type SyncArgs struct{}
+
+func (SyncArgs) Kind() string { return "provider_sync" }
+
+// SyncWorker enqueues a per-user sync job for every data source of every
+// registered provider.
+type SyncWorker struct {
+	riverlib.WorkerDefaults[SyncArgs]
+	q        *dbgen.Queries
+	registry *Registry
+}
+
+func NewSyncWorker(q *dbgen.Queries, registry *Registry) *SyncWorker {
+	return &SyncWorker{q: q, registry: registry}
+}
+
+func (w *SyncWorker) Work(ctx context.Context, job *riverlib.Job[SyncArgs]) error {
+	var params []riverlib.InsertManyParams
+	for _, name := range w.registry.Names() {
+		sources, err := w.q.ListDataSourcesByProvider(ctx, name)
+		if err != nil {
+			return err
+		}
+		for _, src := range sources {
+			params = append(params, riverlib.InsertManyParams{Args: SyncUserArgs{Provider: name, UserID: src.UserID}})
+		}
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	if _, err := client.InsertMany(ctx, params); err != nil {
+		return fmt.Errorf("enqueue user syncs: %w", err)
+	}
+
+	slog.Info("provider sync enqueued", "jobs", len(params))
+	return nil
+}
+
+// SyncUserArgs are the arguments for syncing one user's data source.
+type SyncUserArgs struct {
+	Provider string `json:"provider"`
+	UserID   int64  `json:"user_id"`
+}
+
+func (SyncUserArgs) Kind() string { return "provider_sync_user" }
+
+// InsertOpts keeps at most one pending or running sync per data source.
+// Completed jobs are excluded from the unique states so the next period
+// can enqueue a fresh one.
+func (SyncUserArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		MaxAttempts: 5,
+		UniqueOpts:  riversetup.UniqueUnfinished(0),
+	}
+}
+
+// SyncUserWorker fetches one data source and stores its activities.
+type SyncUserWorker struct {
+	riverlib.WorkerDefaults[SyncUserArgs]
+	q        *dbgen.Queries
+	registry *Registry
+	keyring  *tokencrypt.Keyring
+}
+
+func NewSyncUserWorker(q *dbgen.Queries, registry *Registry, keyring *tokencrypt.Keyring) *SyncUserWorker {
+	return &SyncUserWorker{q: q, registry: registry, keyring: keyring}
+}
+
+func (w *SyncUserWorker) Timeout(job *riverlib.Job[SyncUserArgs]) time.Duration {
+	return syncUserTimeout
+}
+
+func (w *SyncUserWorker) Work(ctx context.Context, job *riverlib.Job[SyncUserArgs]) error {
+	userID, name := job.Args.UserID, job.Args.Provider
+
+	p, ok := w.registry.Get(name)
+	if !ok {
+		// Provider disabled since the job was enqueued.
+		return riverlib.JobCancel(fmt.Errorf("provider %q not registered", name))
+	}
+
+	ds, err := w.q.GetDataSourceByUserAndProvider(ctx, dbgen.GetDataSourceByUserAndProviderParams{
+		UserID:   userID,
+		Provider: name,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Disconnected since the job was enqueued; retrying won't help.
+		return riverlib.JobCancel(fmt.Errorf("%s data source for user %d not found", name, userID))
+	}
+	if err != nil {
+		return err
+	}
+
+	// Respect the poll interval or rate limit reset from the last run.
+	if ds.NextSyncAt.Valid && time.Now().Before(ds.NextSyncAt.Time) {
+		return riverlib.JobSnooze(time.Until(ds.NextSyncAt.Time))
+	}
+
+	token, err := w.accessToken(ctx, p, ds)
+	if err != nil {
+		return err
+	}
+
+	result, err := p.Fetch(ctx, token, ds.SyncEtag.String)
+	var rateLimited RateLimited
+	if errors.As(err, &rateLimited) {
+		// Snoozing doesn't use up an attempt, unlike returning the error.
+		wait := rateLimited.Wait(time.Now())
+		w.saveSyncState(ctx, ds, ds.SyncEtag.String, time.Now().Add(wait))
+		slog.Warn("provider rate limited", "provider", name, "user_id", userID, "wait", wait, "error", err)
+		return riverlib.JobSnooze(wait)
+	}
+	if err != nil {
+		return err
+	}
+
+	nextSync := time.Now().Add(result.PollInterval)
+	if result.NotModified {
+		w.saveSyncState(ctx, ds, result.Cursor, nextSync)
+		slog.Info("provider sync not modified", "provider", name, "user_id", userID)
+		return w.afterSync(ctx, p, ds)
+	}
+
+	touched, storeErr := w.storeAll(ctx, userID, name, result.Activities)
+
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	if err := summary.Reaggregate(ctx, w.q, client, userID, touched); err != nil {
+		return err
+	}
+	if len(touched) > 0 {
+		if _, err := client.Insert(ctx, pullrequest.ProjectArgs{UserID: userID}, nil); err != nil {
+			return err
+		}
+	}
+
+	// Saved only after storing, so an interrupted run or a failed store
+	// doesn't leave a cursor that would hide the activities it never
+	// stored. The retry fetches them again; stored ones are deduplicated.
+	if storeErr != nil {
+		return storeErr
+	}
+	w.saveSyncState(ctx, ds, result.Cursor, nextSync)
+	slog.Info("provider sync complete", "provider", name, "user_id", userID, "activities", len(result.Activities), "changed", len(touched))
+	return w.afterSync(ctx, p, ds)
+}
+
+// accessToken decrypts the stored token, first renewing it if it's about to
+// expire and the provider supports refreshing.
+func (w *SyncUserWorker) accessToken(ctx context.Context, p Provider, ds dbgen.DataSource) (string, error) {
+	refresher, ok := p.(Refresher)
+	if !ok || ds.RefreshToken == nil || !ds.ExpiresAt.Valid || time.Until(ds.ExpiresAt.Time) > refreshMargin {
+		token, err := w.keyring.Decrypt(ds.AccessToken)
+		return string(token), err
+	}
+
+	refreshToken, err := w.keyring.Decrypt(ds.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	token, err := refresher.RefreshToken(ctx, string(refreshToken))
+	if err != nil {
+		return "", fmt.Errorf("refresh %s token: %w", ds.Provider, err)
+	}
+	if token.AccessToken == "" {
+		// The grant was revoked; the user has to reconnect.
+		return "", riverlib.JobCancel(fmt.Errorf("%s refresh token rejected for user %d", ds.Provider, ds.UserID))
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = string(refreshToken)
+	}
+
+	accessToken, err := w.keyring.Encrypt([]byte(token.AccessToken))
+	if err != nil {
+		return "", err
+	}
+	sealedRefresh, err := w.keyring.Encrypt([]byte(token.RefreshToken))
+	if err != nil {
+		return "", err
+	}
+	_, err = w.q.UpsertDataSource(ctx, dbgen.UpsertDataSourceParams{
+		UserID:       ds.UserID,
+		Provider:     ds.Provider,
+		AccessToken:  accessToken,
+		RefreshToken: sealedRefresh,
+		ExpiresAt:    pgtype.Timestamptz{Time: token.ExpiresAt, Valid: !token.ExpiresAt.IsZero()},
+	})
+	if err != nil {
+		return "", fmt.Errorf("save refreshed %s token: %w", ds.Provider, err)
+	}
+	return token.AccessToken, nil
+}
+
+// storeAll stores the activities and returns the times of those that were
+// inserted or changed. It keeps going past failures and returns them all.
+func (w *SyncUserWorker) storeAll(ctx context.Context, userID int64, source string, acts []Activity) ([]time.Time, error) {
+	var touched []time.Time
+	var errs []error
+	for _, act := range acts {
+		n, err := w.store(ctx, userID, source, act)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("store activity %s: %w", act.ExternalID, err))
+			continue
+		}
+		if n > 0 {
+			touched = append(touched, act.OccurredAt)
+		}
+	}
+	return touched, errors.Join(errs...)
+}
+
+// store inserts the activity, or updates it in place if it's mutable. A
+// row still stored under the activity's legacy key is moved to its current
+// key instead.
+func (w *SyncUserWorker) store(ctx context.Context, userID int64, source string, act Activity) (int64, error) {
+	params := act.InsertParams(userID, source)
+	if act.LegacyExternalID != "" {
+		n, err := w.q.RekeyActivity(ctx, dbgen.RekeyActivityParams{
+			UserID:       userID,
+			Source:       source,
+			ExternalID:   pgtype.Text{String: act.LegacyExternalID, Valid: true},
+			ExternalID_2: params.ExternalID,
+			Payload:      params.Payload,
+		})
+		if err != nil || n > 0 {
+			return n, err
+		}
+	}
+	if act.Mutable {
+		return w.q.UpsertActivity(ctx, dbgen.UpsertActivityParams(params))
+	}
+	return w.q.InsertActivity(ctx, params)
+}
+
+func (w *SyncUserWorker) afterSync(ctx context.Context, p Provider, ds dbgen.DataSource) error {
+	if hook, ok := p.(AfterSyncer); ok {
+		return hook.AfterSync(ctx, ds)
+	}
+	return nil
+}
+
+// saveSyncState stores the cursor and earliest next poll time. Failures are
+// only logged; the next sync just fetches without a cursor.
+func (w *SyncUserWorker) saveSyncState(ctx context.Context, ds dbgen.DataSource, cursor string, next time.Time) {
+	err := w.q.UpdateSyncState(ctx, dbgen.UpdateSyncStateParams{
+		UserID:     ds.UserID,
+		Provider:   ds.Provider,
+		SyncEtag:   pgtype.Text{String: cursor, Valid: cursor != ""},
+		NextSyncAt: pgtype.Timestamptz{Time: next, Valid: true},
+	})
+	if err != nil {
+		slog.Error("save sync state failed", "provider", ds.Provider, "user_id", ds.UserID, "error", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Package pullrequest keeps the pull_requests table, which tracks each pull
+// request a user opened from open to merge or close, in step with their
+// pull_request activities.
+package pullrequest
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+// Pull request states stored in pull_requests.state.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+	StateMerged = "merged"
+)
+
+// Lifecycle is what a user's activities tell about one pull request.
+// Review timestamps aren't part of it, since reviews of the user's pull
+// requests are other people's activity; providers that can look them up
+// fill them in separately.
+type Lifecycle struct {
+	Source   string
+	Repo     string
+	Number   int
+	Title    string
+	State    string
+	StateAt  time.Time
+	OpenedAt time.Time
+	MergedAt time.Time
+	ClosedAt time.Time
+}
+
+// UpsertParams returns the parameters that store l for the user. Zero
+// times are stored as NULL.
+func (l Lifecycle) UpsertParams(userID int64) dbgen.UpsertPullRequestParams {
+	return dbgen.UpsertPullRequestParams{
+		UserID:   userID,
+		Source:   l.Source,
+		Repo:     l.Repo,
+		Number:   int32(l.Number),
+		Title:    l.Title,
+		State:    l.State,
+		StateAt:  Timestamptz(l.StateAt),
+		OpenedAt: Timestamptz(l.OpenedAt),
+		MergedAt: Timestamptz(l.MergedAt),
+		ClosedAt: Timestamptz(l.ClosedAt),
+	}
+}
+
+// Timestamptz converts t to a timestamptz parameter, NULL if t is zero.
+func Timestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+// storedPullRequest is the activity payload shared by every provider's
+// pull_request activities.
+type storedPullRequest struct {
+	Repo    string `json:"repo"`
+	Payload struct {
+		Action      string `json:"action"`
+		Number      int    `json:"number"`
+		PullRequest *struct {
+			Number int    `json:"number"`
+			Title  string `json:"title"`
+			Merged bool   `json:"merged"`
+		} `json:"pull_request"`
+	} `json:"payload"`
+}
+
+type key struct {
+	source, repo string
+	number       int
+}
+
+// Fold replays pull_request activities, oldest first, into one lifecycle
+// per pull request. Only pull requests whose opening is among the
+// activities are returned: without it there's no start to measure from.
+// Actions other than opened, closed and reopened (edits, labels, new
+// commits) only update the title.
+func Fold(rows []dbgen.ListPullRequestActivitiesRow) []Lifecycle {
+	prs := make(map[key]*Lifecycle)
+	for _, row := range rows {
+		var p storedPullRequest
+		if err := json.Unmarshal(row.Payload, &p); err != nil {
+			continue
+		}
+		number := p.Payload.Number
+		if number == 0 && p.Payload.PullRequest != nil {
+			number = p.Payload.PullRequest.Number
+		}
+		if p.Repo == "" || number == 0 {
+			continue
+		}
+
+		k := key{row.Source, p.Repo, number}
+		l, ok := prs[k]
+		if !ok {
+			l = &Lifecycle{Source: row.Source, Repo: p.Repo, Number: number}
+			prs[k] = l
+		}
+		at := row.OccurredAt.Time
+		if p.Payload.PullRequest != nil && p.Payload.PullRequest.Title != "" {
+			l.Title = p.Payload.PullRequest.Title
+		}
+
+		switch p.Payload.Action {
+		case "opened", "":
+			if l.OpenedAt.IsZero() || at.Before(l.OpenedAt) {
+				l.OpenedAt = at
+			}
+			if l.State == "" {
+				l.State, l.StateAt = StateOpen, at
+			}
+		case "reopened":
+			l.State, l.StateAt = StateOpen, at
+			l.ClosedAt = time.Time{}
+		case "closed":
+			l.State, l.StateAt = StateClosed, at
+			l.ClosedAt = at
+			if p.Payload.PullRequest != nil && p.Payload.PullRequest.Merged {
+				l.State = StateMerged
+				if l.MergedAt.IsZero() {
+					l.MergedAt = at
+				}
+			}
+		}
+	}
+
+	lifecycles := make([]Lifecycle, 0, len(prs))
+	for _, l := range prs {
+		if l.OpenedAt.IsZero() {
+			continue
+		}
+		lifecycles = append(lifecycles, *l)
+	}
+	sort.Slice(lifecycles, func(i, j int) bool {
+		return lifecycles[i].OpenedAt.Before(lifecycles[j].OpenedAt)
+	})
+	return lifecycles
+}
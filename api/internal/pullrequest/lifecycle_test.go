@@ -0,0 +1,104 @@
+package pullrequest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+func prRow(source, payload string, at time.Time) dbgen.ListPullRequestActivitiesRow {
+	return dbgen.ListPullRequestActivitiesRow{
+		Source:     source,
+		Payload:    []byte(payload),
+		OccurredAt: pgtype.Timestamptz{Time: at, Valid: true},
+	}
+}
+
+func TestFold(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	rows := []dbgen.ListPullRequestActivitiesRow{
+		prRow("github", `{"repo":"a/b","payload":{"action":"opened","number":1,"pull_request":{"number":1,"title":"Add x","state":"open"}}}`, t0),
+		prRow("github", `{"repo":"a/b","payload":{"action":"opened","number":2,"pull_request":{"number":2,"title":"Try y","state":"open"}}}`, t0.Add(time.Hour)),
+		prRow("github", `{"repo":"a/b","payload":{"action":"edited","number":1,"pull_request":{"number":1,"title":"Add x and z","state":"open"}}}`, t0.Add(2*time.Hour)),
+		prRow("github", `{"repo":"a/b","payload":{"action":"closed","number":2,"pull_request":{"number":2,"title":"Try y","state":"closed","merged":false}}}`, t0.Add(3*time.Hour)),
+		prRow("github", `{"repo":"a/b","payload":{"action":"closed","number":1,"pull_request":{"number":1,"title":"Add x and z","state":"closed","merged":true}}}`, t0.Add(4*time.Hour)),
+		// Same number on another forge is a different pull request
+		prRow("gitlab", `{"repo":"a/b","payload":{"action":"opened","number":1,"pull_request":{"number":1,"title":"MR","state":"open"}}}`, t0.Add(5*time.Hour)),
+		// Closed without its opening among the activities
+		prRow("github", `{"repo":"a/b","payload":{"action":"closed","number":3,"pull_request":{"number":3,"state":"closed","merged":true}}}`, t0.Add(6*time.Hour)),
+		prRow("github", `not json`, t0),
+	}
+
+	got := Fold(rows)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, Lifecycle{
+		Source: "github", Repo: "a/b", Number: 1, Title: "Add x and z",
+		State: StateMerged, StateAt: t0.Add(4 * time.Hour),
+		OpenedAt: t0, MergedAt: t0.Add(4 * time.Hour), ClosedAt: t0.Add(4 * time.Hour),
+	}, got[0])
+	assert.Equal(t, Lifecycle{
+		Source: "github", Repo: "a/b", Number: 2, Title: "Try y",
+		State: StateClosed, StateAt: t0.Add(3 * time.Hour),
+		OpenedAt: t0.Add(time.Hour), ClosedAt: t0.Add(3 * time.Hour),
+	}, got[1])
+	assert.Equal(t, "gitlab", got[2].Source)
+	assert.Equal(t, StateOpen, got[2].State)
+}
+
+func TestFold_Reopened(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	rows := []dbgen.ListPullRequestActivitiesRow{
+		prRow("github", `{"repo":"a/b","payload":{"action":"opened","number":7}}`, t0),
+		prRow("github", `{"repo":"a/b","payload":{"action":"closed","number":7,"pull_request":{"number":7,"merged":false}}}`, t0.Add(time.Hour)),
+		prRow("github", `{"repo":"a/b","payload":{"action":"reopened","number":7}}`, t0.Add(2*time.Hour)),
+	}
+
+	got := Fold(rows)
+	require.Len(t, got, 1)
+	assert.Equal(t, StateOpen, got[0].State)
+	assert.Equal(t, t0.Add(2*time.Hour), got[0].StateAt)
+	assert.True(t, got[0].ClosedAt.IsZero())
+}
+
+func TestLifecycle_UpsertParams(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	l := Lifecycle{Source: "github", Repo: "a/b", Number: 4, State: StateOpen, StateAt: t0, OpenedAt: t0}
+
+	p := l.UpsertParams(9)
+	assert.Equal(t, int64(9), p.UserID)
+	assert.Equal(t, int32(4), p.Number)
+	assert.True(t, p.OpenedAt.Valid)
+	assert.False(t, p.MergedAt.Valid)
+	assert.False(t, p.ClosedAt.Valid)
+	assert.False(t, p.FirstReviewAt.Valid)
+}
+
+func TestProjectArgs(t *testing.T) {
+	args := ProjectArgs{UserID: 1}
+	assert.Equal(t, "pull_request_project", args.Kind())
+
+	opts := args.InsertOpts().UniqueOpts
+	assert.True(t, opts.ByArgs)
+	assert.NotContains(t, opts.ByState, rivertype.JobStateCompleted)
+	// Jobs run no sooner than the window they're unique in
+	assert.Equal(t, projectDelay, opts.ByPeriod)
+	assert.WithinDuration(t, time.Now().Add(projectDelay), args.InsertOpts().ScheduledAt, time.Second)
+}
+
+func TestLastStored(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	rows := []dbgen.ListPullRequestActivitiesRow{
+		{CreatedAt: pgtype.Timestamptz{Time: t0.Add(time.Hour), Valid: true}},
+		{CreatedAt: pgtype.Timestamptz{Time: t0, Valid: true}},
+		{},
+	}
+	assert.Equal(t, t0.Add(time.Hour), lastStored(rows))
+	assert.True(t, lastStored(nil).IsZero())
+}
@@ -0,0 +1,103 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
+)
+
+// projectDelay is how long a projection waits before it runs, so a sync's
+// or webhook burst's requests share one job.
+const projectDelay = 10 * time.Second
+
+// projectOverlap is how far before the last projected activity the next
+// projection looks, so activities whose insert committed after a
+// projection read past their created_at aren't missed.
+const projectOverlap = time.Minute
+
+// ProjectArgs are the arguments for rebuilding a user's pull request
+// lifecycles from their activities.
+type ProjectArgs struct {
+	UserID int64 `json:"user_id"`
+}
+
+func (ProjectArgs) Kind() string { return "pull_request_project" }
+
+// InsertOpts dedupes against jobs that haven't finished yet, so each sync
+// and webhook can ask for a projection without piling up jobs.
+func (ProjectArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		ScheduledAt: time.Now().Add(projectDelay),
+		UniqueOpts:  riversetup.UniqueUnfinished(projectDelay),
+	}
+}
+
+// ProjectWorker runs Project for a user.
+type ProjectWorker struct {
+	riverlib.WorkerDefaults[ProjectArgs]
+	q *dbgen.Queries
+}
+
+func NewProjectWorker(q *dbgen.Queries) *ProjectWorker {
+	return &ProjectWorker{q: q}
+}
+
+func (w *ProjectWorker) Work(ctx context.Context, job *riverlib.Job[ProjectArgs]) error {
+	return Project(ctx, w.q, job.Args.UserID)
+}
+
+// Project folds the pull_request activities of the user's pull requests
+// that got new activities since the last projection and upserts the
+// resulting lifecycles. It is idempotent, and the upsert keeps anything a
+// provider looked up that the activities don't know about.
+func Project(ctx context.Context, q *dbgen.Queries, userID int64) error {
+	var since time.Time
+	until, err := q.GetPullRequestProjection(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("get projection: %w", err)
+	}
+	if until.Valid {
+		since = until.Time.Add(-projectOverlap)
+	}
+
+	rows, err := q.ListPullRequestActivities(ctx, dbgen.ListPullRequestActivitiesParams{
+		UserID:  userID,
+		Column2: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	for _, l := range Fold(rows) {
+		if err := q.UpsertPullRequest(ctx, l.UpsertParams(userID)); err != nil {
+			return fmt.Errorf("upsert pull request %s#%d: %w", l.Repo, l.Number, err)
+		}
+	}
+
+	latest := lastStored(rows)
+	if latest.IsZero() {
+		return nil
+	}
+	return q.UpsertPullRequestProjection(ctx, dbgen.UpsertPullRequestProjectionParams{
+		UserID:         userID,
+		ProjectedUntil: pgtype.Timestamptz{Time: latest, Valid: true},
+	})
+}
+
+// lastStored returns the newest created_at among rows.
+func lastStored(rows []dbgen.ListPullRequestActivitiesRow) time.Time {
+	var latest time.Time
+	for _, r := range rows {
+		if r.CreatedAt.Valid && r.CreatedAt.Time.After(latest) {
+			latest = r.CreatedAt.Time
+		}
+	}
+	return latest
+}
@@ -41,3 +41,9 @@ func NewClient(pool *pgxpool.Pool, workers *riverlib.Workers, periodicJobs []*ri
 		PeriodicJobs: periodicJobs,
 	})
 }
+
+// NewInsertOnlyClient creates a River client that can insert jobs but never
+// works them, for command-line tools that hand work to the API server.
+func NewInsertOnlyClient(pool *pgxpool.Pool) (*riverlib.Client[pgx.Tx], error) {
+	return riverlib.NewClient(riverpgxv5.New(pool), &riverlib.Config{})
+}
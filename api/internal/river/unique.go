@@ -0,0 +1,32 @@
+package river
+
+import (
+	"time"
+
+	riverlib "github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// UniqueUnfinished dedupes a job by args against jobs of the same kind that
+// haven't finished yet, so callers can ask for work without piling up jobs
+// while completed ones don't block the next request.
+//
+// River always counts running jobs as duplicates. A job that reads state
+// when it runs therefore passes a period: jobs are then only unique within
+// the window they were inserted in, and a request made while a job runs,
+// which may not see what prompted it, gets a job of its own. Scheduling the
+// job period after insertion makes it run once its window has passed. A
+// zero period dedupes against all unfinished jobs.
+func UniqueUnfinished(period time.Duration) riverlib.UniqueOpts {
+	return riverlib.UniqueOpts{
+		ByArgs:   true,
+		ByPeriod: period,
+		ByState: []rivertype.JobState{
+			rivertype.JobStateAvailable,
+			rivertype.JobStatePending,
+			rivertype.JobStateRetryable,
+			rivertype.JobStateRunning,
+			rivertype.JobStateScheduled,
+		},
+	}
+}
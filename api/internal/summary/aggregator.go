@@ -0,0 +1,231 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+// maxLanguages caps the languages stored per day.
+const maxLanguages = 10
+
+// LanguageSource returns the bytes of code per language in a repository
+// seen through the activity source. A nil map means the breakdown is
+// unknown, including for sources the LanguageSource doesn't cover.
+type LanguageSource interface {
+	RepoLanguages(ctx context.Context, userID int64, source, repo string) (map[string]int64, error)
+	// CachedRepoLanguages answers from what is already known, without
+	// calling out to a provider.
+	CachedRepoLanguages(ctx context.Context, source, repo string) (map[string]int64, error)
+}
+
+// Aggregator computes a user's daily summary from raw activities.
+type Aggregator struct {
+	q             *dbgen.Queries
+	excludeMerges bool
+	languages     LanguageSource
+}
+
+// NewAggregator creates an Aggregator. If excludeMerges is set, merge
+// commits are left out of total_commits. languages may be nil, in which
+// case top_languages stays empty.
+func NewAggregator(q *dbgen.Queries, excludeMerges bool, languages LanguageSource) *Aggregator {
+	return &Aggregator{q: q, excludeMerges: excludeMerges, languages: languages}
+}
+
+// Compute summarizes the activities that fall on the calendar day date
+// (YYYY-MM-DD) in loc without storing the result.
+func (a *Aggregator) Compute(ctx context.Context, userID int64, date string, loc *time.Location) (*SummaryResponse, error) {
+	return a.compute(ctx, userID, date, loc, false)
+}
+
+// ComputeCached is Compute with languages resolved from the cache only, so
+// it can run within a request without waiting on provider APIs.
+func (a *Aggregator) ComputeCached(ctx context.Context, userID int64, date string, loc *time.Location) (*SummaryResponse, error) {
+	return a.compute(ctx, userID, date, loc, true)
+}
+
+func (a *Aggregator) compute(ctx context.Context, userID int64, date string, loc *time.Location, cachedOnly bool) (*SummaryResponse, error) {
+	start, end, err := dayBounds(date, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := a.q.AggregateDailySummary(ctx, dbgen.AggregateDailySummaryParams{
+		UserID:  userID,
+		Column2: pgtype.Timestamptz{Time: start, Valid: true},
+		Column3: pgtype.Timestamptz{Time: end, Valid: true},
+		Column4: a.excludeMerges,
+		Column5: date,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repoRows, err := a.q.ListDailyRepoActivity(ctx, dbgen.ListDailyRepoActivityParams{
+		UserID:  userID,
+		Column2: pgtype.Timestamptz{Time: start, Valid: true},
+		Column3: pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	topRepos := make([]RepoCount, 0, len(repoRows))
+	for _, r := range repoRows {
+		topRepos = append(topRepos, RepoCount{Name: r.Name, Source: r.Source, Count: r.Count})
+	}
+
+	return &SummaryResponse{
+		Date:          date,
+		TotalCommits:  row.TotalCommits,
+		TotalPrs:      row.TotalPrs,
+		CodingMinutes: row.CodingMinutes,
+		LinesAdded:    row.LinesAdded,
+		LinesRemoved:  row.LinesRemoved,
+		TotalReviews:  row.TotalReviews,
+		TopRepos:      topRepos,
+		TopLanguages:  a.topLanguages(ctx, userID, topRepos, cachedOnly),
+	}, nil
+}
+
+// AggregateDay computes the summary for date in loc and upserts it.
+func (a *Aggregator) AggregateDay(ctx context.Context, userID int64, date string, loc *time.Location) error {
+	sum, err := a.Compute(ctx, userID, date, loc)
+	if err != nil {
+		return err
+	}
+
+	topRepos, _ := json.Marshal(sum.TopRepos)
+	topLanguages, _ := json.Marshal(sum.TopLanguages)
+	day, _ := time.Parse(time.DateOnly, date)
+	err = a.q.UpsertDailySummary(ctx, dbgen.UpsertDailySummaryParams{
+		UserID:        userID,
+		Date:          pgtype.Date{Time: day, Valid: true},
+		TotalCommits:  pgtype.Int4{Int32: sum.TotalCommits, Valid: true},
+		TotalPrs:      pgtype.Int4{Int32: sum.TotalPrs, Valid: true},
+		CodingMinutes: pgtype.Int4{Int32: sum.CodingMinutes, Valid: true},
+		TopRepos:      topRepos,
+		TopLanguages:  topLanguages,
+		LinesAdded:    pgtype.Int4{Int32: sum.LinesAdded, Valid: true},
+		LinesRemoved:  pgtype.Int4{Int32: sum.LinesRemoved, Valid: true},
+		TotalReviews:  pgtype.Int4{Int32: sum.TotalReviews, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("daily summary aggregated", "user_id", userID, "date", date, "timezone", loc.String())
+	return nil
+}
+
+// topLanguages resolves the languages of the day's repos. Repos whose
+// languages can't be resolved are left out rather than failing the day.
+func (a *Aggregator) topLanguages(ctx context.Context, userID int64, repos []RepoCount, cachedOnly bool) []LanguageShare {
+	if a.languages == nil {
+		return []LanguageShare{}
+	}
+
+	languages := make([]map[string]int64, len(repos))
+	for i, r := range repos {
+		var langs map[string]int64
+		var err error
+		if cachedOnly {
+			langs, err = a.languages.CachedRepoLanguages(ctx, r.Source, r.Name)
+		} else {
+			langs, err = a.languages.RepoLanguages(ctx, userID, r.Source, r.Name)
+		}
+		if err != nil {
+			slog.Warn("resolve repo languages failed", "source", r.Source, "repo", r.Name, "error", err)
+			continue
+		}
+		languages[i] = langs
+	}
+	return languageShares(repos, languages)
+}
+
+// dayBounds returns the [start, end) instants of the calendar day date in
+// loc. Days with a DST transition are 23 or 25 hours long.
+func dayBounds(date string, loc *time.Location) (time.Time, time.Time, error) {
+	d, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("parse date %q: %w", date, err)
+	}
+	start := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, loc)
+	return start, end, nil
+}
+
+// localYesterday returns the date before now's calendar date in loc.
+func localYesterday(now time.Time, loc *time.Location) string {
+	local := now.In(loc)
+	// Noon avoids landing in a DST gap at midnight.
+	return time.Date(local.Year(), local.Month(), local.Day()-1, 12, 0, 0, 0, loc).Format(time.DateOnly)
+}
+
+// LoadLocation resolves an IANA zone name, falling back to UTC for names
+// the server's zone database doesn't know.
+func LoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		slog.Warn("unknown timezone, using UTC", "timezone", name, "error", err)
+		return time.UTC
+	}
+	return loc
+}
+
+// languageShares splits each repo's activity count across its languages in
+// proportion to bytes of code, then ranks languages by the summed weight.
+// languages[i] holds the breakdown of repos[i]; it may be shorter.
+func languageShares(repos []RepoCount, languages []map[string]int64) []LanguageShare {
+	weights := make(map[string]float64)
+	var total float64
+	for i, r := range repos {
+		if i >= len(languages) {
+			break
+		}
+		var repoBytes int64
+		for _, n := range languages[i] {
+			repoBytes += n
+		}
+		if repoBytes == 0 {
+			continue
+		}
+		for lang, n := range languages[i] {
+			w := float64(r.Count) * float64(n) / float64(repoBytes)
+			weights[lang] += w
+			total += w
+		}
+	}
+
+	shares := make([]LanguageShare, 0, len(weights))
+	for lang, w := range weights {
+		shares = append(shares, LanguageShare{
+			Name:    lang,
+			Weight:  round(w, 2),
+			Percent: round(w/total*100, 1),
+		})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Weight != shares[j].Weight {
+			return shares[i].Weight > shares[j].Weight
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	if len(shares) > maxLanguages {
+		shares = shares[:maxLanguages]
+	}
+	return shares
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
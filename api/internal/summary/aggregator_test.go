@@ -0,0 +1,114 @@
+package summary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDayBounds(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		date      string
+		loc       *time.Location
+		wantStart time.Time
+		wantHours float64
+	}{
+		{
+			name:      "utc",
+			date:      "2026-03-10",
+			loc:       time.UTC,
+			wantStart: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			wantHours: 24,
+		},
+		{
+			name:      "ahead of utc",
+			date:      "2026-03-10",
+			loc:       shanghai,
+			wantStart: time.Date(2026, 3, 9, 16, 0, 0, 0, time.UTC),
+			wantHours: 24,
+		},
+		{
+			name:      "spring forward",
+			date:      "2026-03-08",
+			loc:       newYork,
+			wantStart: time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC),
+			wantHours: 23,
+		},
+		{
+			name:      "fall back",
+			date:      "2026-11-01",
+			loc:       newYork,
+			wantStart: time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC),
+			wantHours: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := dayBounds(tt.date, tt.loc)
+			require.NoError(t, err)
+			assert.True(t, start.Equal(tt.wantStart), "start = %s", start.UTC())
+			assert.Equal(t, tt.wantHours, end.Sub(start).Hours())
+		})
+	}
+}
+
+func TestDayBounds_InvalidDate(t *testing.T) {
+	_, _, err := dayBounds("03/10/2026", time.UTC)
+	assert.Error(t, err)
+}
+
+func TestLocalYesterday(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	// 2026-03-10 02:00 UTC is already the 10th in Shanghai but still the
+	// 9th in Los Angeles.
+	now := time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2026-03-09", localYesterday(now, time.UTC))
+	assert.Equal(t, "2026-03-09", localYesterday(now, shanghai))
+	assert.Equal(t, "2026-03-08", localYesterday(now, losAngeles))
+}
+
+func TestLoadLocation_FallsBackToUTC(t *testing.T) {
+	assert.Equal(t, time.UTC, LoadLocation("Not/AZone"))
+	assert.Equal(t, "Europe/Berlin", LoadLocation("Europe/Berlin").String())
+}
+
+func TestLanguageShares(t *testing.T) {
+	repos := []RepoCount{
+		{Name: "user/api", Count: 6},
+		{Name: "user/web", Count: 4},
+		{Name: "user/unknown", Count: 5},
+	}
+	languages := []map[string]int64{
+		{"Go": 900, "Shell": 100},
+		{"TypeScript": 3000, "CSS": 1000},
+		nil,
+	}
+
+	got := languageShares(repos, languages)
+
+	assert.Equal(t, []LanguageShare{
+		{Name: "Go", Weight: 5.4, Percent: 54},
+		{Name: "TypeScript", Weight: 3, Percent: 30},
+		{Name: "CSS", Weight: 1, Percent: 10},
+		{Name: "Shell", Weight: 0.6, Percent: 6},
+	}, got)
+}
+
+func TestLanguageShares_NoLanguages(t *testing.T) {
+	got := languageShares([]RepoCount{{Name: "user/repo", Count: 3}}, nil)
+	assert.NotNil(t, got)
+	assert.Empty(t, got)
+}
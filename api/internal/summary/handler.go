@@ -19,9 +19,11 @@ func NewHandler(svc *Service) *Handler {
 
 func (h *Handler) RegisterRoutes(g *echo.Group) {
 	g.GET("/summaries", h.List)
+	g.GET("/summaries/today", h.Today)
 	g.GET("/summaries/weekly", h.ListWeekly)
 	g.GET("/summaries/monthly", h.ListMonthly)
 	g.GET("/summaries/heatmap", h.Heatmap)
+	g.GET("/summaries/languages", h.Languages)
 }
 
 func (h *Handler) List(c *echo.Context) error {
@@ -40,6 +42,20 @@ func (h *Handler) List(c *echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+func (h *Handler) Today(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.Today(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
 func (h *Handler) ListWeekly(c *echo.Context) error {
 	userID, err := mw.GetUserID(c)
 	if err != nil {
@@ -87,3 +103,17 @@ func (h *Handler) Heatmap(c *echo.Context) error {
 
 	return c.JSON(http.StatusOK, resp)
 }
+
+func (h *Handler) Languages(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.Languages(c.Request().Context(), userID, c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
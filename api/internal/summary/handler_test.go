@@ -1,12 +1,16 @@
 package summary
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/labstack/echo/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
 )
 
 func TestListSummaries_MissingAuth(t *testing.T) {
@@ -20,6 +24,17 @@ func TestListSummaries_MissingAuth(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestToday_MissingAuth(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/summaries/today", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(nil)
+	err := h.Today(c)
+	assert.Error(t, err)
+}
+
 func TestListWeekly_MissingAuth(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/summaries/weekly", nil)
@@ -74,3 +89,36 @@ func TestCommitCountToLevel(t *testing.T) {
 		assert.Equal(t, tt.want, got, "commitCountToLevel(%d)", tt.count)
 	}
 }
+
+func TestLanguages_MissingAuth(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/summaries/languages", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(nil)
+	err := h.Languages(c)
+	assert.Error(t, err)
+}
+
+func TestLanguages_InvalidRange(t *testing.T) {
+	svc := NewService(nil, nil)
+
+	tests := []struct {
+		name     string
+		from, to string
+	}{
+		{name: "bad from", from: "yesterday"},
+		{name: "bad to", to: "2026-13-01"},
+		{name: "from after to", from: "2026-02-01", to: "2026-01-01"},
+		{name: "too long", from: "2024-01-01", to: "2026-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Languages(context.Background(), 1, tt.from, tt.to)
+			var appErr *apperror.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, http.StatusBadRequest, appErr.Code)
+		})
+	}
+}
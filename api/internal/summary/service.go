@@ -2,18 +2,47 @@ package summary
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
 	dbgen "github.com/ethanwang/devpulse/api/db/generated"
 	"github.com/ethanwang/devpulse/api/internal/apperror"
 )
 
 type SummaryResponse struct {
-	Date          string `json:"date"`
-	TotalCommits  int32  `json:"totalCommits"`
-	TotalPrs      int32  `json:"totalPrs"`
-	CodingMinutes int32  `json:"codingMinutes"`
+	Date          string          `json:"date"`
+	TotalCommits  int32           `json:"totalCommits"`
+	TotalPrs      int32           `json:"totalPrs"`
+	CodingMinutes int32           `json:"codingMinutes"`
+	LinesAdded    int32           `json:"linesAdded"`
+	LinesRemoved  int32           `json:"linesRemoved"`
+	TotalReviews  int32           `json:"totalReviews"`
+	TopRepos      []RepoCount     `json:"topRepos"`
+	TopLanguages  []LanguageShare `json:"topLanguages"`
+}
+
+// RepoCount is a repository's activity count for a day. It is also the
+// element format of daily_summaries.top_repos. Source is the activity
+// source the repository was seen through; summaries stored before it was
+// recorded lack it.
+type RepoCount struct {
+	Name   string `json:"name"`
+	Source string `json:"source,omitempty"`
+	Count  int32  `json:"count"`
+}
+
+// LanguageShare is a language's share of activity. Weight is the activity
+// count attributed to the language; it is also the element format of
+// daily_summaries.top_languages.
+type LanguageShare struct {
+	Name    string  `json:"name"`
+	Weight  float64 `json:"weight"`
+	Percent float64 `json:"percent"`
 }
 
 type ListSummariesResponse struct {
@@ -21,11 +50,40 @@ type ListSummariesResponse struct {
 }
 
 type Service struct {
-	q *dbgen.Queries
+	q          *dbgen.Queries
+	aggregator *Aggregator
+}
+
+func NewService(q *dbgen.Queries, aggregator *Aggregator) *Service {
+	return &Service{q: q, aggregator: aggregator}
+}
+
+// location returns the user's timezone.
+func (s *Service) location(ctx context.Context, userID int64) (*time.Location, error) {
+	tz, err := s.q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("user not found")
+		}
+		return nil, apperror.Internalf("get timezone: %w", err)
+	}
+	return LoadLocation(tz), nil
 }
 
-func NewService(q *dbgen.Queries) *Service {
-	return &Service{q: q}
+// Today computes the current day's summary in the user's timezone from raw
+// activities, since the stored row may lag behind the latest sync. Repo
+// languages come from the cache; re-aggregation resolves the rest.
+func (s *Service) Today(ctx context.Context, userID int64) (*SummaryResponse, error) {
+	loc, err := s.location(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sum, err := s.aggregator.ComputeCached(ctx, userID, time.Now().In(loc).Format(time.DateOnly), loc)
+	if err != nil {
+		return nil, apperror.Internalf("compute today: %w", err)
+	}
+	return sum, nil
 }
 
 func (s *Service) List(ctx context.Context, userID int64, days int) (*ListSummariesResponse, error) {
@@ -48,12 +106,102 @@ func (s *Service) List(ctx context.Context, userID int64, days int) (*ListSummar
 			TotalCommits:  r.TotalCommits.Int32,
 			TotalPrs:      r.TotalPrs.Int32,
 			CodingMinutes: r.CodingMinutes.Int32,
+			LinesAdded:    r.LinesAdded.Int32,
+			LinesRemoved:  r.LinesRemoved.Int32,
+			TotalReviews:  r.TotalReviews.Int32,
+			TopRepos:      decodeList[RepoCount](r.TopRepos),
+			TopLanguages:  decodeList[LanguageShare](r.TopLanguages),
 		})
 	}
 
 	return &ListSummariesResponse{Summaries: summaries}, nil
 }
 
+// decodeList decodes a jsonb array column, treating NULL or malformed
+// values as empty.
+func decodeList[T any](raw json.RawMessage) []T {
+	items := []T{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return []T{}
+		}
+	}
+	return items
+}
+
+// --- Languages ---
+
+type LanguagesResponse struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Languages []LanguageShare `json:"languages"`
+}
+
+// Languages sums the daily language weights between from and to (inclusive,
+// YYYY-MM-DD). Both default to a 30 day window ending today in the user's
+// timezone.
+func (s *Service) Languages(ctx context.Context, userID int64, from, to string) (*LanguagesResponse, error) {
+	var start, end time.Time
+	if to != "" {
+		t, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return nil, apperror.BadRequest("to must be a date in YYYY-MM-DD format")
+		}
+		end = t
+	}
+	if from != "" {
+		t, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return nil, apperror.BadRequest("from must be a date in YYYY-MM-DD format")
+		}
+		start = t
+	}
+	if end.IsZero() {
+		loc, err := s.location(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		end, _ = time.Parse(time.DateOnly, time.Now().In(loc).Format(time.DateOnly))
+	}
+	if start.IsZero() {
+		start = end.AddDate(0, 0, -29)
+	}
+	if start.After(end) {
+		return nil, apperror.BadRequest("from must not be after to")
+	}
+	if end.Sub(start) > 366*24*time.Hour {
+		return nil, apperror.BadRequest("range must not exceed 366 days")
+	}
+
+	rows, err := s.q.ListLanguageTotals(ctx, dbgen.ListLanguageTotalsParams{
+		UserID:  userID,
+		Column2: pgtype.Date{Time: start, Valid: true},
+		Column3: pgtype.Date{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, apperror.Internalf("list language totals: %w", err)
+	}
+
+	var total float64
+	for _, r := range rows {
+		total += r.Weight
+	}
+	languages := make([]LanguageShare, 0, len(rows))
+	for _, r := range rows {
+		share := LanguageShare{Name: r.Name, Weight: round(r.Weight, 2)}
+		if total > 0 {
+			share.Percent = round(r.Weight/total*100, 1)
+		}
+		languages = append(languages, share)
+	}
+
+	return &LanguagesResponse{
+		From:      start.Format(time.DateOnly),
+		To:        end.Format(time.DateOnly),
+		Languages: languages,
+	}, nil
+}
+
 // --- Period (weekly/monthly) summaries ---
 
 type PeriodSummary struct {
@@ -61,6 +209,9 @@ type PeriodSummary struct {
 	TotalCommits  int32  `json:"totalCommits"`
 	TotalPrs      int32  `json:"totalPrs"`
 	CodingMinutes int32  `json:"codingMinutes"`
+	LinesAdded    int32  `json:"linesAdded"`
+	LinesRemoved  int32  `json:"linesRemoved"`
+	TotalReviews  int32  `json:"totalReviews"`
 }
 
 type PeriodSummariesResponse struct {
@@ -88,6 +239,9 @@ func (s *Service) ListWeekly(ctx context.Context, userID int64, weeks int) (*Per
 			TotalCommits:  r.TotalCommits,
 			TotalPrs:      r.TotalPrs,
 			CodingMinutes: r.CodingMinutes,
+			LinesAdded:    r.LinesAdded,
+			LinesRemoved:  r.LinesRemoved,
+			TotalReviews:  r.TotalReviews,
 		})
 	}
 
@@ -114,6 +268,9 @@ func (s *Service) ListMonthly(ctx context.Context, userID int64, months int) (*P
 			TotalCommits:  r.TotalCommits,
 			TotalPrs:      r.TotalPrs,
 			CodingMinutes: r.CodingMinutes,
+			LinesAdded:    r.LinesAdded,
+			LinesRemoved:  r.LinesRemoved,
+			TotalReviews:  r.TotalReviews,
 		})
 	}
 
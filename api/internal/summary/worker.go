@@ -2,22 +2,29 @@ package summary
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sort"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5"
 	riverlib "github.com/riverqueue/river"
 
 	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	riversetup "github.com/ethanwang/devpulse/api/internal/river"
 )
 
-// AggregateArgs are the arguments for the daily aggregation job.
+// AggregateArgs are the arguments for the periodic aggregation job. It only
+// fans out one AggregateUserArgs job per active user for the day that most
+// recently ended in that user's timezone.
 type AggregateArgs struct{}
 
 func (AggregateArgs) Kind() string { return "daily_aggregate" }
 
-// AggregateWorker aggregates activities into daily summaries.
+// AggregateWorker enqueues per-user aggregation jobs. It runs hourly, so
+// each user is aggregated within an hour of their local midnight; the
+// unique (user, date) args keep later runs from repeating the work.
 type AggregateWorker struct {
 	riverlib.WorkerDefaults[AggregateArgs]
 	q *dbgen.Queries
@@ -28,46 +35,156 @@ func NewAggregateWorker(q *dbgen.Queries) *AggregateWorker {
 }
 
 func (w *AggregateWorker) Work(ctx context.Context, job *riverlib.Job[AggregateArgs]) error {
-	yesterday := time.Now().AddDate(0, 0, -1)
-	startOfDay := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, time.UTC)
-	endOfDay := startOfDay.AddDate(0, 0, 1)
-
-	users, err := w.q.ListDistinctActivityUsers(ctx)
+	users, err := w.q.ListActiveUserTimezones(ctx)
 	if err != nil {
 		return err
 	}
+	if len(users) == 0 {
+		return nil
+	}
 
-	for _, userID := range users {
-		if err := w.aggregateUser(ctx, userID, startOfDay, endOfDay); err != nil {
-			slog.Error("aggregation failed for user", "user_id", userID, "error", err)
-		}
+	now := time.Now()
+	params := make([]riverlib.InsertManyParams, 0, len(users))
+	for _, u := range users {
+		params = append(params, riverlib.InsertManyParams{Args: AggregateUserArgs{
+			UserID: u.ID,
+			Date:   localYesterday(now, LoadLocation(u.Timezone)),
+		}})
+	}
+
+	client := riverlib.ClientFromContext[pgx.Tx](ctx)
+	if _, err := client.InsertMany(ctx, params); err != nil {
+		return fmt.Errorf("enqueue user aggregations: %w", err)
 	}
 	return nil
 }
 
-func (w *AggregateWorker) aggregateUser(ctx context.Context, userID int64, start, end time.Time) error {
-	row, err := w.q.AggregateDailySummary(ctx, dbgen.AggregateDailySummaryParams{
-		UserID:  userID,
-		Column2: pgtype.Timestamptz{Time: start, Valid: true},
-		Column3: pgtype.Timestamptz{Time: end, Valid: true},
-	})
+// AggregateUserArgs are the arguments for aggregating one user's local day.
+type AggregateUserArgs struct {
+	UserID int64  `json:"user_id"`
+	Date   string `json:"date"`
+}
+
+func (AggregateUserArgs) Kind() string { return "daily_aggregate_user" }
+
+// InsertOpts makes the job unique per (user, date), including completed
+// jobs, so the hourly fan-out aggregates each day once.
+func (AggregateUserArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		UniqueOpts: riverlib.UniqueOpts{ByArgs: true},
+	}
+}
+
+// AggregateUserWorker aggregates one user's day in their timezone.
+type AggregateUserWorker struct {
+	riverlib.WorkerDefaults[AggregateUserArgs]
+	q          *dbgen.Queries
+	aggregator *Aggregator
+}
+
+func NewAggregateUserWorker(q *dbgen.Queries, aggregator *Aggregator) *AggregateUserWorker {
+	return &AggregateUserWorker{q: q, aggregator: aggregator}
+}
+
+func (w *AggregateUserWorker) Work(ctx context.Context, job *riverlib.Job[AggregateUserArgs]) error {
+	tz, err := w.q.GetUserTimezone(ctx, job.Args.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("user %d not found", job.Args.UserID))
+	}
 	if err != nil {
 		return err
 	}
 
-	err = w.q.UpsertDailySummary(ctx, dbgen.UpsertDailySummaryParams{
-		UserID:        userID,
-		Date:          pgtype.Date{Time: start, Valid: true},
-		TotalCommits:  pgtype.Int4{Int32: row.TotalCommits, Valid: true},
-		TotalPrs:      pgtype.Int4{Int32: row.TotalPrs, Valid: true},
-		CodingMinutes: pgtype.Int4{Int32: 0, Valid: true},
-		TopRepos:      json.RawMessage("[]"),
-		TopLanguages:  json.RawMessage("[]"),
-	})
+	if err := w.aggregator.AggregateDay(ctx, job.Args.UserID, job.Args.Date, LoadLocation(tz)); err != nil {
+		slog.Error("aggregation failed for user", "user_id", job.Args.UserID, "date", job.Args.Date, "error", err)
+		return err
+	}
+	return nil
+}
+
+// reaggregateDelay debounces re-aggregation: syncs and webhooks that touch
+// the same day shortly after each other share one scheduled job.
+const reaggregateDelay = 30 * time.Second
+
+// ReaggregateArgs are the arguments for re-aggregating a day after new
+// activities landed on it.
+type ReaggregateArgs struct {
+	UserID int64  `json:"user_id"`
+	Date   string `json:"date"`
+}
+
+func (ReaggregateArgs) Kind() string { return "daily_reaggregate" }
+
+// InsertOpts dedupes against jobs that haven't finished yet. Unlike
+// AggregateUserArgs, completed jobs don't block a new one, since the whole
+// point is to run again when the day changes.
+func (ReaggregateArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		UniqueOpts: riversetup.UniqueUnfinished(reaggregateDelay),
+	}
+}
+
+// ReaggregateWorker re-runs aggregation for one user's day.
+type ReaggregateWorker struct {
+	riverlib.WorkerDefaults[ReaggregateArgs]
+	q          *dbgen.Queries
+	aggregator *Aggregator
+}
+
+func NewReaggregateWorker(q *dbgen.Queries, aggregator *Aggregator) *ReaggregateWorker {
+	return &ReaggregateWorker{q: q, aggregator: aggregator}
+}
+
+func (w *ReaggregateWorker) Work(ctx context.Context, job *riverlib.Job[ReaggregateArgs]) error {
+	tz, err := w.q.GetUserTimezone(ctx, job.Args.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return riverlib.JobCancel(fmt.Errorf("user %d not found", job.Args.UserID))
+	}
 	if err != nil {
 		return err
 	}
+	return w.aggregator.AggregateDay(ctx, job.Args.UserID, job.Args.Date, LoadLocation(tz))
+}
 
-	slog.Info("daily summary aggregated", "user_id", userID, "date", start.Format("2006-01-02"))
+// Reaggregate enqueues a ReaggregateArgs job for each of the user's local
+// days that contains one of the given activity times. Sync workers call it
+// with the times of the activities they inserted or changed.
+func Reaggregate(ctx context.Context, q *dbgen.Queries, client *riverlib.Client[pgx.Tx], userID int64, occurredAt []time.Time) error {
+	if len(occurredAt) == 0 {
+		return nil
+	}
+
+	tz, err := q.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get timezone: %w", err)
+	}
+
+	params := make([]riverlib.InsertManyParams, 0)
+	for _, date := range localDates(occurredAt, LoadLocation(tz)) {
+		params = append(params, riverlib.InsertManyParams{
+			Args:       ReaggregateArgs{UserID: userID, Date: date},
+			InsertOpts: &riverlib.InsertOpts{ScheduledAt: time.Now().Add(reaggregateDelay)},
+		})
+	}
+
+	if _, err := client.InsertMany(ctx, params); err != nil {
+		return fmt.Errorf("enqueue reaggregation: %w", err)
+	}
 	return nil
 }
+
+// localDates returns the distinct calendar dates in loc of the given times,
+// in ascending order.
+func localDates(times []time.Time, loc *time.Location) []string {
+	seen := make(map[string]bool, len(times))
+	var dates []string
+	for _, t := range times {
+		d := t.In(loc).Format(time.DateOnly)
+		if !seen[d] {
+			seen[d] = true
+			dates = append(dates, d)
+		}
+	}
+	sort.Strings(dates)
+	return dates
+}
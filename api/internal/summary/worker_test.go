@@ -2,11 +2,46 @@ package summary
 
 import (
 	"testing"
+	"time"
 
+	"github.com/riverqueue/river/rivertype"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAggregateArgs_Kind(t *testing.T) {
 	args := AggregateArgs{}
 	assert.Equal(t, "daily_aggregate", args.Kind())
 }
+
+func TestAggregateUserArgs(t *testing.T) {
+	args := AggregateUserArgs{UserID: 1, Date: "2026-03-08"}
+	assert.Equal(t, "daily_aggregate_user", args.Kind())
+	assert.True(t, args.InsertOpts().UniqueOpts.ByArgs)
+}
+
+func TestReaggregateArgs(t *testing.T) {
+	args := ReaggregateArgs{UserID: 1, Date: "2026-03-08"}
+	assert.Equal(t, "daily_reaggregate", args.Kind())
+
+	opts := args.InsertOpts().UniqueOpts
+	assert.True(t, opts.ByArgs)
+	assert.NotContains(t, opts.ByState, rivertype.JobStateCompleted)
+	// Jobs run no sooner than the window they're unique in
+	assert.Equal(t, reaggregateDelay, opts.ByPeriod)
+}
+
+func TestLocalDates(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	times := []time.Time{
+		time.Date(2026, 3, 10, 16, 0, 0, 0, time.UTC), // 11th in Tokyo
+		time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC),  // 10th in Tokyo
+		time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC), // 10th in Tokyo
+	}
+
+	assert.Equal(t, []string{"2026-03-10", "2026-03-11"}, localDates(times, tokyo))
+	assert.Equal(t, []string{"2026-03-10"}, localDates(times, time.UTC))
+	assert.Empty(t, localDates(nil, time.UTC))
+}
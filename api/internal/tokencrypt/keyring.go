@@ -0,0 +1,148 @@
+package tokencrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks an encrypted value. The full layout is
+// "enc:<key id>:" followed by the AES-GCM nonce and ciphertext.
+const prefix = "enc:"
+
+// ErrUnknownKey is returned when a value was encrypted with a key id
+// that is not in the keyring.
+var ErrUnknownKey = errors.New("unknown encryption key id")
+
+// Keyring encrypts tokens with its primary key and decrypts values
+// written with any key it holds, so old keys can stay readable while
+// rows are re-encrypted.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring from raw AES keys (16, 24 or 32 bytes).
+func NewKeyring(primaryID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primaryID]; !ok {
+		return nil, fmt.Errorf("primary key %q not in keyring", primaryID)
+	}
+
+	k := &Keyring{primary: primaryID, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// ParseKeyring parses a comma-separated "id:base64key" list. If primaryID
+// is empty, the first key in the list is primary.
+func ParseKeyring(spec, primaryID string) (*Keyring, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("invalid key entry: want id:base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode key %q: %w", id, err)
+		}
+		if primaryID == "" {
+			primaryID = id
+		}
+		keys[id] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no encryption keys configured")
+	}
+	return NewKeyring(primaryID, keys)
+}
+
+// PrimaryID returns the id of the key used for new encryptions.
+func (k *Keyring) PrimaryID() string { return k.primary }
+
+// Encrypt seals plaintext with the primary key. Nil input stays nil so
+// optional columns (e.g. refresh_token) remain NULL.
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	if plaintext == nil {
+		return nil, nil
+	}
+	aead := k.keys[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	out := []byte(prefix + k.primary + ":")
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+// Decrypt opens a value produced by Encrypt. Values without the
+// encryption prefix predate encryption and are returned unchanged.
+func (k *Keyring) Decrypt(stored []byte) ([]byte, error) {
+	if stored == nil {
+		return nil, nil
+	}
+	id, sealed, ok := split(stored)
+	if !ok {
+		return stored, nil
+	}
+	aead, found := k.keys[id]
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt with key %q: %w", id, err)
+	}
+	return plaintext, nil
+}
+
+// NeedsRotation reports whether a stored value is plaintext or was
+// encrypted with a key other than the primary.
+func (k *Keyring) NeedsRotation(stored []byte) bool {
+	if stored == nil {
+		return false
+	}
+	id, _, ok := split(stored)
+	return !ok || id != k.primary
+}
+
+// split extracts the key id and sealed bytes from an encrypted value.
+func split(stored []byte) (id string, sealed []byte, ok bool) {
+	rest, found := bytes.CutPrefix(stored, []byte(prefix))
+	if !found {
+		return "", nil, false
+	}
+	idBytes, sealed, found := bytes.Cut(rest, []byte(":"))
+	if !found {
+		return "", nil, false
+	}
+	return string(idBytes), sealed, true
+}
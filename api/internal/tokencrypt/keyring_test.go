@@ -0,0 +1,125 @@
+package tokencrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	keyA = bytes.Repeat([]byte{0xa}, 32)
+	keyB = bytes.Repeat([]byte{0xb}, 32)
+)
+
+func TestEncryptDecrypt_RoundTrip(t *testing.T) {
+	k, err := NewKeyring("a", map[string][]byte{"a": keyA})
+	require.NoError(t, err)
+
+	sealed, err := k.Encrypt([]byte("gho_secret"))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(sealed, []byte("enc:a:")))
+	assert.NotContains(t, string(sealed), "gho_secret")
+
+	plain, err := k.Decrypt(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "gho_secret", string(plain))
+}
+
+func TestEncrypt_NilStaysNil(t *testing.T) {
+	k, err := NewKeyring("a", map[string][]byte{"a": keyA})
+	require.NoError(t, err)
+
+	sealed, err := k.Encrypt(nil)
+	require.NoError(t, err)
+	assert.Nil(t, sealed)
+
+	plain, err := k.Decrypt(nil)
+	require.NoError(t, err)
+	assert.Nil(t, plain)
+}
+
+func TestDecrypt_LegacyPlaintext(t *testing.T) {
+	k, err := NewKeyring("a", map[string][]byte{"a": keyA})
+	require.NoError(t, err)
+
+	plain, err := k.Decrypt([]byte("gho_legacy"))
+	require.NoError(t, err)
+	assert.Equal(t, "gho_legacy", string(plain))
+	assert.True(t, k.NeedsRotation([]byte("gho_legacy")))
+}
+
+func TestRotation_OldKeyStillDecrypts(t *testing.T) {
+	old, err := NewKeyring("a", map[string][]byte{"a": keyA})
+	require.NoError(t, err)
+	sealed, err := old.Encrypt([]byte("gho_secret"))
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring("b", map[string][]byte{"a": keyA, "b": keyB})
+	require.NoError(t, err)
+
+	assert.True(t, rotated.NeedsRotation(sealed))
+	plain, err := rotated.Decrypt(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "gho_secret", string(plain))
+
+	resealed, err := rotated.Encrypt(plain)
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsRotation(resealed))
+}
+
+func TestDecrypt_UnknownKey(t *testing.T) {
+	a, err := NewKeyring("a", map[string][]byte{"a": keyA})
+	require.NoError(t, err)
+	sealed, err := a.Encrypt([]byte("gho_secret"))
+	require.NoError(t, err)
+
+	b, err := NewKeyring("b", map[string][]byte{"b": keyB})
+	require.NoError(t, err)
+
+	_, err = b.Decrypt(sealed)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	k, err := NewKeyring("a", map[string][]byte{"a": keyA})
+	require.NoError(t, err)
+	sealed, err := k.Encrypt([]byte("gho_secret"))
+	require.NoError(t, err)
+
+	sealed[len(sealed)-1] ^= 0xff
+	_, err = k.Decrypt(sealed)
+	assert.Error(t, err)
+}
+
+func TestParseKeyring(t *testing.T) {
+	spec := "b:" + base64.StdEncoding.EncodeToString(keyB) + ", a:" + base64.StdEncoding.EncodeToString(keyA)
+
+	k, err := ParseKeyring(spec, "")
+	require.NoError(t, err)
+	assert.Equal(t, "b", k.PrimaryID())
+
+	k, err = ParseKeyring(spec, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", k.PrimaryID())
+
+	_, err = ParseKeyring(spec, "missing")
+	assert.Error(t, err)
+
+	_, err = ParseKeyring("", "")
+	assert.Error(t, err)
+
+	_, err = ParseKeyring("a:not-base64!", "")
+	assert.Error(t, err)
+
+	_, err = ParseKeyring("a:"+base64.StdEncoding.EncodeToString([]byte("short")), "")
+	assert.Error(t, err)
+}
+
+func TestRekeyArgs_Kind(t *testing.T) {
+	args := RekeyArgs{}
+	assert.Equal(t, "token_rekey", args.Kind())
+	assert.True(t, args.InsertOpts().UniqueOpts.ByArgs)
+}
@@ -0,0 +1,94 @@
+package tokencrypt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+)
+
+// RekeyArgs are the arguments for the token re-encryption job.
+// PrimaryKeyID makes the job unique per primary key, so it runs once
+// each time a new key is promoted.
+type RekeyArgs struct {
+	PrimaryKeyID string `json:"primary_key_id"`
+}
+
+func (RekeyArgs) Kind() string { return "token_rekey" }
+
+func (RekeyArgs) InsertOpts() riverlib.InsertOpts {
+	return riverlib.InsertOpts{
+		UniqueOpts: riverlib.UniqueOpts{ByArgs: true},
+	}
+}
+
+// RekeyWorker re-encrypts data source tokens that are still plaintext or
+// sealed with a non-primary key.
+type RekeyWorker struct {
+	riverlib.WorkerDefaults[RekeyArgs]
+	q       *dbgen.Queries
+	keyring *Keyring
+}
+
+func NewRekeyWorker(q *dbgen.Queries, keyring *Keyring) *RekeyWorker {
+	return &RekeyWorker{q: q, keyring: keyring}
+}
+
+func (w *RekeyWorker) Work(ctx context.Context, job *riverlib.Job[RekeyArgs]) error {
+	rows, err := w.q.ListDataSourceTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rotated int
+	for _, row := range rows {
+		if !w.keyring.NeedsRotation(row.AccessToken) && !w.keyring.NeedsRotation(row.RefreshToken) {
+			continue
+		}
+		ok, err := w.rekey(ctx, row)
+		if err != nil {
+			// Fail the job so it retries; rows already rotated are skipped next time.
+			return fmt.Errorf("rekey data source %d: %w", row.ID, err)
+		}
+		if ok {
+			rotated++
+		}
+	}
+
+	slog.Info("token rekey complete", "primary_key_id", w.keyring.PrimaryID(), "rows", len(rows), "rotated", rotated)
+	return nil
+}
+
+// rekey re-encrypts a row's tokens. It reports false if the tokens changed
+// since they were listed, e.g. by a sync refreshing them; the new tokens
+// are already sealed with the primary key.
+func (w *RekeyWorker) rekey(ctx context.Context, row dbgen.ListDataSourceTokensRow) (bool, error) {
+	access, err := w.reencrypt(row.AccessToken)
+	if err != nil {
+		return false, fmt.Errorf("access token: %w", err)
+	}
+	refresh, err := w.reencrypt(row.RefreshToken)
+	if err != nil {
+		return false, fmt.Errorf("refresh token: %w", err)
+	}
+
+	n, err := w.q.UpdateDataSourceTokens(ctx, dbgen.UpdateDataSourceTokensParams{
+		ID:             row.ID,
+		AccessToken:    access,
+		RefreshToken:   refresh,
+		AccessToken_2:  row.AccessToken,
+		RefreshToken_2: row.RefreshToken,
+	})
+	return n > 0, err
+}
+
+func (w *RekeyWorker) reencrypt(stored []byte) ([]byte, error) {
+	plaintext, err := w.keyring.Decrypt(stored)
+	if err != nil {
+		return nil, err
+	}
+	return w.keyring.Encrypt(plaintext)
+}
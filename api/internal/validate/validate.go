@@ -29,6 +29,8 @@ func formatFieldError(fe validator.FieldError) string {
 		return fmt.Sprintf("%s is required", fe.Field())
 	case "email":
 		return "invalid email format"
+	case "timezone":
+		return fmt.Sprintf("%s must be an IANA time zone name", fe.Field())
 	case "min":
 		return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
 	case "max":
@@ -0,0 +1,58 @@
+package wakatime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Client calls the WakaTime API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string // for testing; defaults to "https://wakatime.com/api/v1"
+}
+
+// NewClient creates a new WakaTime API client.
+// If httpClient is nil, a default http.Client is used.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &Client{httpClient: httpClient, baseURL: "https://wakatime.com/api/v1"}
+}
+
+// FetchSummaries fetches per-day coding summaries for the authenticated user
+// between start and end (inclusive, by date).
+func (c *Client) FetchSummaries(ctx context.Context, token string, start, end time.Time) ([]DaySummary, error) {
+	query := url.Values{
+		"start": {start.Format(time.DateOnly)},
+		"end":   {end.Format(time.DateOnly)},
+	}
+	reqURL := fmt.Sprintf("%s/users/current/summaries?%s", c.baseURL, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch summaries: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wakatime api returned %d", resp.StatusCode)
+	}
+
+	var body SummariesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode summaries: %w", err)
+	}
+
+	return body.Data, nil
+}
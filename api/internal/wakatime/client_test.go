@@ -0,0 +1,96 @@
+package wakatime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient creates a Client pointed at the given test server URL.
+func newTestClient(serverURL string) *Client {
+	c := NewClient(nil)
+	c.baseURL = serverURL
+	return c
+}
+
+func TestFetchSummaries_Success(t *testing.T) {
+	body := SummariesResponse{
+		Data: []DaySummary{
+			{
+				GrandTotal: GrandTotal{TotalSeconds: 5400},
+				Range: Range{
+					Date:     "2026-03-01",
+					Start:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+					End:      time.Date(2026, 3, 1, 23, 59, 59, 0, time.UTC),
+					Timezone: "UTC",
+				},
+				Projects:  []Item{{Name: "devpulse", TotalSeconds: 3600}, {Name: "dotfiles", TotalSeconds: 1800}},
+				Languages: []Item{{Name: "Go", TotalSeconds: 5400}},
+			},
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	result, err := client.FetchSummaries(context.Background(), "test-token", start, start)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "2026-03-01", result[0].Range.Date)
+	assert.Equal(t, 5400.0, result[0].GrandTotal.TotalSeconds)
+	require.Len(t, result[0].Projects, 2)
+	assert.Equal(t, "devpulse", result[0].Projects[0].Name)
+	assert.Equal(t, 3600.0, result[0].Projects[0].TotalSeconds)
+}
+
+func TestFetchSummaries_RequestParams(t *testing.T) {
+	var receivedAuth, receivedPath, receivedStart, receivedEnd string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = r.Header.Get("Authorization")
+		receivedPath = r.URL.Path
+		receivedStart = r.URL.Query().Get("start")
+		receivedEnd = r.URL.Query().Get("end")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SummariesResponse{})
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	_, err := client.FetchSummaries(context.Background(), "waka_my-secret-token", start, end)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer waka_my-secret-token", receivedAuth)
+	assert.Equal(t, "/users/current/summaries", receivedPath)
+	assert.Equal(t, "2026-03-01", receivedStart)
+	assert.Equal(t, "2026-03-07", receivedEnd)
+}
+
+func TestFetchSummaries_Non200(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	now := time.Now()
+	result, err := client.FetchSummaries(context.Background(), "bad-token", now, now)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "401")
+}
@@ -0,0 +1,37 @@
+package wakatime
+
+import "time"
+
+// SummariesResponse is the envelope returned by the Summaries API.
+// https://wakatime.com/developers#summaries
+type SummariesResponse struct {
+	Data []DaySummary `json:"data"`
+}
+
+// DaySummary is the coding activity for a single day.
+type DaySummary struct {
+	GrandTotal GrandTotal `json:"grand_total"`
+	Range      Range      `json:"range"`
+	Projects   []Item     `json:"projects"`
+	Languages  []Item     `json:"languages"`
+	Editors    []Item     `json:"editors"`
+}
+
+// GrandTotal is the total coding time across all projects for a day.
+type GrandTotal struct {
+	TotalSeconds float64 `json:"total_seconds"`
+}
+
+// Range describes the day a summary covers, in the user's WakaTime timezone.
+type Range struct {
+	Date     string    `json:"date"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Timezone string    `json:"timezone"`
+}
+
+// Item is a named bucket of coding time (project, language, editor).
+type Item struct {
+	Name         string  `json:"name"`
+	TotalSeconds float64 `json:"total_seconds"`
+}
@@ -0,0 +1,99 @@
+package wakatime
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+// ProviderName is the data source and activity source key for WakaTime.
+const ProviderName = "wakatime"
+
+// syncDays is how many days back each sync re-reads. WakaTime keeps
+// updating a day's totals as late heartbeats arrive, so recent days are
+// refreshed on every run rather than fetched once.
+const syncDays = 7
+
+// Provider syncs WakaTime coding time through the Summaries API.
+type Provider struct {
+	client *Client
+	oauth  provider.OAuthConfig
+}
+
+// NewProvider creates the WakaTime provider for the given OAuth app.
+// WakaTime doesn't support PKCE, so only the state protects its flow.
+func NewProvider(client *Client, creds provider.Credentials) *Provider {
+	return &Provider{
+		client: client,
+		oauth: provider.OAuthConfig{
+			Credentials:  creds,
+			AuthorizeURL: "https://wakatime.com/oauth/authorize",
+			TokenURL:     "https://wakatime.com/oauth/token",
+			Scopes:       []string{"read_summaries"},
+		},
+	}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+func (p *Provider) OAuthConfig() provider.OAuthConfig { return p.oauth }
+
+func (p *Provider) ExchangeCode(ctx context.Context, code, verifier string) (*provider.Token, error) {
+	return provider.ExchangeCode(ctx, p.oauth, code, verifier)
+}
+
+// Fetch reads the last syncDays of summaries. WakaTime has no change
+// cursor, so cursor is ignored.
+func (p *Provider) Fetch(ctx context.Context, token, cursor string) (*provider.FetchResult, error) {
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -(syncDays - 1))
+	days, err := p.client.FetchSummaries(ctx, token, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &provider.FetchResult{}
+	for _, day := range days {
+		result.Activities = append(result.Activities, dayActivities(day)...)
+	}
+	return result, nil
+}
+
+func (p *Provider) SupportedTypes() []string { return []string{"coding"} }
+
+// dayActivities turns a day summary into one "coding" activity per project.
+// The external ID is stable per (date, project) so later syncs update the
+// same row as the day's total grows. The day is in the WakaTime account's
+// timezone, so daily summaries count the minutes toward the payload's date
+// rather than the timestamp. The timestamp is noon UTC of that date, which
+// falls on the same date in all but the furthest-east timezones, so
+// re-aggregation picks the right day.
+func dayActivities(day DaySummary) []provider.Activity {
+	date, err := time.Parse(time.DateOnly, day.Range.Date)
+	if err != nil {
+		return nil
+	}
+	occurredAt := date.Add(12 * time.Hour)
+
+	var acts []provider.Activity
+	for _, p := range day.Projects {
+		if p.TotalSeconds <= 0 {
+			continue
+		}
+		payload, _ := json.Marshal(map[string]any{
+			"project": p.Name,
+			"seconds": p.TotalSeconds,
+			"date":    day.Range.Date,
+		})
+		acts = append(acts, provider.Activity{
+			Type:       "coding",
+			OccurredAt: occurredAt,
+			ExternalID: day.Range.Date + ":" + p.Name,
+			Payload:    payload,
+			Mutable:    true,
+		})
+	}
+	return acts
+}
@@ -0,0 +1,53 @@
+package wakatime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethanwang/devpulse/api/internal/provider"
+)
+
+func TestProvider(t *testing.T) {
+	p := NewProvider(nil, provider.Credentials{ClientID: "waka-client-id"})
+	assert.Equal(t, "wakatime", p.Name())
+	assert.Equal(t, []string{"coding"}, p.SupportedTypes())
+	assert.False(t, p.OAuthConfig().PKCE)
+}
+
+func TestDayActivities(t *testing.T) {
+	// Start is midnight in the WakaTime account's timezone
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	day := DaySummary{
+		Range: Range{Date: "2026-03-01", Start: start},
+		Projects: []Item{
+			{Name: "devpulse", TotalSeconds: 3600},
+			{Name: "idle", TotalSeconds: 0},
+		},
+	}
+
+	acts := dayActivities(day)
+
+	require.Len(t, acts, 1)
+	assert.Equal(t, "2026-03-01:devpulse", acts[0].ExternalID)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), acts[0].OccurredAt)
+	assert.True(t, acts[0].Mutable)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(acts[0].Payload, &payload))
+	assert.Equal(t, "devpulse", payload["project"])
+	assert.Equal(t, 3600.0, payload["seconds"])
+	assert.Equal(t, "2026-03-01", payload["date"])
+}
+
+func TestDayActivities_InvalidDate(t *testing.T) {
+	day := DaySummary{
+		Range:    Range{Start: time.Now()},
+		Projects: []Item{{Name: "devpulse", TotalSeconds: 60}},
+	}
+
+	assert.Empty(t, dayActivities(day))
+}
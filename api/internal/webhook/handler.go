@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/github"
+	mw "github.com/ethanwang/devpulse/api/internal/middleware"
+)
+
+// maxPayloadBytes matches GitHub's 25 MB cap on webhook payloads.
+const maxPayloadBytes = 25 << 20
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterPublicRoutes mounts the webhook receiver. Requests are
+// authenticated by their HMAC signature, not by JWT.
+func (h *Handler) RegisterPublicRoutes(api *echo.Group) {
+	api.POST("/webhooks/github", h.ReceiveGitHub)
+}
+
+// RegisterProtectedRoutes mounts webhook management routes.
+// The caller is responsible for applying JWT middleware to the group.
+func (h *Handler) RegisterProtectedRoutes(api *echo.Group) {
+	api.POST("/webhooks/github/secret", h.RotateGitHubSecret)
+}
+
+// RotateGitHubSecret issues a new webhook secret for the current user. The
+// optional provider parameter picks the GitHub deployment, github.com by
+// default.
+func (h *Handler) RotateGitHubSecret(c *echo.Context) error {
+	userID, err := mw.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.svc.RotateGitHubSecret(c.Request().Context(), userID, githubProvider(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ReceiveGitHub handles a GitHub webhook delivery.
+func (h *Handler) ReceiveGitHub(c *echo.Context) error {
+	userID, err := strconv.ParseInt(c.QueryParam("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		return apperror.BadRequest("missing user_id parameter")
+	}
+
+	signature := c.Request().Header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		return apperror.Unauthorized("missing webhook signature")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPayloadBytes))
+	if err != nil {
+		return apperror.BadRequest("invalid request body")
+	}
+
+	status, err := h.svc.ReceiveGitHub(
+		c.Request().Context(),
+		userID,
+		githubProvider(c),
+		c.Request().Header.Get("X-GitHub-Event"),
+		c.Request().Header.Get("X-GitHub-Delivery"),
+		signature,
+		body,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]string{"status": status})
+}
+
+// githubProvider returns the provider query parameter, defaulting to
+// github.com.
+func githubProvider(c *echo.Context) string {
+	if p := c.QueryParam("provider"); p != "" {
+		return p
+	}
+	return github.ProviderName
+}
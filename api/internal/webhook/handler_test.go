@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiveGitHub_MissingUserID(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/github", strings.NewReader(`{}`))
+	req.Header.Set("X-Hub-Signature-256", "sha256=00")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(nil)
+	err := h.ReceiveGitHub(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "user_id")
+}
+
+func TestReceiveGitHub_MissingSignature(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/github?user_id=1", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(nil)
+	err := h.ReceiveGitHub(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "signature")
+}
+
+func TestRotateGitHubSecret_MissingAuth(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/github/secret", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(nil)
+	err := h.RotateGitHubSecret(c)
+	assert.Error(t, err)
+}
+
+func TestReceiveGitHub_UnknownProvider(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/github?user_id=1&provider=gitlab", strings.NewReader(`{}`))
+	req.Header.Set("X-Hub-Signature-256", "sha256=00")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewHandler(NewService(nil, nil))
+	err := h.ReceiveGitHub(c)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown github provider")
+}
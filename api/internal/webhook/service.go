@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	riverlib "github.com/riverqueue/river"
+
+	dbgen "github.com/ethanwang/devpulse/api/db/generated"
+	"github.com/ethanwang/devpulse/api/internal/apperror"
+	"github.com/ethanwang/devpulse/api/internal/github"
+	"github.com/ethanwang/devpulse/api/internal/pullrequest"
+	"github.com/ethanwang/devpulse/api/internal/summary"
+)
+
+// SecretResponse tells the user how to configure the webhook on GitHub.
+type SecretResponse struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// Delivery outcomes reported back to GitHub.
+const (
+	StatusReceived  = "received"
+	StatusDuplicate = "duplicate"
+	StatusIgnored   = "ignored"
+)
+
+type Service struct {
+	q     *dbgen.Queries
+	river *riverlib.Client[pgx.Tx]
+}
+
+// NewService creates the webhook service. The River client is used to
+// re-aggregate the days that deliveries add activities to.
+func NewService(q *dbgen.Queries, river *riverlib.Client[pgx.Tx]) *Service {
+	return &Service{q: q, river: river}
+}
+
+// RotateGitHubSecret generates a new webhook secret for the user's data
+// source of the GitHub deployment named by source. Any previously
+// configured secret stops verifying.
+func (s *Service) RotateGitHubSecret(ctx context.Context, userID int64, source string) (*SecretResponse, error) {
+	if !github.IsProvider(source) {
+		return nil, apperror.BadRequest("unknown github provider")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, apperror.Internalf("generate webhook secret: %w", err)
+	}
+	secret := hex.EncodeToString(buf)
+
+	n, err := s.q.SetWebhookSecret(ctx, dbgen.SetWebhookSecretParams{
+		UserID:        userID,
+		Provider:      source,
+		WebhookSecret: pgtype.Text{String: secret, Valid: true},
+	})
+	if err != nil {
+		return nil, apperror.Internalf("save webhook secret: %w", err)
+	}
+	if n == 0 {
+		return nil, apperror.NotFound(source + " is not connected")
+	}
+
+	url := fmt.Sprintf("/api/webhooks/github?user_id=%d", userID)
+	if source != github.ProviderName {
+		url += "&provider=" + source
+	}
+	return &SecretResponse{URL: url, Secret: secret}, nil
+}
+
+// ReceiveGitHub verifies a webhook delivery from the GitHub deployment
+// named by source and records it as an activity. It returns the delivery
+// status: received, duplicate if the activity was already stored, or
+// ignored for unsupported events.
+func (s *Service) ReceiveGitHub(ctx context.Context, userID int64, source, eventName, deliveryID, signature string, body []byte) (string, error) {
+	if !github.IsProvider(source) {
+		return "", apperror.BadRequest("unknown github provider")
+	}
+
+	secret, err := s.q.GetWebhookSecret(ctx, dbgen.GetWebhookSecretParams{
+		UserID:   userID,
+		Provider: source,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", apperror.Internalf("get webhook secret: %w", err)
+	}
+	if !secret.Valid || !github.VerifySignature(secret.String, body, signature) {
+		return "", apperror.Unauthorized("invalid webhook signature")
+	}
+
+	evt, err := github.ParseWebhook(eventName, deliveryID, body)
+	if err != nil {
+		return "", apperror.BadRequest("invalid webhook payload")
+	}
+	if evt == nil {
+		return StatusIgnored, nil
+	}
+
+	n, err := s.q.InsertActivity(ctx, github.ActivityParams(userID, source, *evt))
+	if err != nil {
+		return "", apperror.Internalf("insert webhook activity: %w", err)
+	}
+	if n == 0 {
+		slog.Info("github webhook duplicate", "user_id", userID, "provider", source, "event", eventName, "delivery", deliveryID)
+		return StatusDuplicate, nil
+	}
+	if err := summary.Reaggregate(ctx, s.q, s.river, userID, []time.Time{evt.CreatedAt}); err != nil {
+		// The delivery itself succeeded; don't make GitHub redeliver it.
+		slog.Error("enqueue reaggregation failed", "user_id", userID, "error", err)
+	}
+	switch evt.Type {
+	case "PushEvent":
+		if _, err := s.river.Insert(ctx, github.EnrichCommitsArgs{UserID: userID, Provider: source}, nil); err != nil {
+			slog.Error("enqueue commit enrichment failed", "user_id", userID, "error", err)
+		}
+	case "PullRequestEvent":
+		if _, err := s.river.Insert(ctx, pullrequest.ProjectArgs{UserID: userID}, nil); err != nil {
+			slog.Error("enqueue pull request projection failed", "user_id", userID, "error", err)
+		}
+	case "PullRequestReviewEvent":
+		if _, err := s.river.Insert(ctx, github.EnrichReviewsArgs{UserID: userID, Provider: source}, nil); err != nil {
+			slog.Error("enqueue review enrichment failed", "user_id", userID, "error", err)
+		}
+	}
+
+	slog.Info("github webhook received", "user_id", userID, "provider", source, "event", eventName, "delivery", deliveryID)
+	return StatusReceived, nil
+}
@@ -57,6 +57,9 @@ export interface DailySummary {
   totalCommits: number;
   totalPrs: number;
   codingMinutes: number;
+  linesAdded: number;
+  linesRemoved: number;
+  totalReviews: number;
 }
 
 export interface SummaryListResponse {
@@ -68,6 +71,9 @@ export interface PeriodSummary {
   totalCommits: number;
   totalPrs: number;
   codingMinutes: number;
+  linesAdded: number;
+  linesRemoved: number;
+  totalReviews: number;
 }
 
 export interface PeriodSummariesResponse {
@@ -86,6 +92,7 @@ export interface HeatmapResponse {
 
 export interface RepoStats {
   name: string;
+  source: string;
   count: number;
   lastActive: string;
 }
@@ -94,6 +101,37 @@ export interface TopReposResponse {
   repos: RepoStats[];
 }
 
+export interface PullRequestWeek {
+  week: string;
+  opened: number;
+  merged: number;
+  closed: number;
+  mergeRate: number | null;
+  medianHoursToFirstReview: number | null;
+  medianHoursToMerge: number | null;
+}
+
+export interface PullRequestMetricsResponse {
+  weeks: PullRequestWeek[];
+}
+
+export interface NameCount {
+  name: string;
+  count: number;
+}
+
+export interface ReviewMetricsResponse {
+  from: string;
+  to: string;
+  total: number;
+  states: { approved: number; changesRequested: number; commented: number };
+  medianTurnaroundHours: number | null;
+  daily: { date: string; count: number }[];
+  weekly: { week: string; count: number }[];
+  topRepos: NameCount[];
+  topAuthors: NameCount[];
+}
+
 export interface DataSourceInfo {
   id: number;
   provider: string;
@@ -142,6 +180,12 @@ export const api = {
       `/api/activities/top-repos?days=${days}${source ? `&source=${source}` : ""}`
     ),
 
+  pullRequestMetrics: (weeks = 12) =>
+    request<PullRequestMetricsResponse>(`/api/metrics/pull-requests?weeks=${weeks}`),
+
+  reviewMetrics: (days = 30) =>
+    request<ReviewMetricsResponse>(`/api/metrics/reviews?days=${days}`),
+
   dataSources: () =>
     request<DataSourcesResponse>("/api/data-sources"),
 };